@@ -61,8 +61,12 @@ func (a *App) LoadConfig(explicit string) (*config.Config, string, error) {
 	return cfg, cfgPath, err
 }
 
-// ResolvePackageDir returns the effective package directory.
+// ResolvePackageDir returns the effective package directory. With multiple sources
+// configured, this is the highest-precedence source.
 func (a *App) ResolvePackageDir(cfg *config.Config) string {
+	if cfg != nil && len(cfg.Sources) > 0 {
+		return config.ResolvePackageDir(cfg)
+	}
 	if a != nil && a.Viper != nil {
 		if v := strings.TrimSpace(a.Viper.GetString("packageDir")); v != "" {
 			return v
@@ -3,9 +3,11 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
 
@@ -113,3 +115,89 @@ Hello`
 		t.Fatalf("expected transformed output")
 	}
 }
+
+func TestInstallAndPreviewApplyManifestOverrides(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	pkgDir := filepath.Join(packageDir, "frontend")
+	testutil.CreateTestFile(t, pkgDir, "component.mdc", "---\ndescription: \"Generate component\"\n---\nCreate a component.")
+	testutil.CreateTestManifest(t, pkgDir, `version: "1.0"
+targets:
+  - copilot-prompt
+overrides:
+  copilot-prompt:
+    defaultMode: agent
+    defaultTools: [githubRepo]
+    includeRefs: [docs/frontend.md]
+`)
+
+	a := New(nil, staticProvider{"copilot-prompt": transform.NewCopilotPromptsTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "copilot-prompt"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".github", "prompts", "component.prompt.md"))
+	if err != nil {
+		t.Fatalf("read installed prompt: %v", err)
+	}
+	installed := string(data)
+	for _, want := range []string{"mode: agent", "- githubRepo", "[docs/frontend.md](docs/frontend.md)"} {
+		if !strings.Contains(installed, want) {
+			t.Errorf("installed prompt missing %q:\n%s", want, installed)
+		}
+	}
+
+	resp, err := a.TransformPreview(TransformRequest{Name: "frontend", Target: "copilot-prompt"})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Output != installed {
+		t.Fatalf("preview should match installed output, got %+v", resp.Items)
+	}
+}
+
+func TestWindsurfInstallAllTargetsAndPreviewWarning(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	pkgDir := filepath.Join(packageDir, "frontend")
+	testutil.CreateTestFile(t, pkgDir, "react.mdc", "---\nglobs: web/**/*.tsx\n---\n"+strings.Repeat("Prefer function components.\n\n", 10))
+	testutil.CreateTestManifest(t, pkgDir, "version: \"1.0\"\ntargets:\n  - cursor\n  - windsurf\n")
+
+	a := New(nil, staticProvider{
+		"cursor":   transform.NewCursorTransformer(),
+		"windsurf": &transform.WindsurfRulesTransformer{MaxChars: 120},
+	})
+	resp, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, AllTargets: true})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[1].Target != "windsurf" {
+		t.Fatalf("unexpected results: %+v", resp.Results)
+	}
+	if len(resp.Results[0].Warnings) != 0 {
+		t.Fatalf("unexpected cursor warnings: %v", resp.Results[0].Warnings)
+	}
+	if w := resp.Results[1].Warnings; len(w) != 1 || !strings.HasPrefix(w[0], "react.md: ") || !strings.Contains(w[0], "120-character limit") {
+		t.Fatalf("unexpected windsurf warnings: %v", w)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".windsurf", "rules", "react.md"))
+	if err != nil {
+		t.Fatalf("read windsurf rule: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "---\nglobs: web/**/*.tsx\ntrigger: glob\n---\n") || len(data) > 120 {
+		t.Fatalf("unexpected windsurf rule (%d bytes):\n%s", len(data), data)
+	}
+
+	preview, err := a.TransformPreview(TransformRequest{Name: "frontend", Target: "windsurf", Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(preview.Items) != 1 || !strings.Contains(preview.Items[0].Warning, "120-character limit") || preview.Items[0].Output != string(data) {
+		t.Fatalf("unexpected preview: %+v", preview.Items)
+	}
+}
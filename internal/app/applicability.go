@@ -0,0 +1,119 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/manifest"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// projectFacts are the project characteristics applicability conditions are evaluated against.
+type projectFacts struct {
+	Root       string
+	Type       string
+	Languages  []string
+	Frameworks []string
+}
+
+func detectProjectFacts(projectRoot string) (projectFacts, error) {
+	profile, err := core.ProfileProject(projectRoot)
+	if err != nil {
+		return projectFacts{}, errors.Wrapf(err, errors.CodeInternal, "profile project")
+	}
+	return projectFacts{
+		Root:       projectRoot,
+		Type:       profile.Type,
+		Languages:  profile.Languages,
+		Frameworks: profile.Frameworks,
+	}, nil
+}
+
+// skipReason returns why when does not apply to the project, or "" when it does.
+func (f projectFacts) skipReason(when manifest.Conditions) string {
+	var reasons []string
+	if len(when.ProjectTypes) > 0 && !slices.Contains(when.ProjectTypes, f.Type) {
+		reasons = append(reasons, fmt.Sprintf("project type is %s, requires %s", f.Type, strings.Join(when.ProjectTypes, " or ")))
+	}
+	if len(when.Files) > 0 && !f.hasAnyFile(when.Files) {
+		reasons = append(reasons, fmt.Sprintf("none of %s found", strings.Join(when.Files, ", ")))
+	}
+	if reason := detectedReason("languages", f.Languages, when.Languages); reason != "" {
+		reasons = append(reasons, reason)
+	}
+	if reason := detectedReason("frameworks", f.Frameworks, when.Frameworks); reason != "" {
+		reasons = append(reasons, reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// detectedReason explains why none of the required values were detected, or returns "" when
+// required is empty or one of its values was detected.
+func detectedReason(what string, detected, required []string) string {
+	if len(required) == 0 || slices.ContainsFunc(required, func(v string) bool { return slices.Contains(detected, v) }) {
+		return ""
+	}
+	found := "none"
+	if len(detected) > 0 {
+		found = strings.Join(detected, ", ")
+	}
+	return fmt.Sprintf("%s %s detected, requires %s", what, found, strings.Join(required, " or "))
+}
+
+func (f projectFacts) hasAnyFile(patterns []string) bool {
+	for _, pattern := range patterns {
+		matches, err := glob.Glob(f.Root, pattern)
+		if err == nil && len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// presetConditions returns the applicability conditions of a rules package (from its manifest)
+// or single preset (from its `when` frontmatter).
+func presetConditions(packageDir, name string) (manifest.Conditions, error) {
+	if pkgPath, ok := packagePath(packageDir, name); ok {
+		m, err := loadPackageManifest(pkgPath, nil)
+		if err != nil || m == nil {
+			return manifest.Conditions{}, err
+		}
+		return m.When, nil
+	}
+	presetPath := filepath.Join(core.ResolveRulesPackageDir(packageDir), strings.TrimSuffix(name, ".mdc")+".mdc")
+	data, err := os.ReadFile(presetPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest.Conditions{}, nil
+		}
+		return manifest.Conditions{}, errors.Wrapf(err, errors.CodeInternal, "read %s", presetPath)
+	}
+	node, _, err := transform.SplitFrontmatter(data)
+	if err != nil {
+		// Presets without frontmatter declare no conditions.
+		return manifest.Conditions{}, nil
+	}
+	var fm struct {
+		When manifest.Conditions `yaml:"when"`
+	}
+	if err := node.Decode(&fm); err != nil {
+		return manifest.Conditions{}, errors.Wrapf(err, errors.CodeInvalidArgument, "parse when conditions in %s", presetPath)
+	}
+	return fm.When, nil
+}
+
+// presetSkipReason reports why the preset or package name does not apply to the project, or
+// "" when it applies.
+func presetSkipReason(packageDir, name string, facts projectFacts) (string, error) {
+	when, err := presetConditions(packageDir, name)
+	if err != nil || when.IsZero() {
+		return "", err
+	}
+	return facts.skipReason(when), nil
+}
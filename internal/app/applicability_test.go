@@ -0,0 +1,87 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// applicabilityPresets holds presets gated on the project type, languages and files; the
+// projects the tests install into are Go modules.
+var applicabilityPresets = map[string]string{
+	"golang.mdc":                          testutil.Rule("description: \"Go\"\nwhen:\n  projectTypes: [go]", "Go rules"),
+	"typescript.mdc":                      testutil.Rule("description: \"TS\"\nwhen:\n  languages: [typescript]", "TS rules"),
+	"always.mdc":                          testutil.Rule(`description: "Always"`, "Always"),
+	"frontend/react.mdc":                  testutil.Rule(`description: "React"`, "React"),
+	"frontend/cursor-rules-manifest.yaml": "when:\n  files: [package.json, \"web/*.json\"]\n",
+}
+
+func TestInstallAllSkipsPresetsThatDoNotApply(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, applicabilityPresets)
+	testutil.CreateTestFile(t, projectDir, "go.mod", "module example.com/app\n")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.InstallAll(&InstallAllRequest{Workdir: projectDir, Target: "cursor"})
+	if err != nil {
+		t.Fatalf("InstallAll failed: %v", err)
+	}
+	skipped := map[string]string{}
+	for _, skip := range resp.Skipped {
+		skipped[skip.Name] = skip.Reason
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("expected frontend and typescript to be skipped, got %+v", resp.Skipped)
+	}
+	if reason := skipped["frontend"]; reason != "none of package.json, web/*.json found" {
+		t.Errorf("unexpected frontend reason: %q", reason)
+	}
+	if reason := skipped["typescript"]; reason != "languages go detected, requires typescript" {
+		t.Errorf("unexpected typescript reason: %q", reason)
+	}
+	for _, rule := range []string{"golang.mdc", "always.mdc"} {
+		if _, err := os.Stat(filepath.Join(projectDir, ".cursor", "rules", rule)); err != nil {
+			t.Errorf("expected %s to be installed: %v", rule, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".cursor", "rules", "react.mdc")); !os.IsNotExist(err) {
+		t.Errorf("frontend package should not be installed: %v", err)
+	}
+
+	testutil.CreateTestFile(t, projectDir, filepath.Join("web", "package.json"), "{}")
+	resp, err = a.InstallAll(&InstallAllRequest{Workdir: projectDir, Target: "cursor"})
+	if err != nil {
+		t.Fatalf("InstallAll failed: %v", err)
+	}
+	if len(resp.Skipped) != 1 || resp.Skipped[0].Name != "typescript" {
+		t.Fatalf("frontend should apply once web/*.json exists, got %+v", resp.Skipped)
+	}
+}
+
+func TestSyncApplySkipsPresetsThatDoNotApply(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, applicabilityPresets)
+	testutil.CreateTestFile(t, projectDir, "go.mod", "module example.com/app\n")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.Sync(SyncRequest{Apply: true, DryRun: true, Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	var applied, skipped []string
+	for _, result := range resp.Applied {
+		if result.Skipped != "" {
+			skipped = append(skipped, result.Name+": "+result.Skipped)
+			continue
+		}
+		applied = append(applied, result.Name)
+	}
+	if strings.Join(applied, ",") != "always,golang" {
+		t.Errorf("applied = %v", applied)
+	}
+	if strings.Join(skipped, ",") != "typescript: languages go detected, requires typescript" {
+		t.Errorf("skipped = %v", skipped)
+	}
+}
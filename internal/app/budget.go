@@ -0,0 +1,95 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
+)
+
+// BudgetRequest describes a token budget report request.
+type BudgetRequest struct {
+	Workdir string
+	// Tokenizer names the estimator used to count tokens; empty selects the default.
+	Tokenizer string
+	// Limit is the token budget for all installed rules; zero reports without a limit.
+	Limit int
+}
+
+// BudgetRule is the token cost of one installed rule.
+type BudgetRule struct {
+	Name   string `json:"name" yaml:"name"`
+	Tokens int    `json:"tokens" yaml:"tokens"`
+	Bytes  int    `json:"bytes" yaml:"bytes"`
+}
+
+// BudgetTarget totals the rules installed for one target, largest first.
+type BudgetTarget struct {
+	Target    string       `json:"target" yaml:"target"`
+	SourceDir string       `json:"sourceDir" yaml:"sourceDir"`
+	Rules     []BudgetRule `json:"rules" yaml:"rules"`
+	Tokens    int          `json:"tokens" yaml:"tokens"`
+}
+
+// BudgetResponse reports the token cost of the rules installed in a project.
+type BudgetResponse struct {
+	Workdir     string         `json:"workdir" yaml:"workdir"`
+	Tokenizer   string         `json:"tokenizer" yaml:"tokenizer"`
+	Limit       int            `json:"limit,omitempty" yaml:"limit,omitempty"`
+	Targets     []BudgetTarget `json:"targets" yaml:"targets"`
+	TotalTokens int            `json:"totalTokens" yaml:"totalTokens"`
+}
+
+// OverLimit reports whether a limit was set and the installed rules exceed it.
+func (r *BudgetResponse) OverLimit() bool {
+	return r != nil && r.Limit > 0 && r.TotalTokens > r.Limit
+}
+
+// onDemandTargets install files an agent reads only when invoked, so they do not count
+// against the context budget.
+var onDemandTargets = []string{"copilot-prompt"}
+
+// Budget counts the tokens of every rule installed in the project, per target. Targets with
+// nothing installed are left out.
+func (a *App) Budget(req BudgetRequest) (*BudgetResponse, error) {
+	tokenizer, err := tokens.Lookup(req.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []string{"cursor"}
+	for _, target := range a.availableTargets() {
+		if target != "cursor" && !slices.Contains(onDemandTargets, target) {
+			targets = append(targets, target)
+		}
+	}
+
+	resp := &BudgetResponse{Workdir: wd, Tokenizer: tokenizer.Name(), Limit: req.Limit, Targets: []BudgetTarget{}}
+	for _, target := range targets {
+		effective, err := a.EffectiveRules(EffectiveRequest{Target: target, Workdir: wd})
+		if err != nil {
+			return nil, err
+		}
+		if len(effective.Rules) == 0 {
+			continue
+		}
+		entry := BudgetTarget{Target: target, SourceDir: effective.SourceDir}
+		if rel, relErr := filepath.Rel(wd, effective.SourceDir); relErr == nil {
+			entry.SourceDir = filepath.ToSlash(rel)
+		}
+		for _, rule := range effective.Rules {
+			count := tokenizer.Count(rule.Body)
+			entry.Rules = append(entry.Rules, BudgetRule{Name: rule.Name, Tokens: count, Bytes: len(rule.Body)})
+			entry.Tokens += count
+		}
+		sort.SliceStable(entry.Rules, func(i, j int) bool { return entry.Rules[i].Tokens > entry.Rules[j].Tokens })
+		resp.Targets = append(resp.Targets, entry)
+		resp.TotalTokens += entry.Tokens
+	}
+	return resp, nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestBudgetTotalsInstalledRulesPerTarget(t *testing.T) {
+	projectDir := testutil.CreateTestDir(t, map[string]string{
+		".cursor/rules/small.mdc":                  "---\ndescription: Small\n---\nUse gofmt.",
+		".cursor/rules/large.mdc":                  "---\ndescription: Large\n---\nAlways handle errors before you return. Prefer table-driven tests.",
+		".github/instructions/web.instructions.md": "---\ndescription: Web\napplyTo: \"**\"\n---\nWeb rules.",
+		".github/prompts/review.prompt.md":         "---\ndescription: Review\nmode: agent\n---\nReview the diff.",
+	})
+	a := New(nil, staticProvider{
+		"cursor":         transform.NewCursorTransformer(),
+		"copilot-instr":  transform.NewCopilotInstructionsTransformer(),
+		"copilot-prompt": transform.NewCopilotPromptsTransformer(),
+		"opencode-rules": transform.NewOpenCodeRulesTransformer(),
+	})
+
+	resp, err := a.Budget(BudgetRequest{Workdir: projectDir, Tokenizer: "chars", Limit: 10})
+	if err != nil {
+		t.Fatalf("Budget failed: %v", err)
+	}
+	if resp.Tokenizer != "chars" || len(resp.Targets) != 2 {
+		t.Fatalf("expected cursor and copilot-instr only, got %+v", resp)
+	}
+	cursor := resp.Targets[0]
+	if cursor.Target != "cursor" || cursor.SourceDir != ".cursor/rules" || len(cursor.Rules) != 2 || cursor.Rules[0].Name != "large.mdc" {
+		t.Fatalf("unexpected cursor entry %+v", cursor)
+	}
+	chars := tokens.CharEstimator{}
+	want := chars.Count("Use gofmt.") + chars.Count("Always handle errors before you return. Prefer table-driven tests.") + chars.Count("Web rules.")
+	if resp.TotalTokens != want || !resp.OverLimit() {
+		t.Fatalf("total = %d (over limit %v), want %d over the limit", resp.TotalTokens, resp.OverLimit(), want)
+	}
+
+	if _, err := a.Budget(BudgetRequest{Workdir: projectDir, Tokenizer: "unknown"}); err == nil {
+		t.Fatal("expected an error for an unknown tokenizer")
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/manifest"
+)
+
+// InstallPlanStep is one resource of a dependency-ordered install plan.
+type InstallPlanStep struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	// RequiredBy names the package that declared the dependency; empty for the requested resource.
+	RequiredBy string `json:"requiredBy,omitempty"`
+
+	packageDir string
+	source     string
+	bareName   string
+}
+
+// dependencyPlanner walks package manifests depth-first and orders every resource after its
+// dependencies.
+type dependencyPlanner struct {
+	app        *App
+	sources    []config.PackageSource
+	cfg        *config.Config
+	ruleTarget string
+
+	done  map[string]bool
+	stack []string // keys of the steps being visited, for cycle detection
+	names []string // display names matching stack
+	order []InstallPlanStep
+}
+
+// planInstall returns root preceded by its transitive dependencies in install order.
+func (a *App) planInstall(sources []config.PackageSource, cfg *config.Config, root InstallPlanStep) ([]InstallPlanStep, error) {
+	p := &dependencyPlanner{
+		app:        a,
+		sources:    sources,
+		cfg:        cfg,
+		ruleTarget: root.Target,
+		done:       make(map[string]bool),
+	}
+	if err := p.visit(root); err != nil {
+		return nil, err
+	}
+	return p.order, nil
+}
+
+func (p *dependencyPlanner) visit(step InstallPlanStep) error {
+	key := step.Kind + "\x00" + step.packageDir + "\x00" + step.bareName
+	if p.done[key] {
+		return nil
+	}
+	for i, visiting := range p.stack {
+		if visiting == key {
+			cycle := append(append([]string(nil), p.names[i:]...), step.Name)
+			return errors.Newf(errors.CodeInvalidArgument, "dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	p.stack = append(p.stack, key)
+	p.names = append(p.names, step.Name)
+
+	deps, err := p.dependenciesOf(step)
+	if err != nil {
+		return err
+	}
+	for _, dep := range deps {
+		if err := p.visit(dep); err != nil {
+			return err
+		}
+	}
+
+	p.stack = p.stack[:len(p.stack)-1]
+	p.names = p.names[:len(p.names)-1]
+	p.done[key] = true
+	p.order = append(p.order, step)
+	return nil
+}
+
+// dependenciesOf resolves the dependencies a package step declares in its manifest. Only
+// packages carry manifests; commands, skills, agents and single presets are leaves.
+func (p *dependencyPlanner) dependenciesOf(step InstallPlanStep) ([]InstallPlanStep, error) {
+	if step.Kind != resourceKindRule {
+		return nil, nil
+	}
+	pkgPath, ok := packagePath(step.packageDir, step.bareName)
+	if !ok {
+		return nil, nil
+	}
+	m, err := loadPackageManifest(pkgPath, p.app.resourceRegistry().targets())
+	if err != nil || m == nil {
+		return nil, err
+	}
+
+	var deps []InstallPlanStep
+	for _, group := range p.dependencyGroups(m.Dependencies) {
+		for _, ref := range group.refs {
+			dep, err := p.resolve(group.kind, group.target, strings.TrimSpace(ref), step.Name)
+			if err != nil {
+				return nil, err
+			}
+			deps = append(deps, dep)
+		}
+	}
+	return deps, nil
+}
+
+type dependencyGroup struct {
+	kind   string
+	target string
+	refs   []string
+}
+
+// dependencyGroups pairs each dependency list with the target it installs to. Packages follow
+// the requested rules target; other resources use the OpenCode or Claude Code providers when
+// rules go to that tool and the Cursor ones otherwise.
+func (p *dependencyPlanner) dependencyGroups(deps manifest.Dependencies) []dependencyGroup {
+	prefix := ""
+	for _, tool := range []string{"opencode-", "claude-"} {
+		if strings.HasPrefix(p.ruleTarget, tool) {
+			prefix = tool
+		}
+	}
+	return []dependencyGroup{
+		{kind: resourceKindRule, target: p.ruleTarget, refs: deps.Packages},
+		{kind: resourceKindCommand, target: prefix + "commands", refs: deps.Commands},
+		{kind: resourceKindSkill, target: prefix + "skills", refs: deps.Skills},
+		{kind: resourceKindAgent, target: prefix + "agents", refs: deps.Agents},
+	}
+}
+
+func (p *dependencyPlanner) resolve(kind, target, ref, requiredBy string) (InstallPlanStep, error) {
+	src, bare, err := p.app.resolveSource(p.sources, ref, target, p.cfg)
+	if err != nil {
+		return InstallPlanStep{}, err
+	}
+	if !p.app.sourceProvides(src.Path, bare, target, p.cfg) {
+		return InstallPlanStep{}, errors.Newf(errors.CodeNotFound, "%s %q required by %q not found in any package source", kind, ref, requiredBy)
+	}
+	return InstallPlanStep{
+		Kind:       kind,
+		Name:       ref,
+		Target:     target,
+		RequiredBy: requiredBy,
+		packageDir: src.Path,
+		source:     configuredSourceName(p.cfg, src),
+		bareName:   bare,
+	}, nil
+}
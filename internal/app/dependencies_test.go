@@ -0,0 +1,134 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// dependencyPackages is a package graph: frontend depends on shared, git and the review command,
+// and shared depends on git.
+var dependencyPackages = map[string]string{
+	"frontend/frontend-rule.mdc":          testutil.Rule(`description: "frontend"`, "frontend body"),
+	"frontend/cursor-rules-manifest.yaml": "dependencies:\n  packages: [shared, git]\n  commands: [review]\n",
+	"shared/shared-rule.mdc":              testutil.Rule(`description: "shared"`, "shared body"),
+	"shared/cursor-rules-manifest.yaml":   "dependencies:\n  packages: [git]\n",
+	"git/git-rule.mdc":                    testutil.Rule(`description: "git"`, "git body"),
+	"commands/review.md":                  "# review",
+}
+
+func TestInstallResolvesManifestDependencies(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, dependencyPackages)
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	var plan []string
+	for _, step := range resp.Plan {
+		plan = append(plan, step.Kind+":"+step.Name+"<"+step.RequiredBy)
+	}
+	want := []string{"rule:git<shared", "rule:shared<frontend", "command:review<frontend", "rule:frontend<"}
+	if strings.Join(plan, ",") != strings.Join(want, ",") {
+		t.Fatalf("plan = %v, want %v", plan, want)
+	}
+	for _, rule := range []string{"git-rule.mdc", "shared-rule.mdc", "frontend-rule.mdc"} {
+		if _, err := os.Stat(filepath.Join(projectDir, ".cursor", "rules", rule)); err != nil {
+			t.Errorf("expected %s to be installed: %v", rule, err)
+		}
+	}
+	if len(resp.Results) != 4 || resp.Results[2].Target != "commands" {
+		t.Fatalf("unexpected results: %+v", resp.Results)
+	}
+}
+
+func TestInstallDependencyOptions(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, dependencyPackages)
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(resp.Plan) != 4 || len(resp.Results) != 0 {
+		t.Fatalf("dry run should plan without installing, got %+v", resp)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".cursor")); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote to the project: %v", err)
+	}
+
+	resp, err = a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor", NoDeps: true})
+	if err != nil {
+		t.Fatalf("install without deps failed: %v", err)
+	}
+	if len(resp.Plan) != 1 || len(resp.Results) != 1 {
+		t.Fatalf("--no-deps should install only the package, got %+v", resp)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".cursor", "rules", "shared-rule.mdc")); !os.IsNotExist(err) {
+		t.Fatalf("dependency installed despite NoDeps: %v", err)
+	}
+}
+
+func TestInstallRejectsDependencyCyclesAndMissingDependencies(t *testing.T) {
+	packageDir, projectDir := testutil.SetupPackageProject(t, dependencyPackages)
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	testutil.CreateTestManifest(t, filepath.Join(packageDir, "git"), `dependencies:
+  packages: [frontend]
+`)
+	_, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"})
+	if apperrors.CodeOf(err) != apperrors.CodeInvalidArgument || !strings.Contains(err.Error(), "dependency cycle: frontend -> shared -> git -> frontend") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	testutil.CreateTestManifest(t, filepath.Join(packageDir, "git"), `dependencies:
+  agents: [reviewer]
+`)
+	_, err = a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"})
+	if apperrors.CodeOf(err) != apperrors.CodeNotFound || !strings.Contains(err.Error(), `agent "reviewer" required by "git"`) {
+		t.Fatalf("expected missing dependency error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(projectDir, ".cursor")); !os.IsNotExist(statErr) {
+		t.Fatalf("nothing should be installed when planning fails: %v", statErr)
+	}
+}
+
+func TestInstallClaudeRulesUsesClaudeDependencyTargets(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, dependencyPackages)
+	a := New(nil, staticProvider{
+		"cursor":       transform.NewCursorTransformer(),
+		"claude-rules": transform.NewClaudeRulesTransformer(),
+	})
+
+	resp, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "claude-rules"})
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if len(resp.Results) != 4 || resp.Results[2].Target != "claude-commands" {
+		t.Fatalf("unexpected results: %+v", resp.Results)
+	}
+	for _, path := range []string{
+		filepath.Join(".claude", "rules", "frontend-rule.md"),
+		filepath.Join(".claude", "rules", "git-rule.md"),
+		filepath.Join(".claude", "commands", "review.md"),
+	} {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err != nil {
+			t.Errorf("expected %s to be installed: %v", path, err)
+		}
+	}
+
+	removed, err := a.Remove(RemoveRequest{Name: "review", Target: "claude-commands", Workdir: projectDir})
+	if err != nil || len(removed.Matches) != 1 || !removed.Matches[0].Removed {
+		t.Fatalf("Remove claude-commands = %+v, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".claude", "commands", "review.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected review.md removed, got %v", err)
+	}
+}
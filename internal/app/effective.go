@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
 
 // EffectiveRequest describes an effective rules request.
@@ -17,21 +19,16 @@ type EffectiveRequest struct {
 	Workdir string
 }
 
-// EffectiveFile is a single effective rules file entry.
-type EffectiveFile struct {
-	Name    string
-	Content string
-}
-
 // EffectiveResponse captures effective rules output.
 type EffectiveResponse struct {
-	Target        string
-	SourceDir     string
-	CursorContent string
-	Files         []EffectiveFile
-	Missing       bool
-	MissingReason string
-	Extension     string
+	Target        string               `json:"target" yaml:"target"`
+	SourceDir     string               `json:"sourceDir" yaml:"sourceDir"`
+	Rules         []core.EffectiveRule `json:"rules" yaml:"rules"`
+	Missing       bool                 `json:"missing,omitempty" yaml:"missing,omitempty"`
+	MissingReason string               `json:"missingReason,omitempty" yaml:"missingReason,omitempty"`
+	Extension     string               `json:"extension,omitempty" yaml:"extension,omitempty"`
+	// TotalTokens is the estimated token cost of all rule bodies.
+	TotalTokens int `json:"totalTokens" yaml:"totalTokens"`
 }
 
 // EffectiveRules returns effective rules for a target.
@@ -42,15 +39,17 @@ func (a *App) EffectiveRules(req EffectiveRequest) (*EffectiveResponse, error) {
 	}
 
 	if req.Target == "cursor" {
-		out, err := core.EffectiveRules(wd)
+		rulesDir := filepath.Join(wd, ".cursor", "rules")
+		rules, err := core.LoadEffectiveRules(rulesDir, ".md", ".mdc")
 		if err != nil {
 			return nil, err
 		}
-		return &EffectiveResponse{
-			Target:        "cursor",
-			SourceDir:     filepath.Join(wd, ".cursor", "rules"),
-			CursorContent: out,
-		}, nil
+		for i := range rules {
+			if err := core.ResolveReferences(&rules[i], wd); err != nil {
+				return nil, err
+			}
+		}
+		return newEffectiveResponse("cursor", rulesDir, "", rules), nil
 	}
 
 	transformer, err := a.transformer(req.Target)
@@ -58,46 +57,159 @@ func (a *App) EffectiveRules(req EffectiveRequest) (*EffectiveResponse, error) {
 		return nil, err
 	}
 
-	rulesDir := filepath.Join(wd, transformer.OutputDir())
-	resp := &EffectiveResponse{
-		Target:    transformer.Target(),
-		SourceDir: rulesDir,
-		Extension: transformer.Extension(),
+	if composer, ok := transformer.(transform.Composer); ok {
+		return composedEffectiveRules(wd, transformer.Target(), composer.ComposedFile())
 	}
 
+	rulesDir := filepath.Join(wd, transformer.OutputDir())
 	if _, err := os.Stat(rulesDir); os.IsNotExist(err) {
+		resp := newEffectiveResponse(transformer.Target(), rulesDir, transformer.Extension(), nil)
 		resp.Missing = true
 		resp.MissingReason = fmt.Sprintf("No rules found in %s", rulesDir)
 		return resp, nil
 	}
 
-	var files []string
-	if err := filepath.Walk(rulesDir, func(path string, info os.FileInfo, err error) error {
-		if err == nil && !info.IsDir() && strings.HasSuffix(path, transformer.Extension()) {
-			files = append(files, path)
-		}
-		return nil
-	}); err != nil {
-		return nil, errors.Wrapf(err, errors.CodeInternal, "walk rules directory")
+	rules, err := core.LoadEffectiveRules(rulesDir, transformer.Extension())
+	if err != nil {
+		return nil, err
 	}
-
-	if len(files) == 0 {
+	resp := newEffectiveResponse(transformer.Target(), rulesDir, transformer.Extension(), rules)
+	if len(rules) == 0 {
 		resp.Missing = true
 		resp.MissingReason = fmt.Sprintf("No %s files found in %s", transformer.Extension(), rulesDir)
-		return resp, nil
 	}
+	return resp, nil
+}
 
-	sort.Strings(files)
-	for _, file := range files {
-		data, err := os.ReadFile(file)
+// composedEffectiveRules reads every composed file (e.g. AGENTS.md) in the project as one rule
+// each, named by its project-relative path; agents read hand-written text as well as the
+// installed sections.
+func composedEffectiveRules(wd, target, fileName string) (*EffectiveResponse, error) {
+	files, err := glob.Glob(wd, "**/"+fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "find %s files", fileName)
+	}
+	rules := make([]core.EffectiveRule, 0, len(files))
+	for _, rel := range files {
+		rule, err := core.ReadEffectiveRule(filepath.Join(wd, filepath.FromSlash(rel)))
 		if err != nil {
-			continue
+			return nil, err
 		}
-		resp.Files = append(resp.Files, EffectiveFile{
-			Name:    filepath.Base(file),
-			Content: string(data),
-		})
+		rule.Name = rel
+		rules = append(rules, rule)
+	}
+	resp := newEffectiveResponse(target, wd, "", rules)
+	if len(rules) == 0 {
+		resp.Missing = true
+		resp.MissingReason = fmt.Sprintf("No %s files found in %s", fileName, wd)
+	}
+	return resp, nil
+}
+
+func newEffectiveResponse(target, rulesDir, extension string, rules []core.EffectiveRule) *EffectiveResponse {
+	if rules == nil {
+		rules = []core.EffectiveRule{}
 	}
+	resp := &EffectiveResponse{
+		Target:    target,
+		SourceDir: rulesDir,
+		Rules:     rules,
+		Extension: extension,
+	}
+	for _, rule := range rules {
+		resp.TotalTokens += rule.Tokens
+	}
+	return resp
+}
+
+// EffectiveForRequest asks which installed rules apply when editing a file.
+type EffectiveForRequest struct {
+	Workdir string
+	// Path is the file being edited, absolute or relative to the project root.
+	Path string
+	// Target limits the evaluation to one of the glob-aware targets; empty evaluates all.
+	Target string
+}
 
+// EffectiveMatch is an installed rule that applies to the requested file.
+type EffectiveMatch struct {
+	Target string             `json:"target" yaml:"target"`
+	Rule   core.EffectiveRule `json:"rule" yaml:"rule"`
+	// Reason names the frontmatter setting that matched, e.g. "globs: web/**/*.tsx".
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// EffectiveForResponse lists the rules that apply to one file across rule targets.
+type EffectiveForResponse struct {
+	Path        string           `json:"path" yaml:"path"`
+	Matches     []EffectiveMatch `json:"matches" yaml:"matches"`
+	TotalTokens int              `json:"totalTokens" yaml:"totalTokens"`
+}
+
+// effectiveForTargets are the targets whose rules attach to files by glob.
+var effectiveForTargets = []string{"cursor", "copilot-instr", "opencode-rules", "claude-rules", "windsurf", "cline-rules", "roo-rules"}
+
+// EffectiveFor evaluates alwaysApply, globs, apply_to, applyTo, paths and triggers of every rule
+// installed for the cursor, copilot-instr, opencode-rules, claude-rules, windsurf, cline-rules
+// and roo-rules targets against req.Path.
+func (a *App) EffectiveFor(req EffectiveForRequest) (*EffectiveForResponse, error) {
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := projectRelativePath(wd, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := effectiveForTargets
+	if req.Target != "" {
+		if !slices.Contains(effectiveForTargets, req.Target) {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "target %s does not attach rules to files (use %s)", req.Target, strings.Join(effectiveForTargets, ", "))
+		}
+		targets = []string{req.Target}
+	}
+
+	resp := &EffectiveForResponse{Path: rel, Matches: []EffectiveMatch{}}
+	for _, target := range targets {
+		if target != "cursor" && !slices.Contains(a.availableTargets(), target) {
+			continue
+		}
+		rules, err := a.EffectiveRules(EffectiveRequest{Target: target, Workdir: wd})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules.Rules {
+			reason, ok := core.RuleApplies(target, rule, rel)
+			if !ok {
+				continue
+			}
+			resp.Matches = append(resp.Matches, EffectiveMatch{Target: target, Rule: rule, Reason: reason})
+			resp.TotalTokens += rule.Tokens
+		}
+	}
 	return resp, nil
 }
+
+func (a *App) availableTargets() []string {
+	if a == nil || a.Transformers == nil {
+		return nil
+	}
+	return a.Transformers.AvailableTargets()
+}
+
+// projectRelativePath returns p relative to projectRoot with forward slashes.
+func projectRelativePath(projectRoot, p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return "", errors.New(errors.CodeInvalidArgument, "missing path")
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(projectRoot, p)
+	}
+	rel, err := filepath.Rel(projectRoot, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Newf(errors.CodeInvalidArgument, "path %s is not inside the project %s", p, projectRoot)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestEffectiveForMatchesAcrossTargets(t *testing.T) {
+	projectDir := testutil.CreateTestDir(t, map[string]string{
+		".cursor/rules/always.mdc":                 "---\ndescription: Always\nalwaysApply: true\n---\nAlways.",
+		".cursor/rules/react.mdc":                  "---\ndescription: React\nglobs: \"web/**/*.tsx\"\n---\nUse hooks.",
+		".cursor/rules/golang.mdc":                 "---\ndescription: Go\nglobs: \"**/*.go\"\n---\nUse gofmt.",
+		".github/instructions/web.instructions.md": "---\ndescription: Web\napplyTo: \"**/*.ts,**/*.tsx\"\n---\nWeb.",
+		".github/instructions/py.instructions.md":  "---\ndescription: Py\napplyTo: \"**/*.py\"\n---\nPy.",
+		".opencode/rules/general.mdc":              "---\n{}\n---\nGeneral.",
+	})
+	a := New(nil, staticProvider{
+		"cursor":         transform.NewCursorTransformer(),
+		"copilot-instr":  transform.NewCopilotInstructionsTransformer(),
+		"opencode-rules": transform.NewOpenCodeRulesTransformer(),
+	})
+
+	resp, err := a.EffectiveFor(EffectiveForRequest{Workdir: projectDir, Path: filepath.Join(projectDir, "web", "src", "app.tsx")})
+	if err != nil {
+		t.Fatalf("EffectiveFor failed: %v", err)
+	}
+	if resp.Path != "web/src/app.tsx" {
+		t.Fatalf("path = %q, want web/src/app.tsx", resp.Path)
+	}
+	var got []string
+	for _, match := range resp.Matches {
+		got = append(got, match.Target+" "+match.Rule.Name+" "+match.Reason)
+	}
+	want := []string{
+		"cursor always.mdc alwaysApply: true",
+		"cursor react.mdc globs: web/**/*.tsx",
+		"copilot-instr web.instructions.md applyTo: **/*.tsx",
+		"opencode-rules general.mdc no globs or keywords (always applies)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("matches:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	resp, err = a.EffectiveFor(EffectiveForRequest{Workdir: projectDir, Path: "cmd/main.go", Target: "cursor"})
+	if err != nil {
+		t.Fatalf("EffectiveFor failed: %v", err)
+	}
+	if len(resp.Matches) != 2 || resp.Matches[1].Rule.Name != "golang.mdc" {
+		t.Fatalf("unexpected matches for cmd/main.go: %+v", resp.Matches)
+	}
+
+	if _, err := a.EffectiveFor(EffectiveForRequest{Workdir: projectDir, Path: "../elsewhere.go"}); err == nil {
+		t.Fatal("expected an error for a path outside the project")
+	}
+	if _, err := a.EffectiveFor(EffectiveForRequest{Workdir: projectDir, Path: "main.go", Target: "copilot-prompt"}); err == nil {
+		t.Fatal("expected an error for a target that does not attach rules to files")
+	}
+}
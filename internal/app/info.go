@@ -7,12 +7,15 @@ import (
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 )
 
 // InfoRequest describes an info request.
 type InfoRequest struct {
 	ConfigPath string
 	Workdir    string
+	// Profile also profiles the workspace project (languages, frameworks, workspaces, ...).
+	Profile bool
 }
 
 // InfoResponse captures info data for rendering.
@@ -27,6 +30,7 @@ type InfoResponse struct {
 	Workdir      string
 	Presets      []string
 	Commands     []string
+	Profile      *core.ProjectProfile
 }
 
 // Info returns diagnostics for configuration and workspace.
@@ -65,6 +69,14 @@ func (a *App) Info(req InfoRequest) (*InfoResponse, error) {
 	}
 	sort.Strings(customCmds)
 
+	var profile *core.ProjectProfile
+	if req.Profile {
+		profile, err = core.ProfileProject(wd)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "profile project")
+		}
+	}
+
 	return &InfoResponse{
 		ConfigPath:   cfgPath,
 		ConfigDir:    a.ResolveConfigDir(req.ConfigPath),
@@ -76,6 +88,7 @@ func (a *App) Info(req InfoRequest) (*InfoResponse, error) {
 		Workdir:      wd,
 		Presets:      presets,
 		Commands:     customCmds,
+		Profile:      profile,
 	}, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/manifest"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
@@ -24,6 +25,12 @@ type InstallRequest struct {
 	Target            string
 	AllTargets        bool
 	ShowInstallMethod bool
+	// NoDeps installs only the named resource, ignoring manifest dependencies.
+	NoDeps bool
+	// DryRun resolves the dependency plan without installing anything.
+	DryRun bool
+	// StrictTemplates fails on unrenderable template placeholders even if the vars file is lenient.
+	StrictTemplates bool
 }
 
 // InstallAllRequest describes install-all behavior.
@@ -45,24 +52,40 @@ type InstallResult struct {
 	OutputDir  string
 	Strategy   core.InstallStrategy
 	ShowMethod bool
+	// Warnings are raised by the target for installed rules, such as a Windsurf rule truncated
+	// to fit its size limit.
+	Warnings []string
 }
 
 // InstallResponse captures install outcomes.
 type InstallResponse struct {
+	// Plan lists the requested resource and its dependencies in install order.
+	Plan    []InstallPlanStep
+	DryRun  bool
 	Results []InstallResult
 }
 
+// InstallSkip records a planned install that was skipped because it does not apply to the project.
+type InstallSkip struct {
+	Name   string
+	Target string
+	Reason string
+}
+
 // InstallAllResponse captures install-all outcomes.
 type InstallAllResponse struct {
 	PackageDir string
 	Packages   []string
 	Results    []InstallResult
+	Skipped    []InstallSkip
 }
 
 type installAllEntry struct {
-	Name   string
-	Target string
-	Label  string
+	Name       string
+	Target     string
+	Label      string
+	PackageDir string
+	Source     string
 }
 
 // Install installs a preset or package according to the request.
@@ -78,30 +101,86 @@ func (a *App) Install(req *InstallRequest) (*InstallResponse, error) {
 	if req.Global {
 		wd = config.GlobalProjectRoot(cfg)
 	}
+	name := strings.TrimSpace(req.Name)
 	packageDir := strings.TrimSpace(req.PackageDir)
+	sources := a.PackageSources(cfg)
+	source := ""
 	if packageDir == "" {
-		packageDir = a.ResolvePackageDir(cfg)
-	}
-
-	results, err := a.installInternal(&installInternalRequest{
-		Workdir:           wd,
-		PackageDir:        packageDir,
-		Name:              req.Name,
-		Excludes:          req.Excludes,
-		NoFlatten:         req.NoFlatten,
-		Target:            req.Target,
-		AllTargets:        req.AllTargets,
-		ShowInstallMethod: req.ShowInstallMethod,
-		SkillsSubdir:      cfg.SkillsSubdir,
-		AgentsSubdir:      cfg.AgentsSubdir,
-		HooksSubdir:       cfg.HooksSubdir,
-		IsUser:            req.Global,
-	})
+		src, bare, err := a.resolveSource(sources, name, req.Target, cfg)
+		if err != nil {
+			return nil, err
+		}
+		packageDir, name = src.Path, bare
+		source = configuredSourceName(cfg, src)
+	} else if qualifier, _ := splitSourceName(name); qualifier != "" {
+		return nil, errors.Newf(errors.CodeInvalidArgument, "source-qualified name %q cannot be combined with an explicit package dir", req.Name)
+	} else {
+		sources = []config.PackageSource{{Name: config.DefaultSourceName, Path: packageDir}}
+	}
+
+	target := strings.TrimSpace(req.Target)
+	if target == "" {
+		target = "cursor"
+	}
+	root := InstallPlanStep{
+		Kind:       resourceKindRule,
+		Name:       req.Name,
+		Target:     target,
+		packageDir: packageDir,
+		source:     source,
+		bareName:   name,
+	}
+	if provider, ok := a.resourceRegistry().providerForTarget(target); ok {
+		root.Kind = provider.Kind()
+	}
+	plan := []InstallPlanStep{root}
+	if !req.NoDeps {
+		if plan, err = a.planInstall(sources, cfg, root); err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &InstallResponse{Plan: plan, DryRun: req.DryRun}
+	if req.DryRun {
+		return resp, nil
+	}
+	tmpl, err := projectTemplate(wd, req.StrictTemplates)
 	if err != nil {
 		return nil, err
 	}
+	for idx, step := range plan {
+		// Excludes and --all-targets apply to the requested package only.
+		isRoot := idx == len(plan)-1
+		internal := &installInternalRequest{
+			Workdir:           wd,
+			PackageDir:        step.packageDir,
+			Source:            step.source,
+			Name:              step.bareName,
+			NoFlatten:         req.NoFlatten,
+			Target:            step.Target,
+			ShowInstallMethod: req.ShowInstallMethod && idx == 0,
+			SkillsSubdir:      cfg.SkillsSubdir,
+			AgentsSubdir:      cfg.AgentsSubdir,
+			HooksSubdir:       cfg.HooksSubdir,
+			IsUser:            req.Global,
+			Template:          tmpl,
+		}
+		if isRoot {
+			internal.Target = req.Target
+			internal.Excludes = req.Excludes
+			internal.AllTargets = req.AllTargets
+		}
+		results, err := a.installInternal(internal)
+		if err != nil {
+			if !isRoot {
+				return nil, errors.Wrapf(err, errors.CodeOf(err), "install dependency %s %q of %q", step.Kind, step.Name, step.RequiredBy)
+			}
+			return nil, err
+		}
+		resp.Results = append(resp.Results, results...)
+	}
 
-	return &InstallResponse{Results: results}, nil
+	return resp, nil
 }
 
 // InstallAll installs all packages in the resolved package directory.
@@ -117,29 +196,51 @@ func (a *App) InstallAll(req *InstallAllRequest) (*InstallAllResponse, error) {
 	if req.Global {
 		wd = config.GlobalProjectRoot(cfg)
 	}
-	packageDir := strings.TrimSpace(req.PackageDir)
-	if packageDir == "" {
-		packageDir = a.ResolvePackageDir(cfg)
+	sources := a.PackageSources(cfg)
+	if packageDir := strings.TrimSpace(req.PackageDir); packageDir != "" {
+		sources = []config.PackageSource{{Name: config.DefaultSourceName, Path: packageDir}}
 	}
 
-	entries, err := a.planInstallAllEntries(packageDir, cfg, req.Target)
+	entries, err := a.planInstallAllFromSources(sources, cfg, req.Target)
 	if err != nil {
-		return nil, errors.Wrapf(err, errors.CodeInternal, "plan install-all entries")
+		return nil, err
 	}
 
 	resp := &InstallAllResponse{
-		PackageDir: packageDir,
+		PackageDir: sources[0].Path,
 		Packages:   installAllLabels(entries),
 	}
 	if len(entries) == 0 {
 		return resp, nil
 	}
 
-	for idx, entry := range entries {
-		show := req.ShowInstallMethodFirst && idx == 0
+	facts, err := detectProjectFacts(wd)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := facts.template(false)
+	if err != nil {
+		return nil, err
+	}
+	registry := a.resourceRegistry()
+	shown := false
+	for _, entry := range entries {
+		if provider, ok := registry.providerForTarget(entry.Target); ok && provider.Kind() == resourceKindRule {
+			reason, err := presetSkipReason(entry.PackageDir, entry.Name, facts)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				resp.Skipped = append(resp.Skipped, InstallSkip{Name: entry.Label, Target: entry.Target, Reason: reason})
+				continue
+			}
+		}
+		show := req.ShowInstallMethodFirst && !shown
+		shown = true
 		results, err := a.installInternal(&installInternalRequest{
 			Workdir:           wd,
-			PackageDir:        packageDir,
+			PackageDir:        entry.PackageDir,
+			Source:            entry.Source,
 			Name:              entry.Name,
 			Excludes:          req.Excludes,
 			NoFlatten:         req.NoFlatten,
@@ -150,6 +251,7 @@ func (a *App) InstallAll(req *InstallAllRequest) (*InstallAllResponse, error) {
 			AgentsSubdir:      cfg.AgentsSubdir,
 			HooksSubdir:       cfg.HooksSubdir,
 			IsUser:            req.Global,
+			Template:          tmpl,
 		})
 		if err != nil {
 			return nil, err
@@ -160,6 +262,34 @@ func (a *App) InstallAll(req *InstallAllRequest) (*InstallAllResponse, error) {
 	return resp, nil
 }
 
+// planInstallAllFromSources plans install-all across sources. A target/name pair provided by
+// several sources is installed from the first one only.
+func (a *App) planInstallAllFromSources(sources []config.PackageSource, cfg *config.Config, target string) ([]installAllEntry, error) {
+	var entries []installAllEntry
+	seen := make(map[string]struct{})
+	for _, src := range sources {
+		if err := checkSourceSynced(src); err != nil {
+			return nil, err
+		}
+		planned, err := a.planInstallAllEntries(src.Path, cfg, target)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "plan install-all entries for source %q", src.Name)
+		}
+		for _, entry := range planned {
+			key := entry.Target + "\x00" + entry.Name
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			entry.PackageDir = src.Path
+			entry.Source = configuredSourceName(cfg, src)
+			entry.Label = qualifySourceName(sources, src.Name, entry.Label)
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
 func (a *App) planInstallAllEntries(packageDir string, cfg *config.Config, target string) ([]installAllEntry, error) {
 	trimmedTarget := strings.TrimSpace(target)
 	if trimmedTarget == "" {
@@ -205,6 +335,7 @@ func installAllLabels(entries []installAllEntry) []string {
 type installInternalRequest struct {
 	Workdir           string
 	PackageDir        string
+	Source            string
 	Name              string
 	Excludes          []string
 	NoFlatten         bool
@@ -215,6 +346,10 @@ type installInternalRequest struct {
 	AgentsSubdir      string
 	HooksSubdir       string
 	IsUser            bool
+	StrictTemplates   bool
+	// Template renders the installed rules. When nil, installInternal builds one for Workdir,
+	// so callers installing several resources should build it once and share it.
+	Template *transform.Template
 }
 
 func (a *App) installInternal(req *installInternalRequest) ([]InstallResult, error) {
@@ -222,24 +357,13 @@ func (a *App) installInternal(req *installInternalRequest) ([]InstallResult, err
 		return nil, errors.New(errors.CodeInvalidArgument, "missing preset or package name")
 	}
 
-	pkgPath := filepath.Join(req.PackageDir, req.Name)
-	info, statErr := os.Stat(pkgPath)
-	isPackage := statErr == nil && info.IsDir()
-	if !isPackage {
-		rulesPkgPath := filepath.Join(core.ResolveRulesPackageDir(req.PackageDir), req.Name)
-		if rulesPkgInfo, rulesErr := os.Stat(rulesPkgPath); rulesErr == nil && rulesPkgInfo.IsDir() {
-			pkgPath = rulesPkgPath
-			isPackage = true
-		}
-	}
+	pkgPath, isPackage := packagePath(req.PackageDir, req.Name)
 
 	var m *manifest.Manifest
 	if isPackage {
 		var err error
-		m, err = manifest.Load(pkgPath)
-		if err != nil {
-			// Manifest load errors are non-fatal; proceed without it
-			m = nil
+		if m, err = loadPackageManifest(pkgPath, a.resourceRegistry().targets()); err != nil {
+			return nil, err
 		}
 	}
 
@@ -270,19 +394,29 @@ func (a *App) installInternal(req *installInternalRequest) ([]InstallResult, err
 		effectiveExcludes = append(effectiveExcludes, m.Exclude...)
 	}
 
+	tmpl := req.Template
+	if tmpl == nil {
+		var err error
+		if tmpl, err = projectTemplate(req.Workdir, req.StrictTemplates); err != nil {
+			return nil, err
+		}
+	}
+
 	results := make([]InstallResult, 0, len(targets))
 	for _, tgt := range targets {
 		provider, ok := a.resourceRegistry().providerForTarget(tgt)
 		if !ok {
 			return nil, errors.Newf(errors.CodeInvalidArgument, "unknown target: %s", tgt)
 		}
-		strategy, err := provider.Install(req.Workdir, req.PackageDir, req.Name, providerCfg, nativeResourceInstallOptions{
+		strategy, warnings, err := installWithProvider(provider, req.Workdir, req.PackageDir, req.Name, providerCfg, nativeResourceInstallOptions{
 			Excludes:  effectiveExcludes,
 			NoFlatten: req.NoFlatten,
 			IsUser:    req.IsUser,
+			Source:    req.Source,
+			Template:  tmpl,
 		})
 		if err != nil {
-			return nil, errors.Wrapf(err, errors.CodeInternal, "install to %s failed", tgt)
+			return nil, err
 		}
 		results = append(results, InstallResult{
 			Name:       req.Name,
@@ -290,12 +424,82 @@ func (a *App) installInternal(req *installInternalRequest) ([]InstallResult, err
 			OutputDir:  provider.OutputDir(req.Workdir, providerCfg, req.IsUser),
 			Strategy:   strategy,
 			ShowMethod: req.ShowInstallMethod && provider.Target() == "cursor",
+			Warnings:   warnings,
 		})
 	}
 
 	return results, nil
 }
 
+// packagePath returns the directory of package name, looking in packageDir itself and then in
+// its rules subdirectory.
+func packagePath(packageDir, name string) (string, bool) {
+	for _, dir := range []string{packageDir, core.ResolveRulesPackageDir(packageDir)} {
+		pkgPath := filepath.Join(dir, name)
+		if info, err := os.Stat(pkgPath); err == nil && info.IsDir() {
+			return pkgPath, true
+		}
+	}
+	return "", false
+}
+
+// installWithProvider installs name via provider and records project installs in the lockfile.
+// It returns the warnings the target raised for the installed rules.
+func installWithProvider(provider nativeResourceProvider, projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, []string, error) {
+	if opts.Template == nil {
+		tmpl, err := projectTemplate(projectRoot, false)
+		if err != nil {
+			return core.StrategyUnknown, nil, err
+		}
+		opts.Template = tmpl
+	}
+	opts.Written = &core.Written{}
+	strategy, err := provider.Install(projectRoot, packageDir, name, cfg, opts)
+	if err != nil {
+		return core.StrategyUnknown, nil, errors.Wrapf(err, errors.CodeInternal, "install to %s failed", provider.Target())
+	}
+	if !opts.IsUser {
+		if err := recordInstall(provider, projectRoot, packageDir, name, cfg, opts, strategy); err != nil {
+			return core.StrategyUnknown, nil, err
+		}
+	}
+	return strategy, opts.Written.Warnings(), nil
+}
+
+// loadPackageManifest loads the manifest of the package at pkgPath. When knownTargets is
+// non-nil the manifest's targets and overrides are checked against it as well.
+func loadPackageManifest(pkgPath string, knownTargets []string) (*manifest.Manifest, error) {
+	m, err := manifest.LoadAndValidate(pkgPath, knownTargets)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "load manifest for %s", filepath.Base(pkgPath))
+	}
+	return m, nil
+}
+
+// packageTransformer configures trans with the overrides the manifest of the package at pkgPath
+// declares for its target.
+func packageTransformer(trans transform.Transformer, pkgPath string) (transform.Transformer, error) {
+	m, err := loadPackageManifest(pkgPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	override := m.GetOverride(trans.Target())
+	if override == nil {
+		return trans, nil
+	}
+	configured, err := transform.ApplyOverrides(trans, transform.Overrides{
+		DefaultMode:  override.DefaultMode,
+		DefaultTools: override.DefaultTools,
+		IncludeRefs:  override.IncludeRefs,
+		Nested:       override.Nested,
+		Mode:         override.Mode,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "apply %s overrides from %s manifest", trans.Target(), filepath.Base(pkgPath))
+	}
+	return configured, nil
+}
+
 func (a *App) transformer(target string) (transform.Transformer, error) {
 	if a == nil || a.Transformers == nil {
 		return nil, errors.New(errors.CodeFailedPrecondition, "no transformers configured")
@@ -309,9 +513,10 @@ func installPackageWithTransformer(
 	transformer transform.Transformer,
 	excludes []string,
 	noFlatten bool,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
 	outDir := filepath.Join(workDir, transformer.OutputDir())
-	return installPackageWithTransformerToOutDir(outDir, pkgPath, presetName, transformer, excludes, noFlatten)
+	return installPackageWithTransformerToOutDir(outDir, pkgPath, presetName, transformer, excludes, noFlatten, written)
 }
 
 // installPackageWithTransformerToRulesDir installs a package into the given rules directory.
@@ -320,8 +525,9 @@ func installPackageWithTransformerToRulesDir(
 	transformer transform.Transformer,
 	excludes []string,
 	noFlatten bool,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
-	return installPackageWithTransformerToOutDir(rulesDir, pkgPath, presetName, transformer, excludes, noFlatten)
+	return installPackageWithTransformerToOutDir(rulesDir, pkgPath, presetName, transformer, excludes, noFlatten, written)
 }
 
 func installPackageWithTransformerToOutDir(
@@ -329,10 +535,15 @@ func installPackageWithTransformerToOutDir(
 	transformer transform.Transformer,
 	excludes []string,
 	noFlatten bool,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
 	if err := os.MkdirAll(outDir, 0o755); err != nil {
 		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "create output dir for package %q", presetName)
 	}
+	excluded, err := glob.CompileSet(excludes)
+	if err != nil {
+		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid exclude pattern in package %q", presetName)
+	}
 
 	if err := filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -346,10 +557,10 @@ func installPackageWithTransformerToOutDir(
 		if err != nil {
 			return errors.Wrapf(err, errors.CodeInternal, "get relative path")
 		}
-		if shouldExclude(relPath, excludes) {
+		if excluded.Matches(relPath) {
 			return nil
 		}
-		if err := transformAndWriteFile(path, relPath, outDir, transformer, noFlatten); err != nil {
+		if err := transformAndWriteFile(path, relPath, outDir, transformer, noFlatten, written); err != nil {
 			return errors.Wrapf(err, errors.CodeInternal, "install file from package %q", presetName)
 		}
 		return nil
@@ -364,9 +575,10 @@ func installPresetWithTransformer(
 	workDir, presetPath, presetName string,
 	transformer transform.Transformer,
 	packageDir string,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
 	outDir := filepath.Join(workDir, transformer.OutputDir())
-	return installPresetWithTransformerToOutDir(outDir, presetPath, presetName, transformer, packageDir)
+	return installPresetWithTransformerToOutDir(outDir, presetPath, presetName, transformer, packageDir, written)
 }
 
 // installPresetWithTransformerToRulesDir installs a preset into the given rules directory.
@@ -374,14 +586,16 @@ func installPresetWithTransformerToRulesDir(
 	rulesDir, presetPath, presetName string,
 	transformer transform.Transformer,
 	packageDir string,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
-	return installPresetWithTransformerToOutDir(rulesDir, presetPath, presetName, transformer, packageDir)
+	return installPresetWithTransformerToOutDir(rulesDir, presetPath, presetName, transformer, packageDir, written)
 }
 
 func installPresetWithTransformerToOutDir(
 	outDir, presetPath, presetName string,
 	transformer transform.Transformer,
 	packageDir string,
+	written *core.Written,
 ) (core.InstallStrategy, error) {
 	if !strings.HasSuffix(presetPath, ".mdc") {
 		presetPath += ".mdc"
@@ -396,7 +610,7 @@ func installPresetWithTransformerToOutDir(
 			packageDirResolved = core.DefaultPackageDir()
 		}
 		if core.UseSymlink() || core.WantGNUStow() {
-			return core.ApplyPresetWithOptionalSymlinkToRulesDir(outDir, presetName, packageDirResolved)
+			return core.ApplyPresetWithOptionalSymlinkToRulesDir(outDir, presetName, packageDirResolved, written)
 		}
 	}
 
@@ -404,17 +618,18 @@ func installPresetWithTransformerToOutDir(
 		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "create output dir for preset %q", presetName)
 	}
 
-	if err := transformAndWriteFile(presetPath, filepath.Base(presetPath), outDir, transformer, false); err != nil {
+	if err := transformAndWriteFile(presetPath, filepath.Base(presetPath), outDir, transformer, false, written); err != nil {
 		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "install preset %q", presetName)
 	}
 	return core.StrategyCopy, nil
 }
 
-// transformAndWriteFile reads, transforms, and writes a single file.
+// transformAndWriteFile reads, transforms, and writes a single file, recording it in written.
 func transformAndWriteFile(
 	srcPath, relPath, outDir string,
 	transformer transform.Transformer,
 	noFlatten bool,
+	written *core.Written,
 ) error {
 	data, err := os.ReadFile(srcPath)
 	if err != nil {
@@ -435,6 +650,14 @@ func transformAndWriteFile(
 		return errors.Wrapf(validateErr, errors.CodeInvalidArgument, "validate %s", srcPath)
 	}
 
+	warning := transform.CheckRule(transformer, transformedFM, transformedBody)
+
+	if dir, ok, err := transform.RuleDir(transformer, frontmatter); err != nil {
+		return errors.Wrapf(err, errors.CodeInvalidArgument, "route %s", srcPath)
+	} else if ok {
+		outDir = filepath.Join(filepath.Dir(outDir), dir)
+	}
+
 	var outPath string
 	if noFlatten {
 		outPath = filepath.Join(outDir, relPath)
@@ -443,7 +666,7 @@ func transformAndWriteFile(
 	}
 	outPath = strings.TrimSuffix(outPath, ".mdc") + transformer.Extension()
 
-	output, err := transform.MarshalMarkdown(transformedFM, transformedBody)
+	output, err := transform.RenderRule(transformer, transformedFM, transformedBody)
 	if err != nil {
 		return errors.Wrapf(err, errors.CodeInternal, "marshal %s", srcPath)
 	}
@@ -456,6 +679,10 @@ func transformAndWriteFile(
 		}
 	}
 
+	written.Add(outPath)
+	if warning != "" {
+		written.Warn(outPath, warning)
+	}
 	existing, readErr := os.ReadFile(outPath)
 	if readErr == nil && bytes.Equal(existing, output) {
 		return nil
@@ -468,17 +695,3 @@ func transformAndWriteFile(
 	// #nosec G306 - rule files are meant to be world-readable
 	return os.WriteFile(outPath, output, 0o644)
 }
-
-func shouldExclude(relPath string, patterns []string) bool {
-	for _, pattern := range patterns {
-		matched, err := filepath.Match(pattern, relPath)
-		if err == nil && matched {
-			return true
-		}
-		matched, err = filepath.Match(pattern, filepath.Dir(relPath))
-		if err == nil && matched {
-			return true
-		}
-	}
-	return false
-}
@@ -6,6 +6,7 @@ import (
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
 )
 
 // ListRequest describes a rules listing request.
@@ -14,6 +15,8 @@ type ListRequest struct {
 	Target     string
 	Kind       string
 	Global     bool // if true, list from user dirs (~/.cursor/...) instead of package dir
+	Installed  bool // if true, list resources installed in the project instead of the package dir
+	Workdir    string
 }
 
 // ListTargetEntry contains items for one concrete target.
@@ -21,12 +24,24 @@ type ListTargetEntry struct {
 	Target string
 	Kind   string
 	Items  []string
+	// Unmanaged lists installed items that are not recorded in the project lockfile.
+	Unmanaged []string
 }
 
-// ListResponse contains rules tree data plus target-scoped entries.
+// ListSource is the rules tree of one package source when several are configured.
+type ListSource struct {
+	Name       string
+	PackageDir string
+	Tree       *core.RulesTree
+}
+
+// ListResponse contains rules tree data plus target-scoped entries. With several package
+// sources, Sources holds one tree per source and items are qualified as "source:name" in
+// precedence order.
 type ListResponse struct {
 	PackageDir string
 	Tree       *core.RulesTree
+	Sources    []ListSource
 	Targets    []ListTargetEntry
 	// Errors holds partial failures from providers (e.g. permissions, missing dirs).
 	// When using structured/JSON output, include this field so callers can surface warnings.
@@ -82,26 +97,84 @@ func (a *App) ListRules(req ListRequest) (*ListResponse, error) {
 		}
 		return resp, nil
 	}
-	packageDir = a.ResolvePackageDir(cfg)
-	resp := &ListResponse{PackageDir: packageDir}
-	if showRulesTree {
-		tree, err := core.BuildRulesTree(packageDir)
-		if err != nil {
-			return nil, err
+	if req.Installed {
+		packageDir = a.ResolvePackageDir(cfg)
+		return a.listInstalled(req, cfg, packageDir, providers)
+	}
+	sources := a.PackageSources(cfg)
+	resp := &ListResponse{PackageDir: sources[0].Path}
+	entryIndex := make(map[string]int, len(providers))
+	for _, src := range sources {
+		var tree *core.RulesTree
+		if showRulesTree {
+			tree, err = core.BuildRulesTree(src.Path)
+			if err != nil {
+				if len(sources) == 1 {
+					return nil, err
+				}
+				resp.Errors = append(resp.Errors, src.Name+": "+err.Error())
+			}
+		}
+		if resp.Tree == nil {
+			resp.Tree = tree
+		}
+		if len(sources) > 1 {
+			resp.Sources = append(resp.Sources, ListSource{Name: src.Name, PackageDir: src.Path, Tree: tree})
+		}
+		for _, provider := range providers {
+			items, err := provider.ListAvailable(src.Path, cfg)
+			if err != nil {
+				resp.Errors = append(resp.Errors, qualifySourceName(sources, src.Name, provider.Target())+": "+err.Error())
+				continue
+			}
+			idx, ok := entryIndex[provider.Target()]
+			if !ok {
+				idx = len(resp.Targets)
+				entryIndex[provider.Target()] = idx
+				resp.Targets = append(resp.Targets, ListTargetEntry{Target: provider.Target(), Kind: provider.Kind()})
+			}
+			for _, item := range items {
+				resp.Targets[idx].Items = append(resp.Targets[idx].Items, qualifySourceName(sources, src.Name, item))
+			}
 		}
-		resp.Tree = tree
 	}
+	return resp, nil
+}
+
+// listInstalled lists resources installed in the project, flagging items missing from the lockfile.
+func (a *App) listInstalled(req ListRequest, cfg *config.Config, packageDir string, providers []nativeResourceProvider) (*ListResponse, error) {
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := lockfile.Load(wd)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "read lockfile")
+	}
+	resp := &ListResponse{PackageDir: packageDir}
 	for _, provider := range providers {
-		items, err := provider.ListAvailable(packageDir, cfg)
+		items, err := provider.ListInstalled(wd, cfg, false)
 		if err != nil {
 			resp.Errors = append(resp.Errors, provider.Target()+": "+err.Error())
 			continue
 		}
-		resp.Targets = append(resp.Targets, ListTargetEntry{
+		ext := ""
+		if provider.Kind() == resourceKindRule {
+			if transformer, err := a.transformer(provider.Target()); err == nil {
+				ext = transformer.Extension()
+			}
+		}
+		entry := ListTargetEntry{
 			Target: provider.Target(),
 			Kind:   provider.Kind(),
 			Items:  items,
-		})
+		}
+		for _, item := range items {
+			if !lockManagesItem(lock, provider, wd, item, cfg, ext) {
+				entry.Unmanaged = append(entry.Unmanaged, item)
+			}
+		}
+		resp.Targets = append(resp.Targets, entry)
 	}
 	return resp, nil
 }
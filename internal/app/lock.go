@@ -0,0 +1,316 @@
+package app
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+)
+
+// renderResource installs a resource into a scratch project root and returns the content the
+// lockfile tracks of each file the provider produced, keyed by project-relative slash path.
+// Shared files the resource owns no part of are left out. It never touches the real project; status
+// uses it to compare installed files against what the source produces now.
+func renderResource(provider nativeResourceProvider, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (map[string][]byte, error) {
+	scratch, err := os.MkdirTemp("", "cursor-rules-render-")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "create scratch dir")
+	}
+	defer os.RemoveAll(scratch)
+
+	opts.IsUser = false
+	if _, err := provider.Install(scratch, packageDir, name, cfg, opts); err != nil {
+		return nil, err
+	}
+	files, err := collectFiles(scratch)
+	if err != nil {
+		return nil, err
+	}
+	shared, ok := provider.(sharedFileProvider)
+	if !ok {
+		return files, nil
+	}
+	for rel, data := range files {
+		owned := shared.ownedContent(filepath.Join(scratch, filepath.FromSlash(rel)), name, data)
+		if len(owned) == 0 {
+			delete(files, rel)
+			continue
+		}
+		files[rel] = owned
+	}
+	return files, nil
+}
+
+// collectFiles reads every regular file below root, following symlinks, keyed by slash path relative to root.
+func collectFiles(root string) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	var walk func(dir, relDir string) error
+	walk = func(dir, relDir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			rel := filepath.Join(relDir, entry.Name())
+			info, err := os.Stat(path)
+			if err != nil {
+				// Dangling symlinks carry no content worth recording.
+				continue
+			}
+			if info.IsDir() {
+				if err := walk(path, rel); err != nil {
+					return err
+				}
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			out[filepath.ToSlash(rel)] = data
+		}
+		return nil
+	}
+	if err := walk(root, ""); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// lockEntryFor builds a lock entry by hashing the project files the install recorded in
+// opts.Written. Directories (written by stow) are recorded with every file below them.
+func lockEntryFor(provider nativeResourceProvider, projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions, strategy core.InstallStrategy) (lockfile.Entry, error) {
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return lockfile.Entry{}, errors.Wrapf(err, errors.CodeInternal, "resolve %s", projectRoot)
+	}
+	contents := make(map[string][]byte)
+	owners := make(map[string]string)
+	for _, written := range opts.Written.Paths() {
+		abs, err := filepath.Abs(written)
+		if err != nil {
+			return lockfile.Entry{}, err
+		}
+		rel, ok := relativeTo(root, abs)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return lockfile.Entry{}, err
+		}
+		if !info.IsDir() {
+			data, err := os.ReadFile(abs)
+			if err != nil {
+				return lockfile.Entry{}, err
+			}
+			contents[filepath.ToSlash(rel)] = data
+			owners[filepath.ToSlash(rel)] = abs
+			continue
+		}
+		below, err := collectFiles(abs)
+		if err != nil {
+			return lockfile.Entry{}, err
+		}
+		for sub, data := range below {
+			contents[path.Join(filepath.ToSlash(rel), sub)] = data
+			owners[path.Join(filepath.ToSlash(rel), sub)] = filepath.Join(abs, filepath.FromSlash(sub))
+		}
+	}
+
+	paths := make([]string, 0, len(contents))
+	for rel := range contents {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	files := make([]lockfile.File, 0, len(paths))
+	for _, rel := range paths {
+		files = append(files, lockfile.File{Path: rel, Hash: lockfile.HashBytes(lockedContent(provider, owners[rel], name, contents[rel]))})
+	}
+
+	return lockfile.Entry{
+		Kind:      provider.Kind(),
+		Target:    provider.Target(),
+		Name:      name,
+		Source:    opts.Source,
+		Path:      resourceSourcePath(provider, packageDir, name, cfg),
+		Strategy:  string(strategy),
+		Excludes:  append([]string(nil), opts.Excludes...),
+		NoFlatten: opts.NoFlatten,
+		Hash:      lockfile.HashFiles(files),
+		Files:     files,
+	}, nil
+}
+
+// resourceSourcePath returns where a resource lives inside packageDir as a slash path, or ""
+// when it cannot be found there.
+func resourceSourcePath(provider nativeResourceProvider, packageDir, name string, cfg *config.Config) string {
+	candidates := []string{filepath.Join(packageDir, name)}
+	switch provider.Kind() {
+	case resourceKindRule:
+		rulesDir := core.ResolveRulesPackageDir(packageDir)
+		candidates = append(candidates, filepath.Join(rulesDir, name), filepath.Join(rulesDir, name+".mdc"))
+	case resourceKindCommand:
+		commandsDir := filepath.Join(packageDir, core.CommandsSubdir())
+		for _, ext := range []string{"", ".command.mdc", ".md", ".mdc"} {
+			candidates = append(candidates, filepath.Join(commandsDir, name+ext))
+		}
+	case resourceKindSkill:
+		candidates = append(candidates, filepath.Join(packageDir, core.SkillsSubdir(cfg.SkillsSubdir), name))
+	case resourceKindAgent:
+		candidates = append(candidates, filepath.Join(packageDir, core.ResolveAgentsSubdir(packageDir, cfg.AgentsSubdir), name+".md"))
+	case resourceKindHooks:
+		candidates = append(candidates, filepath.Join(packageDir, core.HooksSubdir(cfg.HooksSubdir), name))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err != nil {
+			continue
+		}
+		if rel, ok := relativeTo(packageDir, candidate); ok {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return ""
+}
+
+// relativeTo returns target relative to base when target is base or lies below it.
+func relativeTo(base, target string) (string, bool) {
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// recordInstall updates the project lockfile after a successful install.
+func recordInstall(provider nativeResourceProvider, projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions, strategy core.InstallStrategy) error {
+	entry, err := lockEntryFor(provider, projectRoot, packageDir, name, cfg, opts, strategy)
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "record %s %q in lockfile", provider.Target(), name)
+	}
+	lock, err := lockfile.Load(projectRoot)
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "read lockfile")
+	}
+	if !provider.RequiresName() {
+		// Unnamed resources (hooks) replace whatever was installed for the target before.
+		lock.Remove(provider.Target(), "")
+	}
+	lock.Upsert(entry)
+	if err := lockfile.Save(projectRoot, lock); err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "write lockfile")
+	}
+	return nil
+}
+
+// recordRemove drops a removed resource from the project lockfile and reports whether it was managed.
+func recordRemove(provider nativeResourceProvider, projectRoot, name string) (bool, error) {
+	lock, err := lockfile.Load(projectRoot)
+	if err != nil {
+		return false, errors.Wrapf(err, errors.CodeInternal, "read lockfile")
+	}
+	if !provider.RequiresName() {
+		name = ""
+	}
+	if !lock.Remove(provider.Target(), strings.TrimSpace(name)) {
+		return false, nil
+	}
+	if err := lockfile.Save(projectRoot, lock); err != nil {
+		return false, errors.Wrapf(err, errors.CodeInternal, "write lockfile")
+	}
+	return true, nil
+}
+
+// sharedFileProvider is implemented by providers that install into files shared with other
+// resources and hand-written text, such as composed AGENTS.md sections. The lockfile hashes and
+// removes only the part of each file the resource owns.
+type sharedFileProvider interface {
+	ownedContent(path, name string, data []byte) []byte
+	removeOwnedContent(path, name string) error
+}
+
+// lockedContent returns the part of a file's data the lockfile tracks for resource name; path is
+// where data was read from.
+func lockedContent(provider nativeResourceProvider, path, name string, data []byte) []byte {
+	if shared, ok := provider.(sharedFileProvider); ok {
+		return shared.ownedContent(path, name, data)
+	}
+	return data
+}
+
+// removeLockedFiles deletes the files recorded for a lock entry, pruning directories below
+// outputDir (or, for routed rules, below its parent) that are left empty. Shared files only lose
+// the entry's part.
+func removeLockedFiles(provider nativeResourceProvider, projectRoot, outputDir string, entry lockfile.Entry) error {
+	for _, f := range entry.Files {
+		path := filepath.Join(projectRoot, filepath.FromSlash(f.Path))
+		if shared, ok := provider.(sharedFileProvider); ok {
+			if err := shared.removeOwnedContent(path, entry.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		stop := outputDir
+		if _, inside := relativeTo(outputDir, path); !inside {
+			// Routed rules live in "<output dir>-*" siblings of the output directory.
+			stop = filepath.Dir(outputDir)
+		}
+		pruneEmptyDirs(filepath.Dir(path), stop)
+	}
+	return nil
+}
+
+// pruneEmptyDirs removes dir and its empty parents, stopping before stop and never leaving it.
+func pruneEmptyDirs(dir, stop string) {
+	for {
+		if rel, ok := relativeTo(stop, dir); !ok || rel == "." {
+			return
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// itemPathProvider is implemented by providers whose installed list items are not paths relative
+// to their output directory, such as routed rules listed as "<dir>/<name>".
+type itemPathProvider interface {
+	itemPath(projectRoot, item string, cfg *config.Config) string
+}
+
+// lockManagesItem reports whether an installed list item for provider is recorded in the lockfile.
+func lockManagesItem(lock *lockfile.Lock, provider nativeResourceProvider, projectRoot, item string, cfg *config.Config, ext string) bool {
+	if _, ok := lock.Find(provider.Target(), item); ok {
+		return true
+	}
+	itemPath := filepath.Join(provider.OutputDir(projectRoot, cfg, false), item)
+	if located, ok := provider.(itemPathProvider); ok {
+		itemPath = located.itemPath(projectRoot, item, cfg)
+	}
+	rel, err := filepath.Rel(projectRoot, itemPath)
+	if err != nil {
+		return false
+	}
+	if lock.ManagesPath(provider.Target(), rel) {
+		return true
+	}
+	return ext != "" && lock.ManagesPath(provider.Target(), rel+ext)
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestInstallWritesLockfile(t *testing.T) {
+	packageDir, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc":            testutil.Rule("description: git", "Use conventional commits."),
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install git failed: %v", err)
+	}
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install frontend failed: %v", err)
+	}
+
+	lock, err := lockfile.Load(projectDir)
+	if err != nil {
+		t.Fatalf("load lockfile: %v", err)
+	}
+	if len(lock.Entries) != 2 {
+		t.Fatalf("expected 2 lock entries, got %+v", lock.Entries)
+	}
+	entry, ok := lock.Find("cursor", "frontend")
+	if !ok {
+		t.Fatalf("frontend not recorded: %+v", lock.Entries)
+	}
+	if entry.Kind != resourceKindRule || entry.Source != "" || entry.Path != "frontend" || entry.Strategy != "copy" {
+		t.Errorf("unexpected entry metadata: %+v", entry)
+	}
+	if git, _ := lock.Find("cursor", "git"); git.Path != "git.mdc" {
+		t.Errorf("unexpected source path for git: %+v", git)
+	}
+	raw, err := os.ReadFile(lockfile.Path(projectDir))
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	if strings.Contains(string(raw), packageDir) {
+		t.Errorf("lockfile should not record the machine-specific package dir:\n%s", raw)
+	}
+	if len(entry.Files) != 2 || entry.Files[0].Path != ".cursor/rules/css.mdc" || entry.Files[1].Path != ".cursor/rules/react.mdc" {
+		t.Fatalf("unexpected recorded files: %+v", entry.Files)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".cursor", "rules", "css.mdc"))
+	if err != nil {
+		t.Fatalf("read installed file: %v", err)
+	}
+	if entry.Files[0].Hash != lockfile.HashBytes(data) {
+		t.Errorf("hash mismatch for css.mdc")
+	}
+	if entry.Hash != lockfile.HashFiles(entry.Files) {
+		t.Errorf("aggregate hash mismatch")
+	}
+}
+
+func TestRemoveUpdatesLockfileAndRemovesRecordedPackageFiles(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	resp, err := a.Remove(RemoveRequest{Name: "frontend", Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if len(resp.Matches) != 1 || !resp.Matches[0].Removed || !resp.Matches[0].Managed {
+		t.Fatalf("unexpected matches: %+v", resp.Matches)
+	}
+	assertNotExists(t, filepath.Join(projectDir, ".cursor", "rules", "react.mdc"))
+	assertNotExists(t, filepath.Join(projectDir, ".cursor", "rules", "css.mdc"))
+	assertNotExists(t, lockfile.Path(projectDir))
+}
+
+func TestListInstalledMarksUnmanagedItems(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	writeInstalledRuleFile(t, filepath.Join(projectDir, ".cursor", "rules", "handwritten.mdc"), "mine")
+
+	resp, err := a.ListRules(ListRequest{Installed: true, Workdir: projectDir, Target: "cursor"})
+	if err != nil {
+		t.Fatalf("ListRules failed: %v", err)
+	}
+	if len(resp.Targets) != 1 {
+		t.Fatalf("unexpected targets: %+v", resp.Targets)
+	}
+	entry := resp.Targets[0]
+	if len(entry.Items) != 3 {
+		t.Fatalf("expected 3 installed items, got %v", entry.Items)
+	}
+	if len(entry.Unmanaged) != 1 || entry.Unmanaged[0] != "handwritten" {
+		t.Fatalf("expected handwritten to be unmanaged, got %v", entry.Unmanaged)
+	}
+}
+
+func TestPruneEmptyDirsStaysInsideStop(t *testing.T) {
+	root := t.TempDir()
+	stop := filepath.Join(root, "rules")
+	sibling := filepath.Join(root, "rules-old", "nested")
+	inside := filepath.Join(stop, "frontend", "nested")
+	for _, dir := range []string{sibling, inside} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	pruneEmptyDirs(sibling, stop)
+	if _, err := os.Stat(sibling); err != nil {
+		t.Fatalf("directory outside stop should be kept: %v", err)
+	}
+
+	pruneEmptyDirs(inside, stop)
+	assertNotExists(t, filepath.Join(stop, "frontend"))
+	if _, err := os.Stat(stop); err != nil {
+		t.Fatalf("stop directory should be kept: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	errpkg "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/manifest"
+)
+
+// ManifestValidateRequest describes a manifest validation request.
+type ManifestValidateRequest struct {
+	// Name is a package name (optionally "source:name") or a path to a package directory.
+	Name       string
+	PackageDir string
+	ConfigPath string
+}
+
+// ManifestValidateResponse reports the outcome of validating a package manifest.
+type ManifestValidateResponse struct {
+	Package string           `json:"package"`
+	Path    string           `json:"path"`
+	Found   bool             `json:"found"`
+	Version string           `json:"version,omitempty"`
+	Issues  []manifest.Issue `json:"issues"`
+}
+
+// Valid reports whether the manifest has no issues.
+func (r *ManifestValidateResponse) Valid() bool {
+	return r != nil && len(r.Issues) == 0
+}
+
+// ValidateManifest checks a package's cursor-rules-manifest.yaml against the manifest schema
+// and the registered targets.
+func (a *App) ValidateManifest(req ManifestValidateRequest) (*ManifestValidateResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errpkg.New(errpkg.CodeInvalidArgument, "missing package name")
+	}
+	pkgPath, err := a.resolveManifestPackage(name, strings.TrimSpace(req.PackageDir), req.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ManifestValidateResponse{
+		Package: name,
+		Path:    manifest.Path(pkgPath),
+		Issues:  []manifest.Issue{},
+	}
+	if _, err := os.Stat(resp.Path); err != nil {
+		if os.IsNotExist(err) {
+			return resp, nil
+		}
+		return nil, errpkg.Wrapf(err, errpkg.CodeInternal, "stat %s", resp.Path)
+	}
+	resp.Found = true
+
+	m, err := manifest.LoadAndValidate(pkgPath, a.resourceRegistry().targets())
+	if err == nil {
+		resp.Version = m.SchemaVersion()
+	} else {
+		var verr *manifest.ValidationError
+		if !errors.As(err, &verr) {
+			return nil, errpkg.Wrapf(err, errpkg.CodeInternal, "read %s", resp.Path)
+		}
+		resp.Issues = verr.Issues
+	}
+	return resp, nil
+}
+
+// resolveManifestPackage finds the directory of the package named name. Existing directory
+// paths are used as-is so authors can validate a package before publishing it.
+func (a *App) resolveManifestPackage(name, packageDir, configPath string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) || strings.HasPrefix(name, ".") {
+		if info, err := os.Stat(name); err == nil && info.IsDir() {
+			return filepath.Abs(name)
+		}
+	}
+	if packageDir == "" {
+		cfg, _, err := a.LoadConfig(configPath)
+		if err != nil {
+			return "", errpkg.Wrapf(err, errpkg.CodeInternal, "load config")
+		}
+		src, bare, err := a.resolveSource(a.PackageSources(cfg), name, "", cfg)
+		if err != nil {
+			return "", err
+		}
+		packageDir, name = src.Path, bare
+	}
+	if pkgPath, ok := packagePath(packageDir, name); ok {
+		return pkgPath, nil
+	}
+	return "", errpkg.Newf(errpkg.CodeNotFound, "package not found: %s", name)
+}
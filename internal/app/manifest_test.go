@@ -0,0 +1,66 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestValidateManifestReportsIssues(t *testing.T) {
+	packageDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	pkgDir := filepath.Join(packageDir, "frontend")
+	testutil.CreateTestFile(t, pkgDir, "component.mdc", "---\ndescription: \"Component\"\n---\nBody.")
+	testutil.CreateTestManifest(t, pkgDir, "version: \"1.0\"\ntargets:\n  - cursr\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.ValidateManifest(ManifestValidateRequest{Name: "frontend"})
+	if err != nil {
+		t.Fatalf("ValidateManifest failed: %v", err)
+	}
+	if !resp.Found || resp.Valid() || len(resp.Issues) != 1 {
+		t.Fatalf("expected one issue, got %+v", resp)
+	}
+	if got := resp.Issues[0].String(); !strings.HasSuffix(got, `:3:5: unknown target "cursr" (known: `+strings.Join(a.resourceRegistry().targets(), ", ")+")") {
+		t.Fatalf("unexpected issue: %s", got)
+	}
+
+	testutil.CreateTestManifest(t, pkgDir, "targets:\n  - cursor\n")
+	resp, err = a.ValidateManifest(ManifestValidateRequest{Name: pkgDir})
+	if err != nil {
+		t.Fatalf("ValidateManifest by path failed: %v", err)
+	}
+	if !resp.Valid() || resp.Version != "1.0" {
+		t.Fatalf("expected valid manifest with default version, got %+v", resp)
+	}
+
+	if _, err := a.ValidateManifest(ManifestValidateRequest{Name: "missing"}); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Fatalf("expected not found for missing package, got %v", err)
+	}
+}
+
+func TestInstallFailsOnInvalidManifest(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	pkgDir := filepath.Join(packageDir, "frontend")
+	testutil.CreateTestFile(t, pkgDir, "component.mdc", "---\ndescription: \"Component\"\n---\nBody.")
+	testutil.CreateTestManifest(t, pkgDir, "version: \"1.0\"\nexclud:\n  - draft.mdc\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	_, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir})
+	if apperrors.CodeOf(err) != apperrors.CodeInvalidArgument {
+		t.Fatalf("expected invalid argument, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `:2:1: unknown key "exclud"`) {
+		t.Fatalf("error should locate the typo, got %v", err)
+	}
+}
@@ -1,11 +1,291 @@
 package app
 
-// PolicyResponse captures policy output.
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/policy"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// Policy violation rule identifiers.
+const (
+	PolicyRuleRequiredPreset      = "required-preset"
+	PolicyRuleForbiddenPreset     = "forbidden-preset"
+	PolicyRuleRequiredFrontmatter = "required-frontmatter"
+	PolicyRuleInvalidFrontmatter  = "invalid-frontmatter"
+	PolicyRuleMaxBodySize         = "max-body-size"
+)
+
+// PolicyRequest describes a policy evaluation request.
+type PolicyRequest struct {
+	PolicyPath string
+	Workdir    string
+	ConfigPath string
+}
+
+// PolicyViolation is a single policy failure.
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Target  string `json:"target,omitempty"`
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// PolicyResponse captures policy evaluation output.
 type PolicyResponse struct {
-	Message string
+	PolicyPath string            `json:"policyPath"`
+	Workdir    string            `json:"workdir"`
+	Targets    []string          `json:"targets"`
+	Checked    int               `json:"checked"`
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// Passed reports whether the evaluation found no violations.
+func (r *PolicyResponse) Passed() bool {
+	return r != nil && len(r.Violations) == 0
+}
+
+// Policy evaluates the policy file against the installed rules of a project.
+func (a *App) Policy(req PolicyRequest) (*PolicyResponse, error) {
+	cfg, _, err := a.LoadConfig(req.ConfigPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	packageDir := a.ResolvePackageDir(cfg)
+
+	policyPath := strings.TrimSpace(req.PolicyPath)
+	if policyPath == "" {
+		policyPath = policy.DefaultPath(packageDir)
+	}
+	if _, statErr := os.Stat(policyPath); os.IsNotExist(statErr) {
+		return nil, errors.Newf(errors.CodeNotFound, "policy file not found: %s", policyPath)
+	}
+	pol, err := policy.Load(policyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "parse policy %s", policyPath)
+	}
+
+	providers, err := a.policyProviders(pol)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PolicyResponse{
+		PolicyPath: policyPath,
+		Workdir:    wd,
+		Violations: []PolicyViolation{},
+	}
+	installedByTarget := make(map[string]map[string]struct{}, len(providers))
+	for _, provider := range providers {
+		target := provider.Target()
+		resp.Targets = append(resp.Targets, target)
+		transformer, err := a.transformer(target)
+		if err != nil {
+			return nil, err
+		}
+		installed, err := provider.ListInstalled(wd, cfg, false)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "list installed rules for %s", target)
+		}
+		set := make(map[string]struct{}, len(installed))
+		outDir := provider.OutputDir(wd, cfg, false)
+		for _, name := range installed {
+			set[name] = struct{}{}
+			resp.Checked++
+			path := filepath.Join(outDir, name+transformer.Extension())
+			resp.Violations = append(resp.Violations, checkRuleFile(pol, wd, target, name, path)...)
+		}
+		installedByTarget[target] = set
+	}
+
+	for _, name := range pol.RequiredPresets {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if requiredPresetInstalled(packageDir, name, installedByTarget) {
+			continue
+		}
+		resp.Violations = append(resp.Violations, PolicyViolation{
+			Rule:    PolicyRuleRequiredPreset,
+			Name:    name,
+			Message: fmt.Sprintf("required preset %q is not installed in any of: %s", name, strings.Join(resp.Targets, ", ")),
+		})
+	}
+	for _, name := range pol.ForbiddenPresets {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		for _, provider := range providers {
+			if _, ok := installedByTarget[provider.Target()][name]; !ok {
+				continue
+			}
+			resp.Violations = append(resp.Violations, PolicyViolation{
+				Rule:    PolicyRuleForbiddenPreset,
+				Target:  provider.Target(),
+				Name:    name,
+				Path:    provider.OutputDir(wd, cfg, false),
+				Message: fmt.Sprintf("forbidden preset %q is installed", name),
+			})
+		}
+	}
+
+	sort.SliceStable(resp.Violations, func(i, j int) bool {
+		vi, vj := resp.Violations[i], resp.Violations[j]
+		if vi.Rule != vj.Rule {
+			return vi.Rule < vj.Rule
+		}
+		if vi.Target != vj.Target {
+			return vi.Target < vj.Target
+		}
+		return vi.Name < vj.Name
+	})
+	return resp, nil
+}
+
+func (a *App) policyProviders(pol *policy.Policy) ([]nativeResourceProvider, error) {
+	registry := a.resourceRegistry()
+	for _, target := range pol.Targets {
+		provider, ok := registry.providerForTarget(target)
+		if !ok || provider.Kind() != resourceKindRule {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "policy references unknown rule target: %s", target)
+		}
+	}
+	var out []nativeResourceProvider
+	for _, provider := range registry.providersForKind(resourceKindRule) {
+		if pol.AppliesToTarget(provider.Target()) {
+			out = append(out, provider)
+		}
+	}
+	return out, nil
+}
+
+// checkRuleFile checks the installed rule at path. Symlinks are followed and "@file" install
+// stubs are resolved, so the preset the agent reads is checked rather than the stub.
+func checkRuleFile(pol *policy.Policy, projectRoot, target, name, path string) []PolicyViolation {
+	rule, err := core.ReadEffectiveRule(path)
+	stub := err == nil && rule.Frontmatter == nil && rule.Resolved != ""
+	if stub {
+		err = core.ResolveReferences(&rule, projectRoot)
+	}
+	if err != nil {
+		return []PolicyViolation{{
+			Rule:    PolicyRuleInvalidFrontmatter,
+			Target:  target,
+			Name:    name,
+			Path:    path,
+			Message: fmt.Sprintf("read rule: %v", err),
+		}}
+	}
+
+	var out []PolicyViolation
+	body, fm := rule.Body, rule.Frontmatter
+	var splitErr error
+	if !stub {
+		fm = nil
+		body = rule.Content
+		node, parsedBody, err := transform.SplitFrontmatter([]byte(rule.Content))
+		splitErr = err
+		if splitErr == nil {
+			body = parsedBody
+			if decodeErr := node.Decode(&fm); decodeErr != nil {
+				splitErr = decodeErr
+			}
+		}
+	}
+	if splitErr != nil && len(pol.RequiredFrontmatter) > 0 {
+		out = append(out, PolicyViolation{
+			Rule:    PolicyRuleInvalidFrontmatter,
+			Target:  target,
+			Name:    name,
+			Path:    path,
+			Message: fmt.Sprintf("frontmatter cannot be parsed: %v", splitErr),
+		})
+	} else if splitErr == nil {
+		for _, key := range pol.RequiredFrontmatter {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			if _, ok := fm[key]; ok {
+				continue
+			}
+			out = append(out, PolicyViolation{
+				Rule:    PolicyRuleRequiredFrontmatter,
+				Target:  target,
+				Name:    name,
+				Path:    path,
+				Message: fmt.Sprintf("missing required frontmatter field %q", key),
+			})
+		}
+	}
+
+	if limit := pol.MaxBodyBytesFor(target); limit > 0 && len(body) > limit {
+		out = append(out, PolicyViolation{
+			Rule:    PolicyRuleMaxBodySize,
+			Target:  target,
+			Name:    name,
+			Path:    path,
+			Message: fmt.Sprintf("body is %d bytes, limit for %s is %d", len(body), target, limit),
+		})
+	}
+	return out
+}
+
+// requiredPresetInstalled reports whether name is installed in any target, either as a
+// single rule file or as a package whose rule files were all flattened into the target.
+func requiredPresetInstalled(packageDir, name string, installedByTarget map[string]map[string]struct{}) bool {
+	for _, installed := range installedByTarget {
+		if _, ok := installed[name]; ok {
+			return true
+		}
+	}
+	files := packageRuleBaseNames(packageDir, name)
+	if len(files) == 0 {
+		return false
+	}
+	for _, installed := range installedByTarget {
+		all := true
+		for _, file := range files {
+			if _, ok := installed[file]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
 }
 
-// Policy returns a placeholder response.
-func (a *App) Policy() *PolicyResponse {
-	return &PolicyResponse{Message: "policy command not yet implemented"}
+func packageRuleBaseNames(packageDir, name string) []string {
+	tree, err := core.BuildRulesTree(packageDir)
+	if err != nil || tree == nil {
+		return nil
+	}
+	for _, pkg := range tree.Packages {
+		if pkg.Name != name {
+			continue
+		}
+		out := make([]string, 0, len(pkg.Files))
+		for _, file := range pkg.Files {
+			base := filepath.Base(file)
+			out = append(out, strings.TrimSuffix(base, filepath.Ext(base)))
+		}
+		return out
+	}
+	return nil
 }
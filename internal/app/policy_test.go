@@ -0,0 +1,186 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func writePolicyFixture(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	return path
+}
+
+func TestPolicyReportsViolations(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	writePolicyFixture(t, packageDir, `version: "1"
+requiredPresets: [git, security]
+forbiddenPresets: [legacy]
+requiredFrontmatter: [description]
+maxBodyBytes:
+  cursor: 10
+`)
+	rulesDir := filepath.Join(projectDir, ".cursor", "rules")
+	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	files := map[string]string{
+		"git.mdc":    "---\ndescription: git\n---\nshort",
+		"legacy.mdc": "---\nalwaysApply: true\n---\nthis body is far too long",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(rulesDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.Policy(PolicyRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Policy failed: %v", err)
+	}
+	if resp.Passed() {
+		t.Fatal("expected violations")
+	}
+	if resp.Checked != 2 {
+		t.Errorf("Checked = %d, want 2", resp.Checked)
+	}
+
+	got := map[string]string{}
+	for _, v := range resp.Violations {
+		got[v.Rule] = v.Name
+	}
+	want := map[string]string{
+		PolicyRuleRequiredPreset:      "security",
+		PolicyRuleForbiddenPreset:     "legacy",
+		PolicyRuleRequiredFrontmatter: "legacy",
+		PolicyRuleMaxBodySize:         "legacy",
+	}
+	for rule, name := range want {
+		if got[rule] != name {
+			t.Errorf("violation %s: got %q, want %q (all: %+v)", rule, got[rule], name, resp.Violations)
+		}
+	}
+}
+
+func TestPolicyRequiredPackageSatisfiedByFlattenedFiles(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	pkgDir := filepath.Join(packageDir, "frontend")
+	rulesDir := filepath.Join(projectDir, ".cursor", "rules")
+	for _, dir := range []string{pkgDir, rulesDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	for _, name := range []string{"react.mdc", "css.mdc"} {
+		content := "---\ndescription: x\n---\nbody"
+		if err := os.WriteFile(filepath.Join(pkgDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(rulesDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	policyPath := writePolicyFixture(t, t.TempDir(), "requiredPresets: [frontend]\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.Policy(PolicyRequest{Workdir: projectDir, PolicyPath: policyPath})
+	if err != nil {
+		t.Fatalf("Policy failed: %v", err)
+	}
+	if !resp.Passed() {
+		t.Fatalf("expected no violations, got %+v", resp.Violations)
+	}
+}
+
+func TestPolicyMissingFile(t *testing.T) {
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", t.TempDir())
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	_, err := a.Policy(PolicyRequest{Workdir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "policy file not found") {
+		t.Fatalf("expected missing policy error, got %v", err)
+	}
+}
+
+func TestPolicyUnknownTarget(t *testing.T) {
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", t.TempDir())
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+	policyPath := writePolicyFixture(t, t.TempDir(), "targets: [nope]\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Policy(PolicyRequest{Workdir: t.TempDir(), PolicyPath: policyPath}); err == nil {
+		t.Fatal("expected unknown target error")
+	}
+}
+
+func TestPolicyChecksStubAndSymlinkSources(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	rulesDir := filepath.Join(projectDir, ".cursor", "rules")
+	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	sources := map[string]string{
+		"git.mdc":      "---\ndescription: git\n---\nthis body is far too long",
+		"security.mdc": "---\nalwaysApply: true\n---\nshort",
+	}
+	for name, content := range sources {
+		if err := os.WriteFile(filepath.Join(packageDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	stub := "---\n@file " + filepath.Join(packageDir, "git.mdc") + "\n"
+	if err := os.WriteFile(filepath.Join(rulesDir, "git.mdc"), []byte(stub), 0o644); err != nil {
+		t.Fatalf("write stub: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(packageDir, "security.mdc"), filepath.Join(rulesDir, "security.mdc")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	policyPath := writePolicyFixture(t, t.TempDir(), `requiredFrontmatter: [description]
+maxBodyBytes:
+  cursor: 10
+`)
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.Policy(PolicyRequest{Workdir: projectDir, PolicyPath: policyPath})
+	if err != nil {
+		t.Fatalf("Policy failed: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, v := range resp.Violations {
+		got[v.Rule] = v.Name
+	}
+	want := map[string]string{
+		PolicyRuleRequiredFrontmatter: "security",
+		PolicyRuleMaxBodySize:         "git",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %+v, want %v", resp.Violations, want)
+	}
+	for rule, name := range want {
+		if got[rule] != name {
+			t.Errorf("violation %s: got %q, want %q (all: %+v)", rule, got[rule], name, resp.Violations)
+		}
+	}
+}
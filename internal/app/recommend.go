@@ -0,0 +1,297 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// Recommendation is a package resource whose metadata matches the project profile.
+type Recommendation struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Label  string `json:"label"`
+	// Matches lists the profile terms (languages, frameworks, ...) the resource matched.
+	Matches []string `json:"matches"`
+
+	packageDir string
+	source     string
+}
+
+// RecommendRequest describes a preset recommendation request.
+type RecommendRequest struct {
+	Workdir    string
+	PackageDir string
+}
+
+// RecommendResponse lists the resources recommended for a project.
+type RecommendResponse struct {
+	Workdir         string
+	Profile         *core.ProjectProfile
+	Recommendations []Recommendation
+}
+
+// InstallRecommendationsRequest installs a (possibly filtered) set of recommendations.
+type InstallRecommendationsRequest struct {
+	Workdir         string
+	Recommendations []Recommendation
+}
+
+// termAliases maps common spellings in preset metadata to profile terms.
+var termAliases = map[string]string{
+	"golang": "go", "ts": "typescript", "js": "javascript", "py": "python", "nodejs": "node",
+	"node.js": "node", "next": "nextjs", "next.js": "nextjs", "reactjs": "react", "vuejs": "vue",
+	"github": "github-actions", "gha": "github-actions", "gitlab": "gitlab-ci",
+}
+
+// Recommend profiles the project and returns the rules, commands, skills, agents and hooks of
+// the configured package sources whose name, tags or keywords match the profile. Rules whose
+// applicability conditions do not match the project are never recommended.
+func (a *App) Recommend(req RecommendRequest) (*RecommendResponse, error) {
+	cfg, _, err := a.LoadConfig("")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := core.ProfileProject(wd)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "profile project")
+	}
+	resp := &RecommendResponse{Workdir: wd, Profile: profile}
+
+	sources := a.PackageSources(cfg)
+	if packageDir := strings.TrimSpace(req.PackageDir); packageDir != "" {
+		sources = []config.PackageSource{{Name: config.DefaultSourceName, Path: packageDir}}
+	}
+	// A fresh machine may have no package directory yet; there is nothing to recommend then.
+	available := sources[:0:0]
+	for _, src := range sources {
+		if info, statErr := os.Stat(src.Path); statErr == nil && info.IsDir() {
+			available = append(available, src)
+		}
+	}
+	if len(available) == 0 {
+		return resp, nil
+	}
+	entries, err := a.planInstallAllFromSources(available, cfg, "")
+	if err != nil {
+		return nil, err
+	}
+
+	facts := projectFacts{Root: wd, Type: profile.Type, Languages: profile.Languages, Frameworks: profile.Frameworks}
+	terms := profileTerms(profile)
+	registry := a.resourceRegistry()
+	for _, entry := range entries {
+		provider, ok := registry.providerForTarget(entry.Target)
+		if !ok {
+			continue
+		}
+		kind := provider.Kind()
+		if kind == resourceKindRule {
+			reason, err := presetSkipReason(entry.PackageDir, entry.Name, facts)
+			if err != nil {
+				return nil, err
+			}
+			if reason != "" {
+				continue
+			}
+		}
+		keywords, err := resourceKeywords(kind, entry.PackageDir, entry.Name, cfg)
+		if err != nil {
+			return nil, err
+		}
+		var matches []string
+		for _, keyword := range keywords {
+			if slices.Contains(terms, keyword) && !slices.Contains(matches, keyword) {
+				matches = append(matches, keyword)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		slices.Sort(matches)
+		resp.Recommendations = append(resp.Recommendations, Recommendation{
+			Kind:       kind,
+			Name:       entry.Name,
+			Target:     entry.Target,
+			Label:      entry.Label,
+			Matches:    matches,
+			packageDir: entry.PackageDir,
+			source:     entry.Source,
+		})
+	}
+	return resp, nil
+}
+
+// InstallRecommendations installs recommendations returned by Recommend.
+func (a *App) InstallRecommendations(req InstallRecommendationsRequest) ([]InstallResult, error) {
+	cfg, _, err := a.LoadConfig("")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := projectTemplate(wd, false)
+	if err != nil {
+		return nil, err
+	}
+	var results []InstallResult
+	for _, rec := range req.Recommendations {
+		installed, err := a.installInternal(&installInternalRequest{
+			Workdir:      wd,
+			PackageDir:   rec.packageDir,
+			Source:       rec.source,
+			Name:         rec.Name,
+			Target:       rec.Target,
+			SkillsSubdir: cfg.SkillsSubdir,
+			AgentsSubdir: cfg.AgentsSubdir,
+			HooksSubdir:  cfg.HooksSubdir,
+			Template:     tmpl,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeOf(err), "install %s", rec.Label)
+		}
+		results = append(results, installed...)
+	}
+	return results, nil
+}
+
+// profileTerms returns the terms of the profile and its workspaces that resource metadata is
+// matched against.
+func profileTerms(p *core.ProjectProfile) []string {
+	var terms []string
+	add := func(values ...string) {
+		for _, v := range values {
+			if v != "" && v != "unknown" && !slices.Contains(terms, v) {
+				terms = append(terms, v)
+			}
+		}
+	}
+	var walk func(p *core.ProjectProfile)
+	walk = func(p *core.ProjectProfile) {
+		add(p.Type)
+		if base, ok := strings.CutSuffix(p.Type, "-monorepo"); ok {
+			add(base, "monorepo")
+		}
+		if p.Monorepo {
+			add("monorepo")
+		}
+		add(p.Languages...)
+		add(p.Frameworks...)
+		add(p.TestFrameworks...)
+		add(p.CI...)
+		for _, ws := range p.Workspaces {
+			walk(ws)
+		}
+	}
+	walk(p)
+	return terms
+}
+
+// resourceKeywords returns the normalized name tokens, tags and keywords of a resource.
+func resourceKeywords(kind, packageDir, name string, cfg *config.Config) ([]string, error) {
+	var raw []string
+	raw = append(raw, name)
+	raw = append(raw, strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' || r == '/' })...)
+	for _, path := range resourceMetadataFiles(kind, packageDir, name, cfg) {
+		tags, err := frontmatterKeywords(path)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, tags...)
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if alias, ok := termAliases[v]; ok {
+			v = alias
+		}
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// resourceMetadataFiles returns the files whose frontmatter describes a resource. Hook presets
+// have no frontmatter and are matched by name only.
+func resourceMetadataFiles(kind, packageDir, name string, cfg *config.Config) []string {
+	var candidates []string
+	switch kind {
+	case resourceKindRule:
+		if pkgPath, ok := packagePath(packageDir, name); ok {
+			matches, _ := filepath.Glob(filepath.Join(pkgPath, "*.mdc"))
+			candidates = append(candidates, matches...)
+		} else {
+			candidates = append(candidates, filepath.Join(core.ResolveRulesPackageDir(packageDir), name+".mdc"))
+		}
+	case resourceKindCommand:
+		dir := filepath.Join(packageDir, core.CommandsSubdir())
+		candidates = append(candidates,
+			filepath.Join(dir, name+".md"),
+			filepath.Join(dir, name+".command.mdc"),
+			filepath.Join(dir, name, name+".md"),
+		)
+	case resourceKindSkill:
+		candidates = append(candidates, filepath.Join(packageDir, core.SkillsSubdir(cfg.SkillsSubdir), name, "SKILL.md"))
+	case resourceKindAgent:
+		candidates = append(candidates, filepath.Join(packageDir, core.ResolveAgentsSubdir(packageDir, cfg.AgentsSubdir), name+".md"))
+	}
+	var out []string
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// frontmatterKeywords reads the `tags` and `keywords` frontmatter fields of path. Both accept a
+// list or a comma-separated string.
+func frontmatterKeywords(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "read %s", path)
+	}
+	node, _, err := transform.SplitFrontmatter(data)
+	if err != nil {
+		return nil, nil
+	}
+	var fm struct {
+		Tags     yaml.Node `yaml:"tags"`
+		Keywords yaml.Node `yaml:"keywords"`
+	}
+	if err := node.Decode(&fm); err != nil {
+		return nil, nil
+	}
+	return append(stringOrList(&fm.Tags), stringOrList(&fm.Keywords)...), nil
+}
+
+func stringOrList(n *yaml.Node) []string {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return strings.Split(n.Value, ",")
+	case yaml.SequenceNode:
+		out := make([]string, 0, len(n.Content))
+		for _, item := range n.Content {
+			if item.Kind == yaml.ScalarNode {
+				out = append(out, item.Value)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestRecommendMatchesProjectProfile(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "golang.mdc", "---\ndescription: \"Go\"\n---\nGo rules")
+	testutil.CreateTestFile(t, packageDir, "api-style.mdc", "---\ndescription: \"HTTP APIs\"\ntags: [gin, rest]\n---\nAPI rules")
+	testutil.CreateTestFile(t, packageDir, "frontend.mdc", "---\ndescription: \"UI\"\nkeywords: \"react, nextjs\"\n---\nUI rules")
+	testutil.CreateTestFile(t, packageDir, "gated.mdc", "---\ndescription: \"Gated\"\ntags: [go]\nwhen:\n  files: [Dockerfile]\n---\nGated")
+	testutil.CreateTestFile(t, packageDir, filepath.Join("commands", "review.md"), "---\ndescription: Review\ntags: [github-actions]\n---\nReview the diff")
+	testutil.CreateTestFile(t, packageDir, filepath.Join("skills", "gotest", "SKILL.md"), "---\nname: gotest\ndescription: Go tests\nkeywords: [testify]\n---\n# Go tests")
+
+	testutil.CreateTestFile(t, projectDir, "go.mod", "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+	testutil.CreateTestFile(t, projectDir, filepath.Join(".github", "workflows", "ci.yml"), "on: push\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.Recommend(RecommendRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	got := map[string][]string{}
+	for _, rec := range resp.Recommendations {
+		got[rec.Kind+" "+rec.Name] = rec.Matches
+	}
+	want := map[string][]string{
+		"rule golang":    {"go"},
+		"rule api-style": {"gin"},
+		"command review": {"github-actions"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("recommendations = %v, want %v", got, want)
+	}
+	for key, matches := range want {
+		if !slices.Equal(got[key], matches) {
+			t.Errorf("%s matches = %v, want %v", key, got[key], matches)
+		}
+	}
+
+	results, err := a.InstallRecommendations(InstallRecommendationsRequest{Workdir: projectDir, Recommendations: resp.Recommendations})
+	if err != nil {
+		t.Fatalf("InstallRecommendations failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 install results, got %+v", results)
+	}
+	for _, path := range []string{
+		filepath.Join(".cursor", "rules", "golang.mdc"),
+		filepath.Join(".cursor", "rules", "api-style.mdc"),
+		filepath.Join(".cursor", "skills", "review"),
+	} {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err != nil {
+			t.Errorf("expected %s to be installed: %v", path, err)
+		}
+	}
+}
+
+func TestRecommendWithoutPackageDir(t *testing.T) {
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+	projectDir := t.TempDir()
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	resp, err := a.Recommend(RecommendRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if resp.Profile == nil || len(resp.Recommendations) != 0 {
+		t.Fatalf("expected a profile and no recommendations, got %+v", resp)
+	}
+}
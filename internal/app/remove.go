@@ -1,10 +1,12 @@
 package app
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
 )
 
 // RemoveRequest describes a remove request.
@@ -23,12 +25,15 @@ type RemoveMatch struct {
 	Name    string
 	Path    string
 	Removed bool
+	// Managed reports whether the removed resource was recorded in the project lockfile.
+	Managed bool
 }
 
 // RemoveResponse captures remove results.
 type RemoveResponse struct {
 	Name    string
 	Workdir string
+	Global  bool
 	Matches []RemoveMatch
 }
 
@@ -49,6 +54,7 @@ func (a *App) Remove(req RemoveRequest) (*RemoveResponse, error) {
 	resp := &RemoveResponse{
 		Name:    req.Name,
 		Workdir: wd,
+		Global:  req.Global,
 	}
 
 	if req.Type != "" && req.Target != "" {
@@ -67,15 +73,29 @@ func (a *App) Remove(req RemoveRequest) (*RemoveResponse, error) {
 	}
 
 	trimmedName := strings.TrimSpace(req.Name)
+	if trimmedName == "" && req.Target == "" {
+		// Without a name, a type-wide remove only covers unnamed resources such as Cursor hooks.
+		if unnamed := slices.DeleteFunc(slices.Clone(providers), nativeResourceProvider.RequiresName); len(unnamed) > 0 {
+			providers = unnamed
+		}
+	}
 	for _, provider := range providers {
 		if provider.RequiresName() && trimmedName == "" {
 			return nil, errors.Newf(errors.CodeInvalidArgument, "name required for remove target %s", provider.Target())
 		}
 	}
 
+	var lock *lockfile.Lock
+	if !req.Global {
+		lock, err = lockfile.Load(wd)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "read lockfile")
+		}
+	}
+
 	if req.Target != "" {
 		provider := providers[0]
-		removed, err := provider.Remove(wd, req.Name, cfg, req.Global)
+		removed, managed, err := removeResource(provider, wd, req.Name, cfg, req.Global, lock)
 		if err != nil {
 			return nil, err
 		}
@@ -85,11 +105,12 @@ func (a *App) Remove(req RemoveRequest) (*RemoveResponse, error) {
 			Name:    req.Name,
 			Path:    provider.OutputDir(wd, cfg, req.Global),
 			Removed: removed,
+			Managed: managed,
 		})
 		return resp, nil
 	}
 
-	matches, err := a.findInstalledRemoveMatches(providers, wd, trimmedName, cfg, req.Global)
+	matches, err := a.findInstalledRemoveMatches(providers, wd, trimmedName, cfg, req.Global, lock)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +122,7 @@ func (a *App) Remove(req RemoveRequest) (*RemoveResponse, error) {
 	}
 
 	match := matches[0]
-	removed, err := match.provider.Remove(wd, req.Name, cfg, req.Global)
+	removed, managed, err := removeResource(match.provider, wd, req.Name, cfg, req.Global, lock)
 	if err != nil {
 		return nil, err
 	}
@@ -111,10 +132,38 @@ func (a *App) Remove(req RemoveRequest) (*RemoveResponse, error) {
 		Name:    req.Name,
 		Path:    match.provider.OutputDir(wd, cfg, req.Global),
 		Removed: removed,
+		Managed: managed,
 	})
 	return resp, nil
 }
 
+// removeResource removes name via the provider. Resources recorded in the lockfile but not
+// visible to the provider (e.g. flattened packages) are removed using their recorded files.
+func removeResource(provider nativeResourceProvider, projectRoot, name string, cfg *config.Config, isUser bool, lock *lockfile.Lock) (removed, managed bool, err error) {
+	removed, err = provider.Remove(projectRoot, name, cfg, isUser)
+	if err != nil {
+		return false, false, err
+	}
+	if isUser {
+		return removed, false, nil
+	}
+	if !removed {
+		entry, ok := lock.Find(provider.Target(), strings.TrimSpace(name))
+		if !ok {
+			return false, false, nil
+		}
+		if err := removeLockedFiles(provider, projectRoot, provider.OutputDir(projectRoot, cfg, false), entry); err != nil {
+			return false, false, errors.Wrapf(err, errors.CodeInternal, "remove files recorded for %q", name)
+		}
+		removed = true
+	}
+	managed, err = recordRemove(provider, projectRoot, name)
+	if err != nil {
+		return false, false, err
+	}
+	return removed, managed, nil
+}
+
 func (a *App) removeProviders(req RemoveRequest) ([]nativeResourceProvider, error) {
 	registry := a.resourceRegistry()
 	if target := strings.TrimSpace(req.Target); target != "" {
@@ -138,7 +187,7 @@ type installedRemoveMatch struct {
 	provider nativeResourceProvider
 }
 
-func (a *App) findInstalledRemoveMatches(providers []nativeResourceProvider, projectRoot, name string, cfg *config.Config, isUser bool) ([]installedRemoveMatch, error) {
+func (a *App) findInstalledRemoveMatches(providers []nativeResourceProvider, projectRoot, name string, cfg *config.Config, isUser bool, lock *lockfile.Lock) ([]installedRemoveMatch, error) {
 	trimmedName := strings.TrimSpace(name)
 	matches := make([]installedRemoveMatch, 0, len(providers))
 	for _, provider := range providers {
@@ -160,6 +209,10 @@ func (a *App) findInstalledRemoveMatches(providers []nativeResourceProvider, pro
 		}
 		if containsInstalledResource(installed, trimmedName) {
 			matches = append(matches, installedRemoveMatch{provider: provider})
+			continue
+		}
+		if _, ok := lock.Find(provider.Target(), trimmedName); ok {
+			matches = append(matches, installedRemoveMatch{provider: provider})
 		}
 	}
 	return matches, nil
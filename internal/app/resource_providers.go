@@ -2,6 +2,7 @@ package app
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"sort"
@@ -10,7 +11,9 @@ import (
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/security"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
 
 const (
@@ -24,7 +27,13 @@ const (
 type nativeResourceInstallOptions struct {
 	Excludes  []string
 	NoFlatten bool
-	IsUser    bool // when true, use UserCursor* dirs (CURSOR_USER_DIR, per-feature overrides)
+	IsUser    bool   // when true, use UserCursor* dirs (CURSOR_USER_DIR, per-feature overrides)
+	Source    string // package source name, recorded in the lockfile
+	// Template renders placeholders in rules for the destination project. It is resolved from
+	// the real project root so scratch renders (status) produce identical output.
+	Template *transform.Template
+	// Written, when set, collects the paths the install wrote, for the lockfile.
+	Written *core.Written
 }
 
 type nativeResourceInstallAllPlan struct {
@@ -56,16 +65,32 @@ type nativeResourceRegistry struct {
 func newNativeResourceRegistry(transformerProvider TransformerProvider) *nativeResourceRegistry {
 	providers := []nativeResourceProvider{
 		commandResourceProvider{target: "commands"},
-		commandResourceProvider{target: "opencode-commands", opencode: true},
+		commandResourceProvider{target: "opencode-commands", flavor: flavorOpenCode},
+		commandResourceProvider{target: "claude-commands", flavor: flavorClaude},
+		commandResourceProvider{target: "gemini-commands", flavor: flavorGemini},
 		skillResourceProvider{target: "skills"},
-		skillResourceProvider{target: "opencode-skills", opencode: true},
+		skillResourceProvider{target: "opencode-skills", flavor: flavorOpenCode},
+		skillResourceProvider{target: "claude-skills", flavor: flavorClaude},
 		agentResourceProvider{target: "agents"},
-		agentResourceProvider{target: "opencode-agents", opencode: true},
-		hooksResourceProvider{},
+		agentResourceProvider{target: "opencode-agents", flavor: flavorOpenCode},
+		agentResourceProvider{target: "claude-agents", flavor: flavorClaude},
+		hooksResourceProvider{target: "hooks"},
+		claudeHooksResourceProvider{hooksResourceProvider{target: "claude-hooks", flavor: flavorClaude}},
 	}
 	if transformerProvider != nil {
 		for _, target := range orderedRuleTargets(transformerProvider.AvailableTargets()) {
-			providers = append(providers, rulesResourceProvider{target: target, tp: transformerProvider})
+			rules := rulesResourceProvider{target: target, tp: transformerProvider}
+			if transformer, err := transformerProvider.Transformer(target); err == nil {
+				if _, ok := transformer.(transform.Composer); ok {
+					providers = append(providers, composedRulesResourceProvider{rules})
+					continue
+				}
+				if _, ok := transformer.(transform.Router); ok {
+					providers = append(providers, routedRulesResourceProvider{rules})
+					continue
+				}
+			}
+			providers = append(providers, rules)
 		}
 	}
 	registry := &nativeResourceRegistry{
@@ -110,6 +135,18 @@ func (r *nativeResourceRegistry) providers() []nativeResourceProvider {
 	return r.ordered
 }
 
+// targets returns every registered target name in registration order.
+func (r *nativeResourceRegistry) targets() []string {
+	if r == nil {
+		return nil
+	}
+	out := make([]string, 0, len(r.ordered))
+	for _, provider := range r.ordered {
+		out = append(out, provider.Target())
+	}
+	return out
+}
+
 func (r *nativeResourceRegistry) uniqueKindProviders() []nativeResourceProvider {
 	if r == nil {
 		return nil
@@ -150,7 +187,7 @@ func containsInstalledResource(installed []string, name string) bool {
 	if slices.Contains(installed, name) {
 		return true
 	}
-	if slices.Contains(installed, name+".md") {
+	if slices.Contains(installed, name+".md") || slices.Contains(installed, name+".toml") {
 		return true
 	}
 	return false
@@ -167,7 +204,7 @@ func orderedRuleTargets(targets []string) []string {
 	}
 
 	ordered := make([]string, 0, len(seen))
-	for _, target := range []string{"cursor", "copilot-instr", "copilot-prompt", "opencode-rules"} {
+	for _, target := range []string{"cursor", "copilot-instr", "copilot-prompt", "opencode-rules", "claude-rules", "windsurf", "cline-rules", "roo-rules"} {
 		if _, ok := seen[target]; !ok {
 			continue
 		}
@@ -195,16 +232,32 @@ func installAllPlansToEntries(provider nativeResourceProvider, plans []nativeRes
 	return entries
 }
 
+// nativeFlavor selects the tool whose native layout a command, skill, agent or hooks provider
+// writes.
+type nativeFlavor int
+
+const (
+	flavorCursor nativeFlavor = iota
+	flavorOpenCode
+	flavorClaude
+	flavorGemini
+)
+
 type commandResourceProvider struct {
-	target   string
-	opencode bool
+	target string
+	flavor nativeFlavor
 }
 
 func (p commandResourceProvider) Kind() string   { return resourceKindCommand }
 func (p commandResourceProvider) Target() string { return p.target }
 func (p commandResourceProvider) OutputDir(projectRoot string, cfg *config.Config, isUser bool) string {
-	if p.opencode {
+	switch p.flavor {
+	case flavorOpenCode:
 		return config.EffectiveOpenCodeCommandsDir(projectRoot, isUser)
+	case flavorClaude:
+		return config.EffectiveClaudeCommandsDir(projectRoot, isUser)
+	case flavorGemini:
+		return config.EffectiveGeminiCommandsDir(projectRoot, isUser)
 	}
 	return config.EffectiveSkillsDir(projectRoot, isUser, cfg)
 }
@@ -216,9 +269,11 @@ func (commandResourceProvider) ListAvailable(packageDir string, _ *config.Config
 }
 
 func (p commandResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
-	if p.opencode {
-		commandsDir := config.EffectiveOpenCodeCommandsDir(projectRoot, isUser)
-		return core.ListInstalledCommands(commandsDir)
+	if p.flavor == flavorGemini {
+		return core.ListInstalledGeminiCommands(p.OutputDir(projectRoot, cfg, isUser))
+	}
+	if p.flavor != flavorCursor {
+		return core.ListInstalledCommands(p.OutputDir(projectRoot, cfg, isUser))
 	}
 	legacyCommandsDir := config.EffectiveCommandsDir(projectRoot, isUser, cfg)
 	skillsDir := config.EffectiveSkillsDir(projectRoot, isUser, cfg)
@@ -252,18 +307,32 @@ func (p commandResourceProvider) ListInstalled(projectRoot string, cfg *config.C
 }
 
 func (p commandResourceProvider) Install(projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
-	if p.opencode {
+	all := strings.TrimSpace(name) == core.CommandsSubdir()
+	switch p.flavor {
+	case flavorOpenCode:
 		commandsDir := config.EffectiveOpenCodeCommandsDir(projectRoot, opts.IsUser)
-		if strings.TrimSpace(name) == core.CommandsSubdir() {
-			return core.InstallOpenCodeCommandCollectionToDir(commandsDir, packageDir, opts.Excludes)
+		if all {
+			return core.InstallOpenCodeCommandCollectionToDir(commandsDir, packageDir, opts.Excludes, opts.Written)
+		}
+		return core.InstallOpenCodeCommandToDir(commandsDir, packageDir, name, opts.Excludes, opts.Written)
+	case flavorClaude:
+		commandsDir := config.EffectiveClaudeCommandsDir(projectRoot, opts.IsUser)
+		if all {
+			return core.InstallClaudeCommandCollectionToDir(commandsDir, packageDir, opts.Excludes, opts.Written)
 		}
-		return core.InstallOpenCodeCommandToDir(commandsDir, packageDir, name, opts.Excludes)
+		return core.InstallClaudeCommandToDir(commandsDir, packageDir, name, opts.Excludes, opts.Written)
+	case flavorGemini:
+		commandsDir := config.EffectiveGeminiCommandsDir(projectRoot, opts.IsUser)
+		if all {
+			return core.InstallGeminiCommandCollectionToDir(commandsDir, packageDir, opts.Excludes, opts.Written)
+		}
+		return core.InstallGeminiCommandToDir(commandsDir, packageDir, name, opts.Excludes, opts.Written)
 	}
 	skillsDir := config.EffectiveSkillsDir(projectRoot, opts.IsUser, cfg)
-	if strings.TrimSpace(name) == core.CommandsSubdir() {
-		return core.InstallCommandCollectionAsSkillsToDir(skillsDir, packageDir, opts.Excludes)
+	if all {
+		return core.InstallCommandCollectionAsSkillsToDir(skillsDir, packageDir, opts.Excludes, opts.Written)
 	}
-	return core.InstallCommandAsSkillToDir(skillsDir, packageDir, name, opts.Excludes)
+	return core.InstallCommandAsSkillToDir(skillsDir, packageDir, name, opts.Excludes, opts.Written)
 }
 func (commandResourceProvider) PlanInstallAll(packageDir string, _ *config.Config) ([]nativeResourceInstallAllPlan, error) {
 	names, err := core.ListCursorCompatibleCommands(packageDir)
@@ -279,9 +348,9 @@ func (commandResourceProvider) PlanInstallAll(packageDir string, _ *config.Confi
 	}
 	return plans, nil
 }
-func (p commandResourceProvider) IncludeInDefaultInstallAll() bool { return !p.opencode }
+func (p commandResourceProvider) IncludeInDefaultInstallAll() bool { return p.flavor == flavorCursor }
 func (p commandResourceProvider) DetectDefaultTarget(packageDir, name string, _ *config.Config) (target string, ok bool, err error) {
-	if p.opencode {
+	if p.flavor != flavorCursor {
 		return "", false, nil
 	}
 	if strings.TrimSpace(name) != core.CommandsSubdir() {
@@ -308,8 +377,11 @@ func (p commandResourceProvider) Remove(projectRoot, name string, cfg *config.Co
 		return false, err
 	}
 	return removeFromInstalledList(name, installed, func() error {
-		if p.opencode {
-			return core.RemoveCommand(config.EffectiveOpenCodeCommandsDir(projectRoot, isUser), name)
+		if p.flavor == flavorGemini {
+			return core.RemoveGeminiCommand(p.OutputDir(projectRoot, cfg, isUser), name)
+		}
+		if p.flavor != flavorCursor {
+			return core.RemoveCommand(p.OutputDir(projectRoot, cfg, isUser), name)
 		}
 		skillsDir := config.EffectiveSkillsDir(projectRoot, isUser, cfg)
 		commandsDir := config.EffectiveCommandsDir(projectRoot, isUser, cfg)
@@ -321,15 +393,18 @@ func (p commandResourceProvider) Remove(projectRoot, name string, cfg *config.Co
 }
 
 type skillResourceProvider struct {
-	target   string
-	opencode bool
+	target string
+	flavor nativeFlavor
 }
 
 func (p skillResourceProvider) Kind() string   { return resourceKindSkill }
 func (p skillResourceProvider) Target() string { return p.target }
 func (p skillResourceProvider) OutputDir(projectRoot string, cfg *config.Config, isUser bool) string {
-	if p.opencode {
+	switch p.flavor {
+	case flavorOpenCode:
 		return config.EffectiveOpenCodeSkillsDir(projectRoot, isUser)
+	case flavorClaude:
+		return config.EffectiveClaudeSkillsDir(projectRoot, isUser)
 	}
 	return config.EffectiveSkillsDir(projectRoot, isUser, cfg)
 }
@@ -340,21 +415,13 @@ func (skillResourceProvider) ListAvailable(packageDir string, cfg *config.Config
 	return core.ListSkillDirs(packageDir, cfg.SkillsSubdir)
 }
 func (p skillResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
-	skillsDir := config.EffectiveSkillsDir(projectRoot, isUser, cfg)
-	if p.opencode {
-		skillsDir = config.EffectiveOpenCodeSkillsDir(projectRoot, isUser)
-	}
-	return core.ListSkillDirsFrom(skillsDir)
+	return core.ListSkillDirsFrom(p.OutputDir(projectRoot, cfg, isUser))
 }
 func (p skillResourceProvider) Install(projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
-	skillsDir := config.EffectiveSkillsDir(projectRoot, opts.IsUser, cfg)
-	if p.opencode {
-		skillsDir = config.EffectiveOpenCodeSkillsDir(projectRoot, opts.IsUser)
-	}
 	if strings.TrimSpace(name) == core.SkillsSubdir(cfg.SkillsSubdir) {
-		return installAllFromProviderWithDir(p, projectRoot, packageDir, cfg, opts.IsUser)
+		return installAllFromProviderWithDir(p, projectRoot, packageDir, cfg, opts)
 	}
-	return core.InstallSkillToDir(skillsDir, packageDir, name, cfg.SkillsSubdir)
+	return core.InstallSkillToDir(p.OutputDir(projectRoot, cfg, opts.IsUser), packageDir, name, cfg.SkillsSubdir, opts.Written)
 }
 func (skillResourceProvider) PlanInstallAll(packageDir string, cfg *config.Config) ([]nativeResourceInstallAllPlan, error) {
 	names, err := core.ListSkillDirs(packageDir, cfg.SkillsSubdir)
@@ -370,9 +437,9 @@ func (skillResourceProvider) PlanInstallAll(packageDir string, cfg *config.Confi
 	}
 	return plans, nil
 }
-func (p skillResourceProvider) IncludeInDefaultInstallAll() bool { return !p.opencode }
+func (p skillResourceProvider) IncludeInDefaultInstallAll() bool { return p.flavor == flavorCursor }
 func (p skillResourceProvider) DetectDefaultTarget(packageDir, name string, cfg *config.Config) (target string, ok bool, err error) {
-	if p.opencode {
+	if p.flavor != flavorCursor {
 		return "", false, nil
 	}
 	if strings.TrimSpace(name) != core.SkillsSubdir(cfg.SkillsSubdir) {
@@ -390,24 +457,23 @@ func (p skillResourceProvider) Remove(projectRoot, name string, cfg *config.Conf
 		return false, err
 	}
 	return removeFromInstalledList(name, installed, func() error {
-		skillsDir := config.EffectiveSkillsDir(projectRoot, isUser, cfg)
-		if p.opencode {
-			skillsDir = config.EffectiveOpenCodeSkillsDir(projectRoot, isUser)
-		}
-		return core.RemoveSkill(skillsDir, name)
+		return core.RemoveSkill(p.OutputDir(projectRoot, cfg, isUser), name)
 	})
 }
 
 type agentResourceProvider struct {
-	target   string
-	opencode bool
+	target string
+	flavor nativeFlavor
 }
 
 func (p agentResourceProvider) Kind() string   { return resourceKindAgent }
 func (p agentResourceProvider) Target() string { return p.target }
 func (p agentResourceProvider) OutputDir(projectRoot string, cfg *config.Config, isUser bool) string {
-	if p.opencode {
+	switch p.flavor {
+	case flavorOpenCode:
 		return config.EffectiveOpenCodeAgentsDir(projectRoot, isUser)
+	case flavorClaude:
+		return config.EffectiveClaudeAgentsDir(projectRoot, isUser)
 	}
 	return config.EffectiveAgentsDir(projectRoot, isUser, cfg)
 }
@@ -418,26 +484,22 @@ func (agentResourceProvider) ListAvailable(packageDir string, cfg *config.Config
 	return core.ListAgentFiles(packageDir, cfg.AgentsSubdir)
 }
 func (p agentResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
-	agentsDir := config.EffectiveAgentsDir(projectRoot, isUser, cfg)
-	if p.opencode {
-		agentsDir = config.EffectiveOpenCodeAgentsDir(projectRoot, isUser)
-	}
-	return core.ListAgentFilesFrom(agentsDir)
+	return core.ListAgentFilesFrom(p.OutputDir(projectRoot, cfg, isUser))
 }
 func (p agentResourceProvider) Install(projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
-	agentsDir := config.EffectiveAgentsDir(projectRoot, opts.IsUser, cfg)
-	if p.opencode {
-		agentsDir = config.EffectiveOpenCodeAgentsDir(projectRoot, opts.IsUser)
-	}
 	if strings.TrimSpace(name) == core.AgentsSubdir(cfg.AgentsSubdir) {
-		return installAllFromProviderWithDir(p, projectRoot, packageDir, cfg, opts.IsUser)
+		return installAllFromProviderWithDir(p, projectRoot, packageDir, cfg, opts)
 	}
 	subdir := core.ResolveAgentsSubdir(packageDir, cfg.AgentsSubdir)
 	agentsRoot, err := security.SafeJoin(packageDir, subdir)
 	if err != nil {
 		return core.StrategyUnknown, err
 	}
-	return core.InstallAgentToDir(agentsDir, agentsRoot, name, ".md")
+	agentsDir := p.OutputDir(projectRoot, cfg, opts.IsUser)
+	if p.flavor == flavorClaude {
+		return core.InstallClaudeAgentToDir(agentsDir, agentsRoot, name, opts.Written)
+	}
+	return core.InstallAgentToDir(agentsDir, agentsRoot, name, ".md", opts.Written)
 }
 func (agentResourceProvider) PlanInstallAll(packageDir string, cfg *config.Config) ([]nativeResourceInstallAllPlan, error) {
 	names, err := core.ListAgentFiles(packageDir, cfg.AgentsSubdir)
@@ -453,9 +515,9 @@ func (agentResourceProvider) PlanInstallAll(packageDir string, cfg *config.Confi
 	}
 	return plans, nil
 }
-func (p agentResourceProvider) IncludeInDefaultInstallAll() bool { return !p.opencode }
+func (p agentResourceProvider) IncludeInDefaultInstallAll() bool { return p.flavor == flavorCursor }
 func (p agentResourceProvider) DetectDefaultTarget(packageDir, name string, cfg *config.Config) (target string, ok bool, err error) {
-	if p.opencode {
+	if p.flavor != flavorCursor {
 		return "", false, nil
 	}
 	if strings.TrimSpace(name) != core.AgentsSubdir(cfg.AgentsSubdir) {
@@ -473,36 +535,52 @@ func (p agentResourceProvider) Remove(projectRoot, name string, cfg *config.Conf
 		return false, err
 	}
 	return removeFromInstalledList(name, installed, func() error {
-		agentsDir := config.EffectiveAgentsDir(projectRoot, isUser, cfg)
-		if p.opencode {
-			agentsDir = config.EffectiveOpenCodeAgentsDir(projectRoot, isUser)
-		}
-		return core.RemoveAgent(agentsDir, name)
+		return core.RemoveAgent(p.OutputDir(projectRoot, cfg, isUser), name)
 	})
 }
 
-type hooksResourceProvider struct{}
+type hooksResourceProvider struct {
+	target string
+	flavor nativeFlavor
+}
 
-func (hooksResourceProvider) Kind() string   { return resourceKindHooks }
-func (hooksResourceProvider) Target() string { return "hooks" }
-func (hooksResourceProvider) OutputDir(projectRoot string, cfg *config.Config, isUser bool) string {
+func (hooksResourceProvider) Kind() string     { return resourceKindHooks }
+func (p hooksResourceProvider) Target() string { return p.target }
+func (p hooksResourceProvider) OutputDir(projectRoot string, cfg *config.Config, isUser bool) string {
+	if p.flavor == flavorClaude {
+		return config.EffectiveClaudeHooksDir(projectRoot, isUser)
+	}
 	return config.EffectiveHooksDir(projectRoot, isUser, cfg)
 }
-func (hooksResourceProvider) RequiresName() bool {
-	return false
+func (p hooksResourceProvider) RequiresName() bool {
+	// Claude Code hook presets are merged into settings.json side by side, each with its own
+	// lock entry; Cursor hooks replace hooks.json as a whole.
+	return p.flavor == flavorClaude
 }
 func (hooksResourceProvider) ListAvailable(packageDir string, cfg *config.Config) ([]string, error) {
 	return core.ListHookPresets(packageDir, cfg.HooksSubdir)
 }
-func (hooksResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
-	hooksDir := config.EffectiveHooksDir(projectRoot, isUser, cfg)
-	jsonPath := config.EffectiveHooksJSON(projectRoot, isUser, cfg)
-	return core.ListInstalledHooksFrom(hooksDir, jsonPath)
+func (p hooksResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
+	hooksDir := p.OutputDir(projectRoot, cfg, isUser)
+	if p.flavor == flavorClaude {
+		return core.ListInstalledClaudeHooksFrom(hooksDir)
+	}
+	return core.ListInstalledHooksFrom(hooksDir, config.EffectiveHooksJSON(projectRoot, isUser, cfg))
 }
-func (hooksResourceProvider) Install(projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
-	hooksDir := config.EffectiveHooksDir(projectRoot, opts.IsUser, cfg)
+func (p hooksResourceProvider) Install(projectRoot, packageDir, name string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
+	hooksDir := p.OutputDir(projectRoot, cfg, opts.IsUser)
+	if p.flavor == flavorClaude {
+		// Claude Code runs project hooks from any directory inside the project, so project
+		// scripts are addressed through $CLAUDE_PROJECT_DIR; user scripts by absolute path.
+		commandDir := hooksDir
+		if !opts.IsUser {
+			commandDir = `"$CLAUDE_PROJECT_DIR"/.claude/hooks`
+		}
+		settingsPath := config.EffectiveClaudeSettingsJSON(projectRoot, opts.IsUser)
+		return core.InstallClaudeHookPresetToDirs(hooksDir, settingsPath, commandDir, packageDir, name, cfg.HooksSubdir, opts.Written)
+	}
 	jsonPath := config.EffectiveHooksJSON(projectRoot, opts.IsUser, cfg)
-	return core.InstallHookPresetToDirs(hooksDir, jsonPath, packageDir, name, cfg.HooksSubdir)
+	return core.InstallHookPresetToDirs(hooksDir, jsonPath, packageDir, name, cfg.HooksSubdir, opts.Written)
 }
 func (hooksResourceProvider) PlanInstallAll(packageDir string, cfg *config.Config) ([]nativeResourceInstallAllPlan, error) {
 	names, err := core.ListHookPresets(packageDir, cfg.HooksSubdir)
@@ -518,19 +596,39 @@ func (hooksResourceProvider) PlanInstallAll(packageDir string, cfg *config.Confi
 	}
 	return plans, nil
 }
-func (hooksResourceProvider) IncludeInDefaultInstallAll() bool { return true }
+func (p hooksResourceProvider) IncludeInDefaultInstallAll() bool { return p.flavor == flavorCursor }
 func (hooksResourceProvider) DetectDefaultTarget(_, _ string, _ *config.Config) (target string, ok bool, err error) {
 	return "", false, nil
 }
-func (hooksResourceProvider) Remove(projectRoot, _ string, cfg *config.Config, isUser bool) (bool, error) {
-	installed, err := hooksResourceProvider{}.ListInstalled(projectRoot, cfg, isUser)
+func (p hooksResourceProvider) Remove(projectRoot, name string, cfg *config.Config, isUser bool) (bool, error) {
+	if p.flavor == flavorClaude {
+		hooksDir := p.OutputDir(projectRoot, cfg, isUser)
+		return core.RemoveClaudeHooksFromDirs(hooksDir, config.EffectiveClaudeSettingsJSON(projectRoot, isUser), strings.TrimSpace(name))
+	}
+	installed, err := p.ListInstalled(projectRoot, cfg, isUser)
 	if err != nil {
 		return false, err
 	}
 	if len(installed) == 0 {
 		return false, nil
 	}
-	return true, core.RemoveHookPresetFromDirs(config.EffectiveHooksDir(projectRoot, isUser, cfg), config.EffectiveHooksJSON(projectRoot, isUser, cfg))
+	hooksDir := p.OutputDir(projectRoot, cfg, isUser)
+	return true, core.RemoveHookPresetFromDirs(hooksDir, config.EffectiveHooksJSON(projectRoot, isUser, cfg))
+}
+
+// claudeHooksResourceProvider installs hook presets into .claude/settings.json, which also holds
+// other presets, hand-written hooks and unrelated settings; the lockfile tracks only the hook
+// groups each preset added.
+type claudeHooksResourceProvider struct {
+	hooksResourceProvider
+}
+
+func (claudeHooksResourceProvider) ownedContent(path, name string, data []byte) []byte {
+	return core.ClaudeHooksOwnedContent(path, name, data)
+}
+
+func (claudeHooksResourceProvider) removeOwnedContent(path, name string) error {
+	return core.RemoveClaudeHooksOwnedContent(path, name)
 }
 
 type rulesResourceProvider struct {
@@ -546,6 +644,8 @@ func (p rulesResourceProvider) OutputDir(projectRoot string, cfg *config.Config,
 		return config.EffectiveRulesDir(projectRoot, isUser, cfg)
 	case "opencode-rules":
 		return config.EffectiveOpenCodeRulesDir(projectRoot, isUser)
+	case "claude-rules":
+		return config.EffectiveClaudeRulesDir(projectRoot, isUser)
 	default:
 		if p.tp != nil {
 			if transformer, err := p.tp.Transformer(p.target); err == nil {
@@ -604,45 +704,51 @@ func (p rulesResourceProvider) Install(projectRoot, packageDir, name string, cfg
 	pkgPath := filepath.Join(rulesPackageDir, name)
 	info, statErr := os.Stat(pkgPath)
 	isPackage := statErr == nil && info.IsDir()
+	if isPackage {
+		if trans, err = packageTransformer(trans, pkgPath); err != nil {
+			return core.StrategyUnknown, err
+		}
+	}
+	trans = transform.WithTemplate(trans, opts.Template)
 
 	if p.target == "cursor" && opts.IsUser {
 		rulesDir := config.EffectiveRulesDir(projectRoot, true, cfg)
 		if isPackage {
 			if core.UseSymlink() || core.WantGNUStow() {
-				return core.InstallPackageToRulesDir(rulesDir, rulesPackageDir, name, opts.Excludes, opts.NoFlatten)
+				return core.InstallPackageToRulesDir(rulesDir, rulesPackageDir, name, opts.Excludes, opts.NoFlatten, opts.Written)
 			}
-			return installPackageWithTransformerToRulesDir(rulesDir, pkgPath, name, trans, opts.Excludes, opts.NoFlatten)
+			return installPackageWithTransformerToRulesDir(rulesDir, pkgPath, name, trans, opts.Excludes, opts.NoFlatten, opts.Written)
 		}
 		presetPath := filepath.Join(rulesPackageDir, name)
 		if !strings.HasSuffix(presetPath, ".mdc") {
 			presetPath += ".mdc"
 		}
-		return installPresetWithTransformerToRulesDir(rulesDir, presetPath, name, trans, rulesPackageDir)
+		return installPresetWithTransformerToRulesDir(rulesDir, presetPath, name, trans, rulesPackageDir, opts.Written)
 	}
 
-	if p.target == "opencode-rules" && opts.IsUser {
-		rulesDir := config.EffectiveOpenCodeRulesDir(projectRoot, true)
+	if (p.target == "opencode-rules" || p.target == "claude-rules") && opts.IsUser {
+		rulesDir := p.OutputDir(projectRoot, cfg, true)
 		if isPackage {
-			return installPackageWithTransformerToRulesDir(rulesDir, pkgPath, name, trans, opts.Excludes, opts.NoFlatten)
+			return installPackageWithTransformerToRulesDir(rulesDir, pkgPath, name, trans, opts.Excludes, opts.NoFlatten, opts.Written)
 		}
 		presetPath := filepath.Join(rulesPackageDir, name)
 		if !strings.HasSuffix(presetPath, ".mdc") {
 			presetPath += ".mdc"
 		}
-		return installPresetWithTransformerToRulesDir(rulesDir, presetPath, name, trans, rulesPackageDir)
+		return installPresetWithTransformerToRulesDir(rulesDir, presetPath, name, trans, rulesPackageDir, opts.Written)
 	}
 
 	if isPackage {
 		if trans.Target() == "cursor" && (core.UseSymlink() || core.WantGNUStow()) {
-			return core.InstallPackageFromPackageDir(projectRoot, rulesPackageDir, name, opts.Excludes, opts.NoFlatten)
+			return core.InstallPackageFromPackageDir(projectRoot, rulesPackageDir, name, opts.Excludes, opts.NoFlatten, opts.Written)
 		}
-		return installPackageWithTransformer(projectRoot, pkgPath, name, trans, opts.Excludes, opts.NoFlatten)
+		return installPackageWithTransformer(projectRoot, pkgPath, name, trans, opts.Excludes, opts.NoFlatten, opts.Written)
 	}
 	presetPath := filepath.Join(rulesPackageDir, name)
 	if !strings.HasSuffix(presetPath, ".mdc") {
 		presetPath += ".mdc"
 	}
-	return installPresetWithTransformer(projectRoot, presetPath, name, trans, rulesPackageDir)
+	return installPresetWithTransformer(projectRoot, presetPath, name, trans, rulesPackageDir, opts.Written)
 }
 
 func (p rulesResourceProvider) PlanInstallAll(packageDir string, _ *config.Config) ([]nativeResourceInstallAllPlan, error) {
@@ -710,6 +816,329 @@ func (p rulesResourceProvider) Remove(projectRoot, name string, cfg *config.Conf
 	})
 }
 
+// routedRulesResourceProvider installs rules for targets that route rules to directories next
+// to their output directory (see transform.Router), such as Roo Code's .roo/rules-<mode>.
+// Rules in the output directory are listed by name, routed ones as "<dir>/<name>" (for example
+// "rules-code/review"); removing a bare name removes it from every directory.
+type routedRulesResourceProvider struct {
+	rulesResourceProvider
+}
+
+func (p routedRulesResourceProvider) ListInstalled(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
+	out, err := p.rulesResourceProvider.ListInstalled(projectRoot, cfg, isUser)
+	if err != nil || p.tp == nil {
+		return out, err
+	}
+	transformer, err := p.tp.Transformer(p.target)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := p.routedDirs(projectRoot, cfg, isUser)
+	if err != nil {
+		return nil, err
+	}
+	parent := filepath.Dir(p.OutputDir(projectRoot, cfg, isUser))
+	for _, dir := range dirs {
+		names, err := listInstalledRuleFiles(filepath.Join(parent, dir), transformer.Extension())
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			out = append(out, dir+"/"+name)
+		}
+	}
+	return out, nil
+}
+
+func (p routedRulesResourceProvider) Remove(projectRoot, name string, cfg *config.Config, isUser bool) (bool, error) {
+	if p.tp == nil {
+		return false, nil
+	}
+	installed, err := p.ListInstalled(projectRoot, cfg, isUser)
+	if err != nil {
+		return false, err
+	}
+	transformer, err := p.tp.Transformer(p.target)
+	if err != nil {
+		return false, err
+	}
+	var items []string
+	for _, item := range installed {
+		if item == name || (!strings.Contains(name, "/") && path.Base(item) == name) {
+			items = append(items, item)
+		}
+	}
+	outputDir := p.OutputDir(projectRoot, cfg, isUser)
+	for _, item := range items {
+		dir := filepath.Dir(p.installedPath(outputDir, item))
+		if err := removeInstalledRuleFile(dir, path.Base(item), transformer.Extension()); err != nil {
+			return false, err
+		}
+		if dir != outputDir {
+			pruneEmptyDirs(dir, filepath.Dir(outputDir))
+		}
+	}
+	return len(items) > 0, nil
+}
+
+func (p routedRulesResourceProvider) itemPath(projectRoot, item string, cfg *config.Config) string {
+	return p.installedPath(p.OutputDir(projectRoot, cfg, false), item)
+}
+
+// installedPath returns the path, without extension, of an installed list item.
+func (p routedRulesResourceProvider) installedPath(outputDir, item string) string {
+	if strings.Contains(item, "/") {
+		return filepath.Join(filepath.Dir(outputDir), filepath.FromSlash(item))
+	}
+	return filepath.Join(outputDir, item)
+}
+
+// routedDirs returns the names of the "<output dir>-*" directories next to the output directory.
+func (p routedRulesResourceProvider) routedDirs(projectRoot string, cfg *config.Config, isUser bool) ([]string, error) {
+	outputDir := p.OutputDir(projectRoot, cfg, isUser)
+	entries, err := os.ReadDir(filepath.Dir(outputDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefix := filepath.Base(outputDir) + "-"
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) && security.ValidatePackageName(entry.Name()) == nil {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	return dirs, nil
+}
+
+// composedRulesResourceProvider installs rules for targets that compose every preset into one
+// shared file per directory (see transform.Composer). Each preset owns a managed section of
+// the files it is composed into, so hand-written text and other presets' sections survive
+// reinstalls and removals.
+type composedRulesResourceProvider struct {
+	rulesResourceProvider
+}
+
+func (p composedRulesResourceProvider) OutputDir(projectRoot string, _ *config.Config, _ bool) string {
+	return projectRoot
+}
+
+func (p composedRulesResourceProvider) ListInstalled(projectRoot string, _ *config.Config, isUser bool) ([]string, error) {
+	if isUser {
+		return nil, nil
+	}
+	files, err := p.composedFiles(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range core.ManagedSectionNames(data) {
+			if !seen[name] {
+				seen[name] = true
+				out = append(out, name)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (p composedRulesResourceProvider) Install(projectRoot, packageDir, name string, _ *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
+	if opts.IsUser {
+		return core.StrategyUnknown, errors.Newf(errors.CodeInvalidArgument, "%s has no user-level location; install it into a project", p.target)
+	}
+	if err := security.ValidatePackageName(name); err != nil {
+		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid resource name")
+	}
+	trans, err := p.tp.Transformer(p.target)
+	if err != nil {
+		return core.StrategyUnknown, err
+	}
+	rulesPackageDir := core.ResolveRulesPackageDir(packageDir)
+	pkgPath := filepath.Join(rulesPackageDir, name)
+	var sources []string
+	if info, statErr := os.Stat(pkgPath); statErr == nil && info.IsDir() {
+		if trans, err = packageTransformer(trans, pkgPath); err != nil {
+			return core.StrategyUnknown, err
+		}
+		if sources, err = packageRuleFiles(pkgPath, name, opts.Excludes); err != nil {
+			return core.StrategyUnknown, err
+		}
+	} else {
+		presetPath := pkgPath
+		if !strings.HasSuffix(presetPath, ".mdc") {
+			presetPath += ".mdc"
+		}
+		if _, err := os.Stat(presetPath); os.IsNotExist(err) {
+			return core.StrategyUnknown, errors.Newf(errors.CodeNotFound, "preset %q not found: %s", name, presetPath)
+		}
+		sources = []string{presetPath}
+	}
+	composer, ok := trans.(transform.Composer)
+	if !ok {
+		return core.StrategyUnknown, errors.Newf(errors.CodeInternal, "%s transformer does not compose rules", p.target)
+	}
+	sections, err := composeRuleSections(sources, transform.WithTemplate(trans, opts.Template), composer)
+	if err != nil {
+		return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "compose %q", name)
+	}
+
+	// Drop the preset from files it no longer belongs in, e.g. after its globs moved.
+	existing, err := p.composedFiles(projectRoot)
+	if err != nil {
+		return core.StrategyUnknown, err
+	}
+	for _, rel := range existing {
+		if _, keep := sections[composedFileDir(rel)]; keep {
+			continue
+		}
+		if _, err := core.RemoveManagedSection(filepath.Join(projectRoot, filepath.FromSlash(rel)), name); err != nil {
+			return core.StrategyUnknown, err
+		}
+	}
+	for dir, content := range sections {
+		dest, err := security.SafeJoin(projectRoot, filepath.FromSlash(path.Join(dir, composer.ComposedFile())))
+		if err != nil {
+			return core.StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid %s destination", composer.ComposedFile())
+		}
+		if err := core.UpsertManagedSection(dest, name, content); err != nil {
+			return core.StrategyUnknown, err
+		}
+		opts.Written.Add(dest)
+	}
+	return core.StrategyCopy, nil
+}
+
+func (p composedRulesResourceProvider) Remove(projectRoot, name string, _ *config.Config, isUser bool) (bool, error) {
+	if isUser {
+		return false, nil
+	}
+	files, err := p.composedFiles(projectRoot)
+	if err != nil {
+		return false, err
+	}
+	removed := false
+	for _, rel := range files {
+		ok, err := core.RemoveManagedSection(filepath.Join(projectRoot, filepath.FromSlash(rel)), name)
+		if err != nil {
+			return false, err
+		}
+		removed = removed || ok
+	}
+	return removed, nil
+}
+
+func (p composedRulesResourceProvider) ownedContent(_, name string, data []byte) []byte {
+	section, _ := core.ManagedSection(data, name)
+	return []byte(section)
+}
+
+func (p composedRulesResourceProvider) removeOwnedContent(path, name string) error {
+	_, err := core.RemoveManagedSection(path, name)
+	return err
+}
+
+// composedFiles returns the project-relative paths of every composed file in the project.
+func (p composedRulesResourceProvider) composedFiles(projectRoot string) ([]string, error) {
+	if p.tp == nil {
+		return nil, nil
+	}
+	trans, err := p.tp.Transformer(p.target)
+	if err != nil {
+		return nil, err
+	}
+	composer, ok := trans.(transform.Composer)
+	if !ok {
+		return nil, nil
+	}
+	files, err := glob.Glob(projectRoot, "**/"+composer.ComposedFile())
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "find %s files", composer.ComposedFile())
+	}
+	return files, nil
+}
+
+// composedFileDir returns the directory of a project-relative composed file, "" for the root.
+func composedFileDir(rel string) string {
+	if dir := path.Dir(rel); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// composeRuleSections transforms each rule file and joins the bodies by the directory the
+// composer places them in.
+func composeRuleSections(files []string, trans transform.Transformer, composer transform.Composer) (map[string]string, error) {
+	sections := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "read %s", file)
+		}
+		frontmatter, body, err := transform.SplitFrontmatter(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "parse %s", file)
+		}
+		transformedFM, transformedBody, err := trans.Transform(frontmatter, body)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "transform %s", file)
+		}
+		if err := trans.Validate(transformedFM); err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "validate %s", file)
+		}
+		dir, err := composer.ComposeDir(frontmatter)
+		if err != nil {
+			return nil, err
+		}
+		transformedBody = strings.TrimSpace(transformedBody)
+		if transformedBody == "" {
+			continue
+		}
+		if existing := sections[dir]; existing != "" {
+			transformedBody = existing + "\n\n" + transformedBody
+		}
+		sections[dir] = transformedBody
+	}
+	return sections, nil
+}
+
+// packageRuleFiles returns the .mdc files of a package that are not excluded, in path order.
+func packageRuleFiles(pkgPath, name string, excludes []string) ([]string, error) {
+	excluded, err := glob.CompileSet(excludes)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid exclude pattern in package %q", name)
+	}
+	var files []string
+	err = filepath.Walk(pkgPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Wrapf(err, errors.CodeInternal, "walk package %q", name)
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".mdc") {
+			return nil
+		}
+		rel, err := filepath.Rel(pkgPath, p)
+		if err != nil {
+			return errors.Wrapf(err, errors.CodeInternal, "get relative path")
+		}
+		if !excluded.Matches(rel) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 func listInstalledRuleFiles(rulesDir, ext string) ([]string, error) {
 	entries, err := os.ReadDir(rulesDir)
 	if err != nil {
@@ -749,7 +1178,7 @@ func removeInstalledRuleFile(rulesDir, name, ext string) error {
 	return os.Remove(target)
 }
 
-func installAllFromProviderWithDir(provider nativeResourceProvider, projectRoot, packageDir string, cfg *config.Config, isUser bool) (core.InstallStrategy, error) {
+func installAllFromProviderWithDir(provider nativeResourceProvider, projectRoot, packageDir string, cfg *config.Config, opts nativeResourceInstallOptions) (core.InstallStrategy, error) {
 	plans, err := provider.PlanInstallAll(packageDir, cfg)
 	if err != nil {
 		return core.StrategyUnknown, err
@@ -760,7 +1189,7 @@ func installAllFromProviderWithDir(provider nativeResourceProvider, projectRoot,
 
 	usedStrategy := core.StrategyCopy
 	for _, plan := range plans {
-		strategy, err := provider.Install(projectRoot, packageDir, plan.Name, cfg, nativeResourceInstallOptions{IsUser: isUser})
+		strategy, err := provider.Install(projectRoot, packageDir, plan.Name, cfg, nativeResourceInstallOptions{IsUser: opts.IsUser, Written: opts.Written})
 		if err != nil {
 			return core.StrategyUnknown, err
 		}
@@ -3,10 +3,14 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
 
@@ -223,3 +227,235 @@ func TestHooksProviderPlanInstallAllReturnsPresets(t *testing.T) {
 		t.Errorf("hooks PlanInstallAll: want [format], got %+v", plans)
 	}
 }
+
+func TestClaudeNativeProvidersUseCanonicalOutputDirs(t *testing.T) {
+	projectRoot := t.TempDir()
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", userDir)
+	reg := newNativeResourceRegistry(staticTransformerProvider{"claude-rules": transform.NewClaudeRulesTransformer()})
+
+	tests := []struct {
+		target string
+		isUser bool
+		want   string
+	}{
+		{target: "claude-rules", want: filepath.Join(projectRoot, ".claude", "rules")},
+		{target: "claude-commands", want: filepath.Join(projectRoot, ".claude", "commands")},
+		{target: "claude-skills", want: filepath.Join(projectRoot, ".claude", "skills")},
+		{target: "claude-agents", want: filepath.Join(projectRoot, ".claude", "agents")},
+		{target: "claude-hooks", want: filepath.Join(projectRoot, ".claude", "hooks")},
+		{target: "claude-rules", isUser: true, want: filepath.Join(userDir, "rules")},
+		{target: "claude-agents", isUser: true, want: filepath.Join(userDir, "agents")},
+	}
+
+	for _, tt := range tests {
+		provider, ok := reg.providerForTarget(tt.target)
+		if !ok {
+			t.Fatalf("providerForTarget(%q): missing provider", tt.target)
+		}
+		if provider.IncludeInDefaultInstallAll() {
+			t.Errorf("%s must not be part of the default install all", tt.target)
+		}
+		if got := provider.OutputDir(projectRoot, &config.Config{}, tt.isUser); got != tt.want {
+			t.Fatalf("provider.OutputDir(%q, user=%v): want %q, got %q", tt.target, tt.isUser, tt.want, got)
+		}
+	}
+}
+
+func TestAgentsMDSectionsPreserveHandWrittenContent(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "go.mdc", "---\nalwaysApply: true\n---\nRun gofmt.")
+	webDir := filepath.Join(packageDir, "web")
+	testutil.CreateTestFile(t, webDir, "react.mdc", "---\nglobs: web/src/**/*.tsx\n---\nUse function components.")
+	testutil.CreateTestFile(t, webDir, "general.mdc", "---\nalwaysApply: true\n---\nPrefer pnpm.")
+	testutil.CreateTestManifest(t, webDir, "version: \"1.0\"\noverrides:\n  agents-md:\n    nested: true\n")
+
+	handWritten := "# Project notes\n\nKeep this.\n"
+	rootFile := filepath.Join(projectDir, "AGENTS.md")
+	writeInstalledRuleFile(t, rootFile, handWritten)
+
+	a := New(nil, staticProvider{"agents-md": transform.NewAgentsMDTransformer()})
+	for _, name := range []string{"go", "web", "go"} {
+		if _, err := a.Install(&InstallRequest{Name: name, Workdir: projectDir, Target: "agents-md"}); err != nil {
+			t.Fatalf("Install(%s) failed: %v", name, err)
+		}
+	}
+
+	root, err := os.ReadFile(rootFile)
+	if err != nil {
+		t.Fatalf("read AGENTS.md: %v", err)
+	}
+	want := handWritten + "\n" +
+		"<!-- cursor-rules:begin go -->\nRun gofmt.\n<!-- cursor-rules:end go -->\n\n" +
+		"<!-- cursor-rules:begin web -->\nPrefer pnpm.\n<!-- cursor-rules:end web -->\n"
+	if string(root) != want {
+		t.Fatalf("AGENTS.md:\n%s\nwant:\n%s", root, want)
+	}
+	nested, err := os.ReadFile(filepath.Join(projectDir, "web", "src", "AGENTS.md"))
+	if err != nil {
+		t.Fatalf("read nested AGENTS.md: %v", err)
+	}
+	if string(nested) != "<!-- cursor-rules:begin web -->\nUse function components.\n<!-- cursor-rules:end web -->\n" {
+		t.Fatalf("nested AGENTS.md:\n%s", nested)
+	}
+
+	provider, _ := a.resourceRegistry().providerForTarget("agents-md")
+	installed, err := provider.ListInstalled(projectDir, &config.Config{}, false)
+	if err != nil || len(installed) != 2 || installed[0] != "go" || installed[1] != "web" {
+		t.Fatalf("ListInstalled = %v, %v", installed, err)
+	}
+	status, err := a.Status(StatusRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for _, res := range status.Resources {
+		if res.State != StatusUpToDate {
+			t.Errorf("%s: state %q, want %q (files: %+v)", res.Name, res.State, StatusUpToDate, res.Files)
+		}
+	}
+
+	for _, name := range []string{"web", "go"} {
+		resp, err := a.Remove(RemoveRequest{Name: name, Target: "agents-md", Workdir: projectDir})
+		if err != nil || len(resp.Matches) != 1 || !resp.Matches[0].Removed {
+			t.Fatalf("Remove(%s) = %+v, %v", name, resp, err)
+		}
+	}
+	assertNotExists(t, filepath.Join(projectDir, "web", "src", "AGENTS.md"))
+	if root, _ := os.ReadFile(rootFile); string(root) != handWritten {
+		t.Fatalf("AGENTS.md after remove:\n%s", root)
+	}
+}
+
+func TestRooRulesModeDirectories(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "go.mdc", "---\nglobs: \"**/*.go\"\n---\nRun gofmt.")
+	backendDir := filepath.Join(packageDir, "backend")
+	testutil.CreateTestFile(t, backendDir, "review.mdc", "---\nrooMode: code\n---\nCheck errors.")
+	testutil.CreateTestFile(t, backendDir, "design.mdc", "---\nalwaysApply: true\n---\nDraw the data flow.")
+	testutil.CreateTestManifest(t, backendDir, "version: \"1.0\"\noverrides:\n  roo-rules:\n    mode: architect\n")
+
+	a := New(nil, staticProvider{"roo-rules": transform.NewRooRulesTransformer()})
+	for _, name := range []string{"go", "backend"} {
+		if _, err := a.Install(&InstallRequest{Name: name, Workdir: projectDir, Target: "roo-rules"}); err != nil {
+			t.Fatalf("Install(%s) failed: %v", name, err)
+		}
+	}
+	for rel, want := range map[string]string{
+		".roo/rules/go.md":               "Run gofmt.",
+		".roo/rules-code/review.md":      "Check errors.",
+		".roo/rules-architect/design.md": "Draw the data flow.",
+	} {
+		if data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(rel))); err != nil || string(data) != want {
+			t.Fatalf("%s = %q, %v; want %q", rel, data, err, want)
+		}
+	}
+
+	provider, _ := a.resourceRegistry().providerForTarget("roo-rules")
+	installed, err := provider.ListInstalled(projectDir, &config.Config{}, false)
+	want := []string{"go", "rules-architect/design", "rules-code/review"}
+	if err != nil || !slices.Equal(installed, want) {
+		t.Fatalf("ListInstalled = %v, %v; want %v", installed, err, want)
+	}
+	list, err := a.ListRules(ListRequest{Workdir: projectDir, Installed: true})
+	if err != nil {
+		t.Fatalf("ListRules failed: %v", err)
+	}
+	for _, target := range list.Targets {
+		if len(target.Unmanaged) != 0 {
+			t.Fatalf("%s: unexpected unmanaged items %v", target.Target, target.Unmanaged)
+		}
+	}
+
+	for _, name := range []string{"review", "backend"} {
+		resp, err := a.Remove(RemoveRequest{Name: name, Target: "roo-rules", Workdir: projectDir})
+		if err != nil || len(resp.Matches) != 1 || !resp.Matches[0].Removed {
+			t.Fatalf("Remove(%s) = %+v, %v", name, resp, err)
+		}
+	}
+	assertNotExists(t, filepath.Join(projectDir, ".roo", "rules-code"))
+	assertNotExists(t, filepath.Join(projectDir, ".roo", "rules-architect"))
+	if installed, _ := provider.ListInstalled(projectDir, &config.Config{}, false); !slices.Equal(installed, []string{"go"}) {
+		t.Fatalf("ListInstalled after remove = %v", installed)
+	}
+}
+
+func TestClaudeHooksRecordsEachPreset(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"hooks/one/hooks.json": `{"version":1,"hooks":{"stop":[{"command":"./one.sh"}]}}`,
+		"hooks/one/one.sh":     "#!/bin/sh\n",
+		"hooks/two/hooks.json": `{"version":1,"hooks":{"beforeShellExecution":[{"command":"./two.sh"}]}}`,
+		"hooks/two/two.sh":     "#!/bin/sh\n",
+	})
+	testutil.CreateTestFile(t, filepath.Join(projectDir, ".claude"), "settings.json", `{"model":"opus"}`)
+	a := New(nil, staticProvider{})
+	for _, name := range []string{"one", "two"} {
+		if _, err := a.Install(&InstallRequest{Name: name, Workdir: projectDir, Target: "claude-hooks"}); err != nil {
+			t.Fatalf("Install %s failed: %v", name, err)
+		}
+	}
+
+	lock, err := lockfile.Load(projectDir)
+	if err != nil {
+		t.Fatalf("load lockfile: %v", err)
+	}
+	for _, name := range []string{"one", "two"} {
+		if _, ok := lock.Find("claude-hooks", name); !ok {
+			t.Fatalf("%s not recorded: %+v", name, lock.Entries)
+		}
+	}
+
+	for _, f := range lock.Entries[0].Files {
+		if f.Path == ".claude/hooks/.cursor-rules.json" {
+			t.Fatalf("the managed hooks record is shared and must not be locked: %+v", lock.Entries[0])
+		}
+	}
+	// Other settings are not part of any preset, so editing them is not drift.
+	testutil.CreateTestFile(t, filepath.Join(projectDir, ".claude"), "settings.json",
+		strings.Replace(testutil.MustReadFile(t, filepath.Join(projectDir, ".claude", "settings.json")), `"opus"`, `"sonnet"`, 1))
+	status, err := a.Status(StatusRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for _, res := range status.Resources {
+		if res.State != StatusUpToDate {
+			t.Fatalf("%s should be up to date, got %+v", res.Name, res)
+		}
+	}
+
+	resp, err := a.Remove(RemoveRequest{Name: "one", Target: "claude-hooks", Workdir: projectDir})
+	if err != nil || len(resp.Matches) != 1 || !resp.Matches[0].Removed || !resp.Matches[0].Managed {
+		t.Fatalf("Remove(one) = %+v, %v", resp, err)
+	}
+	hooksDir := filepath.Join(projectDir, ".claude", "hooks")
+	assertNotExists(t, filepath.Join(hooksDir, "one.sh"))
+	assertExists(t, filepath.Join(hooksDir, "two.sh"))
+	lock, _ = lockfile.Load(projectDir)
+	if _, ok := lock.Find("claude-hooks", "one"); ok {
+		t.Fatalf("one still recorded: %+v", lock.Entries)
+	}
+	if _, ok := lock.Find("claude-hooks", "two"); !ok {
+		t.Fatalf("two should stay recorded: %+v", lock.Entries)
+	}
+	settings := testutil.MustReadFile(t, filepath.Join(projectDir, ".claude", "settings.json"))
+	if strings.Contains(settings, "one.sh") || !strings.Contains(settings, "two.sh") || !strings.Contains(settings, "sonnet") {
+		t.Fatalf("unexpected settings.json after removing one:\n%s", settings)
+	}
+
+	// Without the managed hooks record, removal falls back to the lockfile and must keep settings.json.
+	if err := os.Remove(filepath.Join(hooksDir, ".cursor-rules.json")); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := a.Remove(RemoveRequest{Name: "two", Target: "claude-hooks", Workdir: projectDir}); err != nil || !resp.Matches[0].Removed {
+		t.Fatalf("Remove(two) = %+v, %v", resp, err)
+	}
+	assertNotExists(t, filepath.Join(hooksDir, "two.sh"))
+	assertExists(t, filepath.Join(projectDir, ".claude", "settings.json"))
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+)
+
+// RestoreRequest describes a restore-from-lockfile request.
+type RestoreRequest struct {
+	Workdir string
+	// PackageDir overrides the package dir recorded in the lockfile.
+	PackageDir string
+}
+
+// RestoreResponse captures restore outcomes.
+type RestoreResponse struct {
+	Workdir  string
+	Lockfile string
+	Results  []InstallResult
+}
+
+type restorePlanItem struct {
+	entry      lockfile.Entry
+	provider   nativeResourceProvider
+	packageDir string
+}
+
+// Restore reinstalls every resource recorded in the project lockfile. All sources are checked
+// before anything is written, so a missing preset fails the restore without partial installs.
+func (a *App) Restore(req RestoreRequest) (*RestoreResponse, error) {
+	cfg, _, err := a.LoadConfig("")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	lockPath := lockfile.Path(wd)
+	if _, statErr := os.Stat(lockPath); os.IsNotExist(statErr) {
+		return nil, errors.Newf(errors.CodeNotFound, "no lockfile found at %s", lockPath)
+	}
+	lock, err := lockfile.Load(wd)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "parse lockfile %s", lockPath)
+	}
+
+	plan, err := a.planRestore(lock, cfg, strings.TrimSpace(req.PackageDir))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := projectTemplate(wd, false)
+	if err != nil {
+		return nil, err
+	}
+	resp := &RestoreResponse{Workdir: wd, Lockfile: lockPath}
+	for _, item := range plan {
+		strategy, warnings, err := installWithProvider(item.provider, wd, item.packageDir, item.entry.Name, cfg, nativeResourceInstallOptions{
+			Excludes:  item.entry.Excludes,
+			NoFlatten: item.entry.NoFlatten,
+			Source:    item.entry.Source,
+			Template:  tmpl,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, InstallResult{
+			Name:      item.entry.Name,
+			Target:    item.provider.Target(),
+			OutputDir: item.provider.OutputDir(wd, cfg, false),
+			Strategy:  strategy,
+			Warnings:  warnings,
+		})
+	}
+	return resp, nil
+}
+
+func (a *App) planRestore(lock *lockfile.Lock, cfg *config.Config, packageDirOverride string) ([]restorePlanItem, error) {
+	registry := a.resourceRegistry()
+	plan := make([]restorePlanItem, 0, len(lock.Entries))
+	var missing []string
+	for _, entry := range lock.Entries {
+		provider, ok := registry.providerForTarget(entry.Target)
+		if !ok {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "lockfile references unknown target: %s", entry.Target)
+		}
+		packageDir := a.restorePackageDir(entry, cfg, packageDirOverride)
+		available, err := restoreSourceAvailable(provider, packageDir, entry.Name, cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "check source for %s %q", entry.Target, entry.Name)
+		}
+		if !available {
+			missing = append(missing, fmt.Sprintf("%s %q (target %s) in %s", entry.Kind, entry.Name, entry.Target, packageDir))
+			continue
+		}
+		plan = append(plan, restorePlanItem{entry: entry, provider: provider, packageDir: packageDir})
+	}
+	if len(missing) > 0 {
+		return nil, errors.Newf(errors.CodeNotFound, "lockfile references sources missing from the package dir:\n  - %s", strings.Join(missing, "\n  - "))
+	}
+	return plan, nil
+}
+
+// restorePackageDir prefers an explicit override, then the local path of the recorded source,
+// then the configured package dir.
+func (a *App) restorePackageDir(entry lockfile.Entry, cfg *config.Config, override string) string {
+	if override != "" {
+		return override
+	}
+	if entry.Source != "" {
+		for _, src := range a.PackageSources(cfg) {
+			if src.Name == entry.Source {
+				return src.Path
+			}
+		}
+	}
+	return a.ResolvePackageDir(cfg)
+}
+
+func restoreSourceAvailable(provider nativeResourceProvider, packageDir, name string, cfg *config.Config) (bool, error) {
+	if info, err := os.Stat(packageDir); err != nil || !info.IsDir() {
+		return false, nil
+	}
+	switch provider.Kind() {
+	case resourceKindCommand:
+		if name == core.CommandsSubdir() {
+			return true, nil
+		}
+	case resourceKindSkill:
+		if name == core.SkillsSubdir(cfg.SkillsSubdir) {
+			return true, nil
+		}
+	case resourceKindAgent:
+		if name == core.AgentsSubdir(cfg.AgentsSubdir) {
+			return true, nil
+		}
+	}
+	available, err := provider.ListAvailable(packageDir, cfg)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(available, name), nil
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestRestoreReinstallsRecordedResources(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc":            testutil.Rule("description: git", "Use conventional commits."),
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{
+		"cursor":        transform.NewCursorTransformer(),
+		"copilot-instr": transform.NewCopilotInstructionsTransformer(),
+	})
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install git failed: %v", err)
+	}
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "copilot-instr"}); err != nil {
+		t.Fatalf("Install frontend failed: %v", err)
+	}
+
+	// Simulate a fresh clone: only the lockfile survives.
+	lockData, err := os.ReadFile(lockfile.Path(projectDir))
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	clone := t.TempDir()
+	if err := os.MkdirAll(filepath.Dir(lockfile.Path(clone)), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(lockfile.Path(clone), lockData, 0o644); err != nil {
+		t.Fatalf("write lockfile: %v", err)
+	}
+
+	resp, err := a.Restore(RestoreRequest{Workdir: clone})
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if len(resp.Results) != 2 {
+		t.Fatalf("expected 2 restored resources, got %+v", resp.Results)
+	}
+	assertExists(t, filepath.Join(clone, ".cursor", "rules", "git.mdc"))
+	assertExists(t, filepath.Join(clone, ".github", "instructions", "react.instructions.md"))
+	assertExists(t, filepath.Join(clone, ".github", "instructions", "css.instructions.md"))
+}
+
+func TestRestoreFailsWhenSourceMissing(t *testing.T) {
+	packageDir, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc":            testutil.Rule("description: git", "Use conventional commits."),
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install git failed: %v", err)
+	}
+	if _, err := a.Install(&InstallRequest{Name: "frontend", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install frontend failed: %v", err)
+	}
+	if err := os.Remove(filepath.Join(packageDir, "git.mdc")); err != nil {
+		t.Fatalf("remove preset: %v", err)
+	}
+	if err := os.RemoveAll(filepath.Join(projectDir, ".cursor", "rules")); err != nil {
+		t.Fatalf("remove rules: %v", err)
+	}
+
+	_, err := a.Restore(RestoreRequest{Workdir: projectDir})
+	if err == nil {
+		t.Fatal("expected missing source error")
+	}
+	if apperrors.CodeOf(err) != apperrors.CodeNotFound || !strings.Contains(err.Error(), `"git"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Nothing should be installed when any source is missing.
+	assertNotExists(t, filepath.Join(projectDir, ".cursor", "rules", "react.mdc"))
+}
+
+func TestRestoreWithoutLockfile(t *testing.T) {
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", t.TempDir())
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	_, err := a.Restore(RestoreRequest{Workdir: t.TempDir()})
+	if err == nil || apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
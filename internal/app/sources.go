@@ -0,0 +1,121 @@
+package app
+
+import (
+	"os"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// PackageSources returns the package sources in precedence order. CURSOR_RULES_PACKAGE_DIR
+// collapses them into a single default source; without configured sources the resolved
+// package directory is the only source.
+func (a *App) PackageSources(cfg *config.Config) []config.PackageSource {
+	if v := strings.TrimSpace(os.Getenv(config.EnvPackageDir)); v != "" {
+		return []config.PackageSource{{Name: config.DefaultSourceName, Path: v}}
+	}
+	if cfg != nil && len(cfg.Sources) > 0 {
+		return append([]config.PackageSource(nil), cfg.Sources...)
+	}
+	return []config.PackageSource{{Name: config.DefaultSourceName, Path: a.ResolvePackageDir(cfg)}}
+}
+
+// splitSourceName splits a "source:name" reference. Unqualified names return an empty source.
+func splitSourceName(ref string) (source, name string) {
+	ref = strings.TrimSpace(ref)
+	if i := strings.Index(ref, ":"); i > 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return "", ref
+}
+
+// qualifySourceName prefixes name with its source when more than one source is configured,
+// so listings stay unambiguous.
+func qualifySourceName(sources []config.PackageSource, source, name string) string {
+	if len(sources) < 2 {
+		return name
+	}
+	return source + ":" + name
+}
+
+func qualifySourceNames(sources []config.PackageSource, source string, names []string) []string {
+	if len(sources) < 2 {
+		return names
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		out = append(out, source+":"+name)
+	}
+	return out
+}
+
+// resolveSource picks the source that provides ref for target. A qualified ref selects its
+// source explicitly; otherwise the first source providing the name wins. When no source has it,
+// the highest-precedence source is returned so callers report their usual not-found errors.
+func (a *App) resolveSource(sources []config.PackageSource, ref, target string, cfg *config.Config) (config.PackageSource, string, error) {
+	sourceName, name := splitSourceName(ref)
+	if len(sources) == 0 {
+		return config.PackageSource{}, name, errors.New(errors.CodeFailedPrecondition, "no package sources configured")
+	}
+	if sourceName != "" {
+		for _, src := range sources {
+			if src.Name == sourceName {
+				return src, name, checkSourceSynced(src)
+			}
+		}
+		return config.PackageSource{}, name, errors.Newf(errors.CodeNotFound, "unknown package source %q in %q", sourceName, ref)
+	}
+	if len(sources) == 1 {
+		return sources[0], name, checkSourceSynced(sources[0])
+	}
+	for _, src := range sources {
+		if a.sourceProvides(src.Path, name, target, cfg) {
+			return src, name, nil
+		}
+	}
+	return sources[0], name, checkSourceSynced(sources[0])
+}
+
+// sourceProvides reports whether packageDir contains name for target. The default cursor target
+// also matches anything the registry can route to another provider (commands, skills, ...).
+func (a *App) sourceProvides(packageDir, name, target string, cfg *config.Config) bool {
+	if info, err := os.Stat(packageDir); err != nil || !info.IsDir() {
+		return false
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		target = "cursor"
+	}
+	registry := a.resourceRegistry()
+	if provider, ok := registry.providerForTarget(target); ok {
+		if available, err := restoreSourceAvailable(provider, packageDir, name, cfg); err == nil && available {
+			return true
+		}
+	}
+	if target != "cursor" {
+		return false
+	}
+	_, ok, _ := registry.resolveDefaultTarget(packageDir, name, cfg)
+	return ok
+}
+
+// configuredSourceName returns src's name when it comes from the config's sources list, so
+// lockfiles only record names teammates can resolve.
+func configuredSourceName(cfg *config.Config, src config.PackageSource) string {
+	if configured, ok := cfg.FindSource(src.Name); ok && configured.Path == src.Path {
+		return src.Name
+	}
+	return ""
+}
+
+// checkSourceSynced fails for remote sources that have not been cloned yet.
+func checkSourceSynced(src config.PackageSource) error {
+	if !src.IsRemote() {
+		return nil
+	}
+	if info, err := os.Stat(src.Path); err == nil && info.IsDir() {
+		return nil
+	}
+	return errors.Newf(errors.CodeFailedPrecondition, "source %q (%s) has not been synced yet; run `cursor-rules sync`", src.Name, src.URL)
+}
@@ -0,0 +1,242 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestInstallResolvesNamesAcrossSources(t *testing.T) {
+	_, projectDir := testutil.SetupSourcesProject(t, []testutil.TestSource{
+		{Name: "personal", Files: map[string]string{"git.mdc": testutil.Rule("description: personal git", "Personal.")}},
+		{Name: "org", Files: map[string]string{
+			"git.mdc":     testutil.Rule("description: org git", "Org."),
+			"testing.mdc": testutil.Rule("description: testing", "Test."),
+		}},
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	for _, name := range []string{"git", "testing"} {
+		if _, err := a.Install(&InstallRequest{Name: name, Workdir: projectDir, Target: "cursor"}); err != nil {
+			t.Fatalf("Install %s failed: %v", name, err)
+		}
+	}
+	lock, err := lockfile.Load(projectDir)
+	if err != nil {
+		t.Fatalf("load lockfile: %v", err)
+	}
+	if entry, _ := lock.Find("cursor", "git"); entry.Source != "personal" || entry.Path != "git.mdc" {
+		t.Errorf("git should come from the first source, got %+v", entry)
+	}
+	if entry, _ := lock.Find("cursor", "testing"); entry.Source != "org" || entry.Path != "testing.mdc" {
+		t.Errorf("testing should fall through to org, got %+v", entry)
+	}
+
+	if _, err := a.Install(&InstallRequest{Name: "org:git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install org:git failed: %v", err)
+	}
+	lock, _ = lockfile.Load(projectDir)
+	if entry, _ := lock.Find("cursor", "git"); entry.Source != "org" {
+		t.Errorf("qualified name should select org, got %+v", entry)
+	}
+
+	if _, err := a.Install(&InstallRequest{Name: "team:git", Workdir: projectDir, Target: "cursor"}); err == nil {
+		t.Fatal("expected error for unknown source")
+	}
+}
+
+func TestListAndSyncQualifyItemsWithMultipleSources(t *testing.T) {
+	testutil.SetupSourcesProject(t, []testutil.TestSource{
+		{Name: "personal", Files: map[string]string{"git.mdc": testutil.Rule("description: personal git", "Personal.")}},
+		{Name: "org", Files: map[string]string{
+			"git.mdc":     testutil.Rule("description: org git", "Org."),
+			"testing.mdc": testutil.Rule("description: testing", "Test."),
+		}},
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	list, err := a.ListRules(ListRequest{Target: "cursor"})
+	if err != nil {
+		t.Fatalf("ListRules failed: %v", err)
+	}
+	if len(list.Sources) != 2 || list.Sources[0].Name != "personal" {
+		t.Fatalf("expected one tree per source, got %+v", list.Sources)
+	}
+	want := []string{"personal:git", "org:git", "org:testing"}
+	if len(list.Targets) != 1 || !slices.Equal(list.Targets[0].Items, want) {
+		t.Fatalf("expected items %v, got %+v", want, list.Targets)
+	}
+
+	sync, err := a.Sync(SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(sync.Sources) != 2 || !slices.Contains(sync.Presets, "org:testing.mdc") {
+		t.Fatalf("unexpected sync response: %+v", sync)
+	}
+}
+
+func TestTransformPreviewUsesSourcePrecedence(t *testing.T) {
+	testutil.SetupSourcesProject(t, []testutil.TestSource{
+		{Name: "personal", Files: map[string]string{"git.mdc": testutil.Rule("description: personal git", "Personal.")}},
+		{Name: "org", Files: map[string]string{"git.mdc": testutil.Rule("description: org git", "Org.")}},
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.TransformPreview(TransformRequest{Name: "git", Target: "cursor"})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(resp.Items) != 1 || !strings.Contains(resp.Items[0].Output, "Personal.") {
+		t.Fatalf("expected personal git preview, got %+v", resp.Items)
+	}
+	resp, err = a.TransformPreview(TransformRequest{Name: "org:git", Target: "cursor"})
+	if err != nil {
+		t.Fatalf("TransformPreview org:git failed: %v", err)
+	}
+	if len(resp.Items) != 1 || !strings.Contains(resp.Items[0].Output, "Org.") {
+		t.Fatalf("expected org git preview, got %+v", resp.Items)
+	}
+}
+
+func TestSyncClonesRemoteSourceIntoCache(t *testing.T) {
+	bare, work := testutil.InitRemoteRepo(t)
+	testutil.CommitAndPush(t, work, "add git", map[string]string{"git.mdc": "---\ndescription: remote git\n---\nRemote."})
+	configDir := t.TempDir()
+	cacheDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", "")
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", configDir)
+	t.Setenv("CURSOR_RULES_CACHE_DIR", cacheDir)
+	testutil.CreateTestConfig(t, "sources:\n  - name: org\n    url: "+bare+"\n    ref: main\n")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	_, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"})
+	if apperrors.CodeOf(err) != apperrors.CodeFailedPrecondition {
+		t.Fatalf("expected failed precondition before sync, got %v", err)
+	}
+
+	resp, err := a.Sync(SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	wantDir := filepath.Join(cacheDir, "sources", "org")
+	if resp.PackageDir != wantDir || len(resp.Sources) != 1 || resp.Sources[0].URL != bare {
+		t.Fatalf("unexpected sync response: %+v", resp)
+	}
+	if !slices.Contains(resp.Presets, "git.mdc") {
+		t.Fatalf("expected git.mdc from the clone, got %v", resp.Presets)
+	}
+
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install after sync failed: %v", err)
+	}
+	lock, _ := lockfile.Load(projectDir)
+	if entry, ok := lock.Find("cursor", "git"); !ok || entry.Source != "org" || entry.Path != "git.mdc" {
+		t.Errorf("unexpected lock entry: %+v", entry)
+	}
+}
+
+func TestSyncReportsChangesBetweenCommits(t *testing.T) {
+	bare, work := testutil.InitRemoteRepo(t)
+	first := testutil.CommitAndPush(t, work, "initial", map[string]string{
+		"git.mdc":                "---\ndescription: git\n---\nv1",
+		"old.mdc":                "---\ndescription: old\n---\nold",
+		"frontend/react.mdc":     "---\ndescription: react\n---\nv1",
+		"commands/review.md":     "Review the diff.",
+		"skills/deploy/SKILL.md": "---\nname: deploy\n---\nDeploy.",
+	})
+	configDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", "")
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", configDir)
+	t.Setenv("CURSOR_RULES_CACHE_DIR", t.TempDir())
+	testutil.CreateTestConfig(t, "sources:\n  - name: org\n    url: "+bare+"\n")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.Sync(SyncRequest{})
+	if err != nil {
+		t.Fatalf("initial Sync failed: %v", err)
+	}
+	if src := resp.Sources[0]; src.Before != "" || src.After != first || len(src.Changes) != 0 {
+		t.Fatalf("unexpected initial sync source: %+v", src)
+	}
+
+	testutil.RunGit(t, work, "rm", "--quiet", "old.mdc")
+	second := testutil.CommitAndPush(t, work, "update", map[string]string{
+		"git.mdc":                "---\ndescription: git\n---\nv2",
+		"testing.mdc":            "---\ndescription: testing\n---\nnew",
+		"frontend/react.mdc":     "---\ndescription: react\n---\nv2",
+		"skills/deploy/SKILL.md": "---\nname: deploy\n---\nDeploy safely.",
+		"commands/ship.md":       "Ship it.",
+	})
+
+	resp, err = a.Sync(SyncRequest{})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	src := resp.Sources[0]
+	if src.Before != first || src.After != second {
+		t.Fatalf("expected %s -> %s, got %s -> %s", first, second, src.Before, src.After)
+	}
+	want := []SyncChange{
+		{Kind: resourceKindCommand, Name: "ship", Change: SyncChangeAdded},
+		{Kind: resourceKindSkill, Name: "deploy", Change: SyncChangeModified},
+		{Kind: resourceKindRule, Name: "git", Change: SyncChangeModified},
+		{Kind: resourceKindRule, Name: "testing", Change: SyncChangeAdded},
+		{Kind: resourceKindRule, Name: "frontend", Change: SyncChangeModified},
+		{Kind: resourceKindRule, Name: "old", Change: SyncChangeRemoved},
+	}
+	if !slices.Equal(src.Changes, want) {
+		t.Fatalf("unexpected changes:\n got %+v\nwant %+v", src.Changes, want)
+	}
+
+	resp, err = a.Sync(SyncRequest{})
+	if err != nil {
+		t.Fatalf("no-op Sync failed: %v", err)
+	}
+	if src := resp.Sources[0]; src.Before != second || src.After != second || len(src.Changes) != 0 {
+		t.Fatalf("expected no changes on repeated sync, got %+v", src)
+	}
+}
+
+func TestSyncApplyAndWatcherRecordInstalls(t *testing.T) {
+	dirs, projectDir := testutil.SetupSourcesProject(t, []testutil.TestSource{
+		{Name: "org", Files: map[string]string{
+			"git.mdc":     testutil.Rule("description: git", "Git."),
+			"testing.mdc": testutil.Rule("description: testing", "Test."),
+		}},
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	resp, err := a.Sync(SyncRequest{Apply: true, Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(resp.Applied) != 2 || resp.Applied[0].Error != "" || resp.Applied[1].Error != "" {
+		t.Fatalf("unexpected applied results: %+v", resp.Applied)
+	}
+	lock, err := lockfile.Load(projectDir)
+	if err != nil {
+		t.Fatalf("load lockfile: %v", err)
+	}
+	for _, name := range []string{"git", "testing"} {
+		if entry, ok := lock.Find("cursor", name); !ok || entry.Source != "org" || len(entry.Files) != 1 {
+			t.Fatalf("%s not recorded by sync --apply: %+v", name, lock.Entries)
+		}
+	}
+
+	watched := t.TempDir()
+	if _, err := a.applyWatchedPreset(dirs[0])(watched, "git"); err != nil {
+		t.Fatalf("watcher apply failed: %v", err)
+	}
+	lock, _ = lockfile.Load(watched)
+	if _, ok := lock.Find("cursor", "git"); !ok {
+		t.Fatalf("git not recorded by the watcher: %+v", lock.Entries)
+	}
+}
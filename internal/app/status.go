@@ -0,0 +1,215 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// Resource and file drift states reported by Status.
+const (
+	StatusUpToDate        = "up-to-date"
+	StatusOutdated        = "outdated"
+	StatusLocallyModified = "locally-modified"
+	StatusOrphaned        = "orphaned"
+	StatusMissing         = "missing"
+)
+
+// StatusRequest describes a drift detection request.
+type StatusRequest struct {
+	Workdir string
+	Target  string
+	// PackageDir overrides the package dir recorded in the lockfile.
+	PackageDir string
+}
+
+// FileStatus is the drift state of a single installed file.
+type FileStatus struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+}
+
+// ResourceStatus is the drift state of one installed resource.
+type ResourceStatus struct {
+	Kind       string       `json:"kind"`
+	Target     string       `json:"target"`
+	Name       string       `json:"name"`
+	PackageDir string       `json:"packageDir"`
+	State      string       `json:"state"`
+	Files      []FileStatus `json:"files,omitempty"`
+	Message    string       `json:"message,omitempty"`
+}
+
+// StatusResponse captures drift detection output.
+type StatusResponse struct {
+	Workdir   string           `json:"workdir"`
+	Lockfile  string           `json:"lockfile"`
+	Resources []ResourceStatus `json:"resources"`
+}
+
+// Count returns how many resources are in the given state.
+func (r *StatusResponse) Count(state string) int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, res := range r.Resources {
+		if res.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+// Status compares every resource recorded in the lockfile against a fresh in-memory render of
+// its source and against the files on disk.
+func (a *App) Status(req StatusRequest) (*StatusResponse, error) {
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := projectTemplate(wd, false)
+	if err != nil {
+		return nil, err
+	}
+	return a.status(wd, req, tmpl)
+}
+
+// status reports the resources recorded in the lockfile of project root wd, rendering their
+// sources with tmpl.
+func (a *App) status(wd string, req StatusRequest, tmpl *transform.Template) (*StatusResponse, error) {
+	cfg, _, err := a.LoadConfig("")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	lock, err := lockfile.Load(wd)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "parse lockfile %s", lockfile.Path(wd))
+	}
+
+	registry := a.resourceRegistry()
+	target := strings.TrimSpace(req.Target)
+	if target != "" {
+		if _, ok := registry.providerForTarget(target); !ok {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "unknown target: %s", target)
+		}
+	}
+
+	resp := &StatusResponse{
+		Workdir:   wd,
+		Lockfile:  lockfile.Path(wd),
+		Resources: []ResourceStatus{},
+	}
+	for _, entry := range lock.Entries {
+		if target != "" && entry.Target != target {
+			continue
+		}
+		provider, ok := registry.providerForTarget(entry.Target)
+		if !ok {
+			resp.Resources = append(resp.Resources, ResourceStatus{
+				Kind:    entry.Kind,
+				Target:  entry.Target,
+				Name:    entry.Name,
+				State:   StatusOrphaned,
+				Message: "unknown target " + entry.Target,
+			})
+			continue
+		}
+		packageDir := a.restorePackageDir(entry, cfg, strings.TrimSpace(req.PackageDir))
+		status := ResourceStatus{
+			Kind:       entry.Kind,
+			Target:     entry.Target,
+			Name:       entry.Name,
+			PackageDir: packageDir,
+		}
+		available, err := restoreSourceAvailable(provider, packageDir, entry.Name, cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "check source for %s %q", entry.Target, entry.Name)
+		}
+		if !available {
+			status.State = StatusOrphaned
+			status.Message = "source no longer exists in " + packageDir
+			resp.Resources = append(resp.Resources, status)
+			continue
+		}
+		expected, err := renderResource(provider, packageDir, entry.Name, cfg, nativeResourceInstallOptions{
+			Excludes:  entry.Excludes,
+			NoFlatten: entry.NoFlatten,
+			Template:  tmpl,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "render %s %q", entry.Target, entry.Name)
+		}
+		status.Files = compareLockedFiles(provider, wd, entry, expected)
+		status.State = aggregateFileStates(status.Files)
+		resp.Resources = append(resp.Resources, status)
+	}
+	return resp, nil
+}
+
+// compareLockedFiles classifies every recorded or freshly rendered file of a lock entry.
+func compareLockedFiles(provider nativeResourceProvider, projectRoot string, entry lockfile.Entry, expected map[string][]byte) []FileStatus {
+	recorded := make(map[string]string, len(entry.Files))
+	paths := make([]string, 0, len(entry.Files)+len(expected))
+	for _, f := range entry.Files {
+		recorded[f.Path] = f.Hash
+		paths = append(paths, f.Path)
+	}
+	for rel := range expected {
+		if _, ok := recorded[rel]; !ok {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+
+	out := make([]FileStatus, 0, len(paths))
+	for _, rel := range paths {
+		out = append(out, FileStatus{Path: rel, State: fileState(provider, projectRoot, entry.Name, rel, recorded, expected)})
+	}
+	return out
+}
+
+func fileState(provider nativeResourceProvider, projectRoot, name, rel string, recorded map[string]string, expected map[string][]byte) string {
+	lockHash, isRecorded := recorded[rel]
+	want, isExpected := expected[rel]
+	path := filepath.Join(projectRoot, filepath.FromSlash(rel))
+	data, err := os.ReadFile(path)
+	if err == nil {
+		data = lockedContent(provider, path, name, data)
+	}
+	if _, shared := provider.(sharedFileProvider); err != nil || (shared && len(data) == 0) {
+		if !isRecorded {
+			return StatusOutdated
+		}
+		return StatusMissing
+	}
+	diskHash := lockfile.HashBytes(data)
+	switch {
+	case isExpected && diskHash == lockfile.HashBytes(want):
+		return StatusUpToDate
+	case isRecorded && diskHash != lockHash:
+		return StatusLocallyModified
+	default:
+		// Disk still matches the install, but the source now renders differently (or not at all).
+		return StatusOutdated
+	}
+}
+
+// aggregateFileStates reduces file states to a resource state; local edits win over staleness.
+func aggregateFileStates(files []FileStatus) string {
+	state := StatusUpToDate
+	for _, f := range files {
+		switch f.State {
+		case StatusLocallyModified, StatusMissing:
+			return StatusLocallyModified
+		case StatusOutdated:
+			state = StatusOutdated
+		}
+	}
+	return state
+}
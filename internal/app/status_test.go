@@ -0,0 +1,100 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func statusByName(t *testing.T, resp *StatusResponse) map[string]ResourceStatus {
+	t.Helper()
+	out := make(map[string]ResourceStatus, len(resp.Resources))
+	for _, res := range resp.Resources {
+		out[res.Target+"/"+res.Name] = res
+	}
+	return out
+}
+
+func TestStatusReportsDrift(t *testing.T) {
+	packageDir, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc":            testutil.Rule("description: git", "Use conventional commits."),
+		"docs.mdc":           testutil.Rule("description: docs", "v1"),
+		"legacy.mdc":         testutil.Rule("description: legacy", "old"),
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{
+		"cursor":        transform.NewCursorTransformer(),
+		"copilot-instr": transform.NewCopilotInstructionsTransformer(),
+	})
+	for _, req := range []*InstallRequest{
+		{Name: "git", Workdir: projectDir, Target: "cursor"},
+		{Name: "docs", Workdir: projectDir, Target: "cursor"},
+		{Name: "legacy", Workdir: projectDir, Target: "cursor"},
+		{Name: "frontend", Workdir: projectDir, Target: "copilot-instr"},
+	} {
+		if _, err := a.Install(req); err != nil {
+			t.Fatalf("Install %s failed: %v", req.Name, err)
+		}
+	}
+
+	// docs: source changed upstream; frontend: edited locally; legacy: source removed.
+	if err := os.WriteFile(filepath.Join(packageDir, "docs.mdc"), []byte("---\ndescription: docs\n---\nv2"), 0o644); err != nil {
+		t.Fatalf("rewrite preset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, ".github", "instructions", "react.instructions.md"), []byte("edited"), 0o644); err != nil {
+		t.Fatalf("edit installed file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(packageDir, "legacy.mdc")); err != nil {
+		t.Fatalf("remove preset: %v", err)
+	}
+
+	resp, err := a.Status(StatusRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	got := statusByName(t, resp)
+	want := map[string]string{
+		"cursor/git":             StatusUpToDate,
+		"cursor/docs":            StatusOutdated,
+		"cursor/legacy":          StatusOrphaned,
+		"copilot-instr/frontend": StatusLocallyModified,
+	}
+	for key, state := range want {
+		if got[key].State != state {
+			t.Errorf("%s: state %q, want %q (files: %+v)", key, got[key].State, state, got[key].Files)
+		}
+	}
+	if resp.Count(StatusUpToDate) != 1 {
+		t.Errorf("expected 1 up-to-date resource, got %d", resp.Count(StatusUpToDate))
+	}
+}
+
+func TestStatusFiltersByTarget(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc": testutil.Rule("description: git", "Use conventional commits."),
+	})
+	a := New(nil, staticProvider{
+		"cursor":        transform.NewCursorTransformer(),
+		"copilot-instr": transform.NewCopilotInstructionsTransformer(),
+	})
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "copilot-instr"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	resp, err := a.Status(StatusRequest{Workdir: projectDir, Target: "copilot-instr"})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(resp.Resources) != 1 || resp.Resources[0].Target != "copilot-instr" {
+		t.Fatalf("unexpected resources: %+v", resp.Resources)
+	}
+	if _, err := a.Status(StatusRequest{Workdir: projectDir, Target: "nope"}); err == nil {
+		t.Fatal("expected unknown target error")
+	}
+}
@@ -2,7 +2,9 @@ package app
 
 import (
 	"path/filepath"
+	"slices"
 
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 )
@@ -22,11 +24,27 @@ type SyncApplyResult struct {
 	DryRun   bool
 	Strategy core.InstallStrategy
 	Error    string
+	// Skipped explains why the preset was not applied because it does not fit the project.
+	Skipped string
 }
 
-// SyncResponse captures sync output.
+// SyncSource is one package source visited by sync. Before and After are the commits checked
+// out before and after the sync; both are empty for sources that are not git checkouts.
+type SyncSource struct {
+	Name       string
+	PackageDir string
+	URL        string
+	Ref        string
+	Before     string
+	After      string
+	Changes    []SyncChange
+}
+
+// SyncResponse captures sync output. With several package sources, listed items are
+// qualified as "source:name".
 type SyncResponse struct {
 	PackageDir        string
+	Sources           []SyncSource
 	Presets           []string
 	Commands          []string
 	Skills            []string
@@ -38,34 +56,44 @@ type SyncResponse struct {
 	UsedConfigPresets bool
 }
 
-// Sync synchronizes the package repo and optionally applies presets.
+// Sync synchronizes every package source and optionally applies presets.
 func (a *App) Sync(req SyncRequest) (*SyncResponse, error) {
 	cfg, _, err := a.LoadConfig(req.ConfigPath)
 	if err != nil {
 		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
 	}
-	packageDir := a.ResolvePackageDir(cfg)
-
-	if err := core.SyncPackageRepo(packageDir); err != nil {
-		return nil, err
-	}
-	presets, err := core.ListPackagePresets(packageDir)
-	if err != nil {
-		return nil, err
-	}
-	resp := &SyncResponse{
-		PackageDir: packageDir,
-		Presets:    presets,
-	}
-	for _, provider := range a.resourceRegistry().providers() {
-		items, listErr := provider.ListAvailable(packageDir, cfg)
-		if listErr != nil {
-			if provider.Kind() == resourceKindCommand {
-				return nil, errors.Wrapf(listErr, errors.CodeInternal, "list shared commands")
+	sources := a.PackageSources(cfg)
+	resp := &SyncResponse{PackageDir: sources[0].Path}
+	for _, src := range sources {
+		synced := SyncSource{Name: src.Name, PackageDir: src.Path, URL: src.URL, Ref: src.Ref}
+		synced.Before = core.GitHead(src.Path)
+		if err := syncSource(src); err != nil {
+			return nil, err
+		}
+		synced.After = core.GitHead(src.Path)
+		if synced.Before != "" && synced.After != "" && synced.Before != synced.After {
+			changes, err := a.syncChangelog(src.Path, synced.Before, synced.After, cfg)
+			if err != nil {
+				return nil, errors.Wrapf(err, errors.CodeInternal, "compute changes for source %q", src.Name)
 			}
-			continue
+			synced.Changes = changes
+		}
+		presets, err := core.ListPackagePresets(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		resp.Sources = append(resp.Sources, synced)
+		resp.Presets = append(resp.Presets, qualifySourceNames(sources, src.Name, presets)...)
+		for _, provider := range a.resourceRegistry().providers() {
+			items, listErr := provider.ListAvailable(src.Path, cfg)
+			if listErr != nil {
+				if provider.Kind() == resourceKindCommand {
+					return nil, errors.Wrapf(listErr, errors.CodeInternal, "list shared commands")
+				}
+				continue
+			}
+			assignSyncItems(resp, provider.Kind(), qualifySourceNames(sources, src.Name, items))
 		}
-		assignSyncItems(resp, provider.Kind(), items)
 	}
 
 	if !req.Apply {
@@ -83,13 +111,43 @@ func (a *App) Sync(req SyncRequest) (*SyncResponse, error) {
 		toApply = append(toApply, cfg.Presets...)
 		resp.UsedConfigPresets = true
 	} else {
-		for _, p := range presets {
+		for _, p := range resp.Presets {
 			name := p[:len(p)-len(filepath.Ext(p))]
 			toApply = append(toApply, name)
 		}
 	}
 
+	facts, err := detectProjectFacts(req.Workdir)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := facts.template(false)
+	if err != nil {
+		return nil, err
+	}
 	for _, name := range toApply {
+		src, bare, err := a.resolveSource(sources, name, "cursor", cfg)
+		if err == nil {
+			var reason string
+			if reason, err = presetSkipReason(src.Path, bare, facts); err == nil && reason != "" {
+				resp.Applied = append(resp.Applied, SyncApplyResult{
+					Name:    name,
+					Workdir: req.Workdir,
+					DryRun:  req.DryRun,
+					Skipped: reason,
+				})
+				continue
+			}
+		}
+		if err != nil {
+			resp.Applied = append(resp.Applied, SyncApplyResult{
+				Name:    name,
+				Workdir: req.Workdir,
+				DryRun:  req.DryRun,
+				Error:   err.Error(),
+			})
+			continue
+		}
 		if req.DryRun {
 			resp.Applied = append(resp.Applied, SyncApplyResult{
 				Name:    name,
@@ -98,7 +156,14 @@ func (a *App) Sync(req SyncRequest) (*SyncResponse, error) {
 			})
 			continue
 		}
-		strategy, err := core.ApplyPresetToProject(req.Workdir, name, packageDir)
+		results, err := a.installInternal(&installInternalRequest{
+			Workdir:    req.Workdir,
+			PackageDir: src.Path,
+			Source:     configuredSourceName(cfg, src),
+			Name:       bare,
+			Target:     "cursor",
+			Template:   tmpl,
+		})
 		if err != nil {
 			resp.Applied = append(resp.Applied, SyncApplyResult{
 				Name:    name,
@@ -110,25 +175,47 @@ func (a *App) Sync(req SyncRequest) (*SyncResponse, error) {
 		resp.Applied = append(resp.Applied, SyncApplyResult{
 			Name:     name,
 			Workdir:  req.Workdir,
-			Strategy: strategy,
+			Strategy: results[0].Strategy,
 		})
 	}
 
 	return resp, nil
 }
 
+// syncSource clones or fetches remote sources and pulls local git checkouts.
+func syncSource(src config.PackageSource) error {
+	if src.IsRemote() {
+		if err := core.SyncRemoteRepo(src.URL, src.Path, src.Ref); err != nil {
+			return errors.Wrapf(err, errors.CodeInternal, "sync source %q", src.Name)
+		}
+		return nil
+	}
+	return core.SyncPackageRepo(src.Path)
+}
+
 func assignSyncItems(resp *SyncResponse, kind string, items []string) {
 	if resp == nil {
 		return
 	}
 	switch kind {
 	case resourceKindCommand:
-		resp.Commands = items
+		resp.Commands = appendUnique(resp.Commands, items)
 	case resourceKindSkill:
-		resp.Skills = items
+		resp.Skills = appendUnique(resp.Skills, items)
 	case resourceKindAgent:
-		resp.Agents = items
+		resp.Agents = appendUnique(resp.Agents, items)
 	case resourceKindHooks:
-		resp.Hooks = items
+		resp.Hooks = appendUnique(resp.Hooks, items)
+	}
+}
+
+// appendUnique appends items not already present; providers of the same kind list the same
+// package items for different targets.
+func appendUnique(dst, items []string) []string {
+	for _, item := range items {
+		if !slices.Contains(dst, item) {
+			dst = append(dst, item)
+		}
 	}
+	return dst
 }
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// Sync changelog entry types.
+const (
+	SyncChangeAdded    = "added"
+	SyncChangeRemoved  = "removed"
+	SyncChangeModified = "modified"
+)
+
+// SyncChange is one package item that changed between the commits before and after a sync.
+type SyncChange struct {
+	Kind   string
+	Name   string
+	Change string
+}
+
+// syncChangelog lists rules, commands, skills, agents and hook presets that were added, removed
+// or modified in the source checkout at packageDir between commits before and after.
+func (a *App) syncChangelog(packageDir, before, after string, cfg *config.Config) ([]SyncChange, error) {
+	oldDir, err := os.MkdirTemp("", "cursor-rules-sync-")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "create scratch dir")
+	}
+	defer os.RemoveAll(oldDir)
+	if err := core.ExportGitTree(packageDir, before, oldDir); err != nil {
+		return nil, err
+	}
+	changed, err := core.GitChangedFiles(packageDir, before, after)
+	if err != nil {
+		return nil, err
+	}
+	touched := make(map[string]map[string]struct{})
+	for _, rel := range changed {
+		for kind, name := range packageItemsForPath(packageDir, rel, cfg) {
+			if touched[kind] == nil {
+				touched[kind] = make(map[string]struct{})
+			}
+			touched[kind][name] = struct{}{}
+		}
+	}
+
+	var changes []SyncChange
+	for _, provider := range a.resourceRegistry().uniqueKindProviders() {
+		kind := provider.Kind()
+		// Missing subdirectories simply mean no items of that kind at that commit.
+		oldItems, _ := provider.ListAvailable(oldDir, cfg)
+		newItems, _ := provider.ListAvailable(packageDir, cfg)
+		for _, name := range newItems {
+			if !slices.Contains(oldItems, name) {
+				changes = append(changes, SyncChange{Kind: kind, Name: name, Change: SyncChangeAdded})
+			} else if _, ok := touched[kind][name]; ok {
+				changes = append(changes, SyncChange{Kind: kind, Name: name, Change: SyncChangeModified})
+			}
+		}
+		for _, name := range oldItems {
+			if !slices.Contains(newItems, name) {
+				changes = append(changes, SyncChange{Kind: kind, Name: name, Change: SyncChangeRemoved})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// packageItemsForPath maps a changed repository path to the item it belongs to, per kind. A path
+// can match more than one kind (e.g. commands/ in a package without a rules/ subdir); callers only
+// count matches for items that exist.
+func packageItemsForPath(packageDir, rel string, cfg *config.Config) map[string]string {
+	rel = filepath.ToSlash(rel)
+	out := make(map[string]string)
+	if rest, ok := strings.CutPrefix(rel, core.CommandsSubdir()+"/"); ok {
+		if dir, _, nested := strings.Cut(rest, "/"); nested {
+			out[resourceKindCommand] = dir
+		} else {
+			out[resourceKindCommand] = strings.TrimSuffix(strings.TrimSuffix(rest, ".command.mdc"), ".md")
+		}
+	}
+	if rest, ok := strings.CutPrefix(rel, core.SkillsSubdir(cfg.SkillsSubdir)+"/"); ok {
+		dir, _, _ := strings.Cut(rest, "/")
+		out[resourceKindSkill] = dir
+	}
+	if rest, ok := strings.CutPrefix(rel, core.ResolveAgentsSubdir(packageDir, cfg.AgentsSubdir)+"/"); ok && !strings.Contains(rest, "/") {
+		out[resourceKindAgent] = strings.TrimSuffix(rest, ".md")
+	}
+	if rest, ok := strings.CutPrefix(rel, core.HooksSubdir(cfg.HooksSubdir)+"/"); ok {
+		dir, _, _ := strings.Cut(rest, "/")
+		out[resourceKindHooks] = dir
+	}
+
+	rulesRel := rel
+	if rulesRoot := core.ResolveRulesPackageDir(packageDir); rulesRoot != packageDir {
+		var ok bool
+		if rulesRel, ok = strings.CutPrefix(rel, "rules/"); !ok {
+			return out
+		}
+	}
+	if dir, _, nested := strings.Cut(rulesRel, "/"); nested {
+		out[resourceKindRule] = dir
+	} else {
+		out[resourceKindRule] = strings.TrimSuffix(rulesRel, ".mdc")
+	}
+	return out
+}
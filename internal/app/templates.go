@@ -0,0 +1,85 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// VarsFileName is the per-project template variables file inside the project's .cursor directory.
+const VarsFileName = "cursor-rules.vars.yaml"
+
+// projectVars is the content of .cursor/cursor-rules.vars.yaml.
+type projectVars struct {
+	// Strict makes placeholders that cannot be rendered fail the install.
+	Strict bool              `yaml:"strict"`
+	Vars   map[string]string `yaml:"vars"`
+}
+
+// VarsPath returns the template variables file path for a project root.
+func VarsPath(projectRoot string) string {
+	return filepath.Join(projectRoot, ".cursor", VarsFileName)
+}
+
+// projectTemplate builds the template rules are rendered with when installed into projectRoot.
+// strict forces strict rendering regardless of the vars file.
+// Profiling walks the project tree, so commands installing several resources build it once.
+func projectTemplate(projectRoot string, strict bool) (*transform.Template, error) {
+	facts, err := detectProjectFacts(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	return facts.template(strict)
+}
+
+// template builds the template rules are rendered with when installed into the project f
+// describes, for commands that already profiled it.
+func (f projectFacts) template(strict bool) (*transform.Template, error) {
+	vars, err := loadProjectVars(f.Root)
+	if err != nil {
+		return nil, err
+	}
+	root := f.Root
+	if abs, absErr := filepath.Abs(f.Root); absErr == nil {
+		root = abs
+	}
+	if vars.Vars == nil {
+		vars.Vars = map[string]string{}
+	}
+	return &transform.Template{
+		Data: transform.TemplateData{
+			Project: transform.TemplateProject{
+				Name:       filepath.Base(root),
+				Type:       f.Type,
+				Root:       root,
+				Languages:  f.Languages,
+				Frameworks: f.Frameworks,
+			},
+			Vars: vars.Vars,
+		},
+		Strict: strict || vars.Strict,
+	}, nil
+}
+
+func loadProjectVars(projectRoot string) (projectVars, error) {
+	var vars projectVars
+	path := VarsPath(projectRoot)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return vars, nil
+		}
+		return vars, errors.Wrapf(err, errors.CodeInternal, "read %s", path)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&vars); err != nil && err != io.EOF {
+		return vars, errors.Wrapf(err, errors.CodeInvalidArgument, "parse %s", path)
+	}
+	return vars, nil
+}
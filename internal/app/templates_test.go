@@ -0,0 +1,108 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func TestInstallRendersTemplateVariables(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := filepath.Join(t.TempDir(), "webapp")
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "golang.mdc", "---\ndescription: \"Go rules for {{ .Project.Name }}\"\ntemplate: true\n---\nTarget Go {{ .Vars.goVersion }} in this {{ .Project.Type }} module.")
+	testutil.CreateTestFile(t, projectDir, "go.mod", "module example.com/webapp\n")
+	testutil.CreateTestFile(t, projectDir, filepath.Join(".cursor", VarsFileName), "vars:\n  goVersion: \"1.22\"\n")
+
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "golang", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".cursor", "rules", "golang.mdc"))
+	if err != nil {
+		t.Fatalf("read installed rule: %v", err)
+	}
+	for _, want := range []string{"Go rules for webapp", "Target Go 1.22 in this go module."} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("installed rule missing %q:\n%s", want, data)
+		}
+	}
+
+	status, err := a.Status(StatusRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(status.Resources) != 1 || status.Resources[0].State != StatusUpToDate {
+		t.Fatalf("rendered install should be up to date, got %+v", status.Resources)
+	}
+
+	preview, err := a.TransformPreview(TransformRequest{Name: "golang", Target: "cursor", Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(preview.Items) != 1 || preview.Items[0].Output != string(data) {
+		t.Fatalf("preview should match installed output, got %+v", preview.Items)
+	}
+}
+
+func TestInstallStrictTemplatesRejectUndefinedVariables(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "golang.mdc", "---\ndescription: \"Go\"\ntemplate: true\n---\nTarget Go {{ .Vars.goVersion }}.")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	if _, err := a.Install(&InstallRequest{Name: "golang", Workdir: projectDir, Target: "cursor", StrictTemplates: true}); err == nil || !strings.Contains(err.Error(), "goVersion") {
+		t.Fatalf("expected undefined variable error, got %v", err)
+	}
+
+	testutil.CreateTestFile(t, projectDir, filepath.Join(".cursor", VarsFileName), "strict: true\nvar:\n  goVersion: \"1.22\"\n")
+	if _, err := a.Install(&InstallRequest{Name: "golang", Workdir: projectDir, Target: "cursor"}); apperrors.CodeOf(err) != apperrors.CodeInvalidArgument {
+		t.Fatalf("expected a vars file typo to be rejected, got %v", err)
+	}
+
+	testutil.CreateTestFile(t, projectDir, filepath.Join(".cursor", VarsFileName), "strict: true\n")
+	preview, err := a.TransformPreview(TransformRequest{Name: "golang", Target: "cursor", Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(preview.Items) != 1 || !strings.Contains(preview.Items[0].Error, "goVersion") {
+		t.Fatalf("strict vars file should surface preview errors, got %+v", preview.Items)
+	}
+}
+
+func TestPreviewWarnsAboutUnrenderedPlaceholders(t *testing.T) {
+	packageDir := t.TempDir()
+	projectDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+
+	testutil.CreateTestFile(t, packageDir, "helm.mdc", "---\ndescription: \"Helm\"\ntemplate: true\n---\nRelease {{ .Release.Name }} of {{ .Project.Type }}.\n```yaml\nname: {{ .Chart.Name }}\n```")
+	testutil.CreateTestFile(t, packageDir, "plain.mdc", "---\ndescription: \"Plain\"\n---\nUse `{{ . }}` and {{ .Project.Name }}.")
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+
+	preview, err := a.TransformPreview(TransformRequest{Name: "helm", Target: "cursor", Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("TransformPreview failed: %v", err)
+	}
+	if len(preview.Items) != 1 || !strings.Contains(preview.Items[0].Warning, "{{ .Release.Name }}") || strings.Contains(preview.Items[0].Warning, "Chart") {
+		t.Fatalf("expected a warning for the unrendered placeholder only, got %+v", preview.Items)
+	}
+
+	if _, err := a.Install(&InstallRequest{Name: "plain", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(projectDir, ".cursor", "rules", "plain.mdc"))
+	if err != nil || !strings.Contains(string(data), "Use `{{ . }}` and {{ .Project.Name }}.") {
+		t.Fatalf("rules without template: true should install as written, got %q, %v", data, err)
+	}
+}
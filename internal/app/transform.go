@@ -14,6 +14,9 @@ type TransformRequest struct {
 	Name       string
 	Target     string
 	PackageDir string
+	// Workdir is the project whose template variables are used (default: current directory).
+	Workdir         string
+	StrictTemplates bool
 }
 
 // TransformItem is a single previewed transformation.
@@ -44,15 +47,20 @@ func (a *App) TransformPreview(req TransformRequest) (*TransformResponse, error)
 		return nil, err
 	}
 
+	name := req.Name
 	packageDir := strings.TrimSpace(req.PackageDir)
 	if packageDir == "" {
 		cfg, _, err := a.LoadConfig("")
 		if err != nil {
 			return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
 		}
-		packageDir = a.ResolvePackageDir(cfg)
+		src, bare, err := a.resolveSource(a.PackageSources(cfg), req.Name, "", cfg)
+		if err != nil {
+			return nil, err
+		}
+		packageDir, name = src.Path, bare
 	}
-	pkgPath := filepath.Join(packageDir, req.Name)
+	pkgPath := filepath.Join(packageDir, name)
 
 	info, err := os.Stat(pkgPath)
 	if err != nil {
@@ -68,7 +76,20 @@ func (a *App) TransformPreview(req TransformRequest) (*TransformResponse, error)
 		Target: transformer.Target(),
 	}
 
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := projectTemplate(wd, req.StrictTemplates)
+	if err != nil {
+		return nil, err
+	}
+
 	if info.IsDir() {
+		if transformer, err = packageTransformer(transformer, pkgPath); err != nil {
+			return nil, err
+		}
+		transformer = transform.WithTemplate(transformer, tmpl)
 		err = filepath.Walk(pkgPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil || info.IsDir() || !strings.HasSuffix(info.Name(), ".mdc") {
 				return err
@@ -82,7 +103,7 @@ func (a *App) TransformPreview(req TransformRequest) (*TransformResponse, error)
 		return resp, nil
 	}
 
-	resp.Items = append(resp.Items, previewTransform(pkgPath, transformer))
+	resp.Items = append(resp.Items, previewTransform(pkgPath, transform.WithTemplate(transformer, tmpl)))
 	return resp, nil
 }
 
@@ -105,6 +126,16 @@ func previewTransform(path string, transformer transform.Transformer) TransformI
 		return item
 	}
 
+	if _, _, err := transform.RuleDir(transformer, fm); err != nil {
+		item.Error = err.Error()
+		return item
+	}
+
+	var warnings []string
+	if unrendered := transform.UnrenderedPlaceholders(transformer, fm, body); len(unrendered) > 0 {
+		warnings = append(warnings, "unrendered template placeholders: "+strings.Join(unrendered, ", "))
+	}
+
 	transformedFM, transformedBody, err := transformer.Transform(fm, body)
 	if err != nil {
 		item.Error = err.Error()
@@ -112,10 +143,14 @@ func previewTransform(path string, transformer transform.Transformer) TransformI
 	}
 
 	if validateErr := transformer.Validate(transformedFM); validateErr != nil {
-		item.Warning = validateErr.Error()
+		warnings = append(warnings, validateErr.Error())
+	}
+	if warning := transform.CheckRule(transformer, transformedFM, transformedBody); warning != "" {
+		warnings = append(warnings, warning)
 	}
+	item.Warning = strings.Join(warnings, "; ")
 
-	output, err := transform.MarshalMarkdown(transformedFM, transformedBody)
+	output, err := transform.RenderRule(transformer, transformedFM, transformedBody)
 	if err != nil {
 		item.Error = err.Error()
 		return item
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/config"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/lockfile"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// Update actions reported per resource.
+const (
+	UpdateActionUpdated     = "updated"
+	UpdateActionWouldUpdate = "would-update"
+	UpdateActionUnchanged   = "unchanged"
+	UpdateActionSkipped     = "skipped"
+)
+
+// UpdateRequest describes an update of installed resources from their package sources.
+type UpdateRequest struct {
+	Workdir string
+	Target  string
+	// Names limits the update to these resource names. Empty means every recorded resource.
+	Names []string
+	// Force overwrites locally modified files.
+	Force  bool
+	DryRun bool
+	// PackageDir overrides the package dir recorded in the lockfile.
+	PackageDir string
+}
+
+// UpdateResult is the outcome for one recorded resource.
+type UpdateResult struct {
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Action  string `json:"action"`
+	Message string `json:"message,omitempty"`
+}
+
+// UpdateResponse captures update outcomes.
+type UpdateResponse struct {
+	Workdir string         `json:"workdir"`
+	DryRun  bool           `json:"dryRun"`
+	Results []UpdateResult `json:"results"`
+}
+
+// Updated returns the number of resources rewritten (or that would be, in dry-run mode).
+func (r *UpdateResponse) Updated() int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, res := range r.Results {
+		if res.Action == UpdateActionUpdated || res.Action == UpdateActionWouldUpdate {
+			n++
+		}
+	}
+	return n
+}
+
+// Update rewrites installed resources whose package source changed since install. Locally
+// modified resources are left alone unless Force is set; orphaned resources are never touched.
+func (a *App) Update(req UpdateRequest) (*UpdateResponse, error) {
+	wd, err := a.ResolveWorkdir(req.Workdir, true)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := projectTemplate(wd, false)
+	if err != nil {
+		return nil, err
+	}
+	status, err := a.status(wd, StatusRequest{
+		Target:     req.Target,
+		PackageDir: req.PackageDir,
+	}, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := a.LoadConfig("")
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "load config")
+	}
+	lock, err := lockfile.Load(wd)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "parse lockfile %s", lockfile.Path(wd))
+	}
+
+	names := make([]string, 0, len(req.Names))
+	for _, name := range req.Names {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(status.Resources, func(res ResourceStatus) bool { return res.Name == name }) {
+			return nil, errors.Newf(errors.CodeNotFound, "%q is not recorded in %s", name, lockfile.Path(wd))
+		}
+	}
+
+	resp := &UpdateResponse{Workdir: wd, DryRun: req.DryRun, Results: []UpdateResult{}}
+	for _, res := range status.Resources {
+		if len(names) > 0 && !slices.Contains(names, res.Name) {
+			continue
+		}
+		result := UpdateResult{Kind: res.Kind, Target: res.Target, Name: res.Name, State: res.State}
+		switch {
+		case res.State == StatusUpToDate:
+			result.Action = UpdateActionUnchanged
+		case res.State == StatusOrphaned:
+			result.Action = UpdateActionSkipped
+			result.Message = res.Message
+		case res.State == StatusLocallyModified && !req.Force:
+			result.Action = UpdateActionSkipped
+			result.Message = "locally modified; rerun with --force to overwrite"
+		case req.DryRun:
+			result.Action = UpdateActionWouldUpdate
+		default:
+			entry, _ := lock.Find(res.Target, res.Name)
+			if err := a.updateResource(wd, entry, res.PackageDir, cfg, tmpl); err != nil {
+				return nil, err
+			}
+			result.Action = UpdateActionUpdated
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// updateResource reinstalls a recorded resource and deletes previously installed files its
+// source no longer produces. Rules are rendered with tmpl.
+func (a *App) updateResource(projectRoot string, entry lockfile.Entry, packageDir string, cfg *config.Config, tmpl *transform.Template) error {
+	provider, ok := a.resourceRegistry().providerForTarget(entry.Target)
+	if !ok {
+		return errors.Newf(errors.CodeInvalidArgument, "unknown target: %s", entry.Target)
+	}
+	if _, _, err := installWithProvider(provider, projectRoot, packageDir, entry.Name, cfg, nativeResourceInstallOptions{
+		Excludes:  entry.Excludes,
+		NoFlatten: entry.NoFlatten,
+		Source:    entry.Source,
+		Template:  tmpl,
+	}); err != nil {
+		return err
+	}
+
+	lock, err := lockfile.Load(projectRoot)
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "read lockfile")
+	}
+	current, _ := lock.Find(entry.Target, entry.Name)
+	stale := lockfile.Entry{}
+	for _, f := range entry.Files {
+		if !slices.ContainsFunc(current.Files, func(c lockfile.File) bool { return c.Path == f.Path }) {
+			stale.Files = append(stale.Files, f)
+		}
+	}
+	if len(stale.Files) == 0 {
+		return nil
+	}
+	outDir := provider.OutputDir(projectRoot, cfg, false)
+	if err := removeLockedFiles(provider, projectRoot, outDir, stale); err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "remove stale files for %q", entry.Name)
+	}
+	return nil
+}
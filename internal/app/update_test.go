@@ -0,0 +1,114 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+func updateResultsByName(resp *UpdateResponse) map[string]UpdateResult {
+	out := make(map[string]UpdateResult, len(resp.Results))
+	for _, res := range resp.Results {
+		out[res.Name] = res
+	}
+	return out
+}
+
+func TestUpdateRewritesOnlyOutdatedResources(t *testing.T) {
+	packageDir, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc":            testutil.Rule("description: git", "Use conventional commits."),
+		"docs.mdc":           testutil.Rule("description: docs", "v1"),
+		"frontend/react.mdc": testutil.Rule("description: react.mdc", "body"),
+		"frontend/css.mdc":   testutil.Rule("description: css.mdc", "body"),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	for _, name := range []string{"git", "docs", "frontend"} {
+		if _, err := a.Install(&InstallRequest{Name: name, Workdir: projectDir, Target: "cursor"}); err != nil {
+			t.Fatalf("Install %s failed: %v", name, err)
+		}
+	}
+
+	rulesDir := filepath.Join(projectDir, ".cursor", "rules")
+	if err := os.WriteFile(filepath.Join(packageDir, "docs.mdc"), []byte("---\ndescription: docs\n---\nv2"), 0o644); err != nil {
+		t.Fatalf("rewrite preset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(packageDir, "git.mdc"), []byte("---\ndescription: git\n---\nupstream change"), 0o644); err != nil {
+		t.Fatalf("rewrite preset: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(rulesDir, "git.mdc"), []byte("local edit"), 0o644); err != nil {
+		t.Fatalf("edit installed: %v", err)
+	}
+	// frontend drops css.mdc upstream; the installed copy should be cleaned up.
+	if err := os.WriteFile(filepath.Join(packageDir, "frontend", "react.mdc"), []byte("---\ndescription: react\n---\nnew"), 0o644); err != nil {
+		t.Fatalf("rewrite package file: %v", err)
+	}
+	if err := os.Remove(filepath.Join(packageDir, "frontend", "css.mdc")); err != nil {
+		t.Fatalf("remove package file: %v", err)
+	}
+
+	dry, err := a.Update(UpdateRequest{Workdir: projectDir, DryRun: true})
+	if err != nil {
+		t.Fatalf("dry-run Update failed: %v", err)
+	}
+	if got := updateResultsByName(dry)["docs"].Action; got != UpdateActionWouldUpdate {
+		t.Fatalf("dry-run docs action = %q", got)
+	}
+	if data, _ := os.ReadFile(filepath.Join(rulesDir, "docs.mdc")); !strings.Contains(string(data), "v1") {
+		t.Fatalf("dry-run must not write, got %q", data)
+	}
+
+	resp, err := a.Update(UpdateRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	got := updateResultsByName(resp)
+	if got["docs"].Action != UpdateActionUpdated || got["frontend"].Action != UpdateActionUpdated {
+		t.Fatalf("expected docs and frontend updated, got %+v", resp.Results)
+	}
+	if got["git"].Action != UpdateActionSkipped {
+		t.Fatalf("expected locally modified git to be skipped, got %+v", got["git"])
+	}
+	if data, _ := os.ReadFile(filepath.Join(rulesDir, "docs.mdc")); !strings.Contains(string(data), "v2") {
+		t.Fatalf("docs not updated: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(rulesDir, "git.mdc")); string(data) != "local edit" {
+		t.Fatalf("local edit clobbered: %q", data)
+	}
+	assertNotExists(t, filepath.Join(rulesDir, "css.mdc"))
+
+	forced, err := a.Update(UpdateRequest{Workdir: projectDir, Names: []string{"git"}, Force: true})
+	if err != nil {
+		t.Fatalf("forced Update failed: %v", err)
+	}
+	if len(forced.Results) != 1 || forced.Results[0].Action != UpdateActionUpdated {
+		t.Fatalf("unexpected forced results: %+v", forced.Results)
+	}
+	if data, _ := os.ReadFile(filepath.Join(rulesDir, "git.mdc")); !strings.Contains(string(data), "upstream change") {
+		t.Fatalf("git not overwritten: %q", data)
+	}
+
+	status, err := a.Status(StatusRequest{Workdir: projectDir})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Count(StatusUpToDate) != len(status.Resources) {
+		t.Fatalf("expected all resources up-to-date after update, got %+v", status.Resources)
+	}
+}
+
+func TestUpdateUnknownName(t *testing.T) {
+	_, projectDir := testutil.SetupPackageProject(t, map[string]string{
+		"git.mdc": testutil.Rule("description: git", "Use conventional commits."),
+	})
+	a := New(nil, staticProvider{"cursor": transform.NewCursorTransformer()})
+	if _, err := a.Install(&InstallRequest{Name: "git", Workdir: projectDir, Target: "cursor"}); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if _, err := a.Update(UpdateRequest{Workdir: projectDir, Names: []string{"nope"}}); err == nil {
+		t.Fatal("expected error for unrecorded name")
+	}
+}
@@ -28,7 +28,7 @@ func (a *App) StartWatcher(ctx context.Context, req WatchRequest) (*WatchRespons
 		return nil, errors.New(errors.CodeFailedPrecondition, "no config found")
 	}
 	cfg.PackageDir = a.ResolvePackageDir(cfg)
-	if err := core.StartWatcher(ctx, cfg.PackageDir, cfg.AutoApply); err != nil {
+	if err := core.StartWatcher(ctx, cfg.PackageDir, cfg.AutoApply, a.applyWatchedPreset(cfg.PackageDir)); err != nil {
 		return nil, err
 	}
 	return &WatchResponse{
@@ -47,7 +47,7 @@ func (a *App) AutoStartWatcher(ctx context.Context) (bool, *WatchResponse, error
 		return false, nil, nil
 	}
 	cfg.PackageDir = a.ResolvePackageDir(cfg)
-	if err := core.StartWatcher(ctx, cfg.PackageDir, cfg.AutoApply); err != nil {
+	if err := core.StartWatcher(ctx, cfg.PackageDir, cfg.AutoApply, a.applyWatchedPreset(cfg.PackageDir)); err != nil {
 		return false, nil, err
 	}
 	return true, &WatchResponse{
@@ -55,3 +55,20 @@ func (a *App) AutoStartWatcher(ctx context.Context) (bool, *WatchResponse, error
 		AutoApply:  cfg.AutoApply,
 	}, nil
 }
+
+// applyWatchedPreset installs presets changed in packageDir like `install --target cursor`, so
+// they are recorded in each project's lockfile.
+func (a *App) applyWatchedPreset(packageDir string) core.ApplyFunc {
+	return func(projectRoot, preset string) (core.InstallStrategy, error) {
+		results, err := a.installInternal(&installInternalRequest{
+			Workdir:    projectRoot,
+			PackageDir: packageDir,
+			Name:       preset,
+			Target:     "cursor",
+		})
+		if err != nil {
+			return core.StrategyUnknown, err
+		}
+		return results[0].Strategy, nil
+	}
+}
@@ -45,6 +45,12 @@ func NewAppContext(v *viper.Viper, l Logger) *AppContext {
 	ctx.RegisterTransformer("copilot-instr", transform.NewCopilotInstructionsTransformer())
 	ctx.RegisterTransformer("copilot-prompt", transform.NewCopilotPromptsTransformer())
 	ctx.RegisterTransformer("opencode-rules", transform.NewOpenCodeRulesTransformer())
+	ctx.RegisterTransformer("claude-rules", transform.NewClaudeRulesTransformer())
+	ctx.RegisterTransformer("agents-md", transform.NewAgentsMDTransformer())
+	ctx.RegisterTransformer("windsurf", transform.NewWindsurfRulesTransformer())
+	ctx.RegisterTransformer("cline-rules", transform.NewClineRulesTransformer())
+	ctx.RegisterTransformer("roo-rules", transform.NewRooRulesTransformer())
+	ctx.RegisterTransformer("gemini-md", transform.NewGeminiMDTransformer())
 
 	return ctx
 }
@@ -58,7 +64,7 @@ func (ctx *AppContext) RegisterTransformer(name string, t transform.Transformer)
 func (ctx *AppContext) Transformer(target string) (transform.Transformer, error) {
 	t, ok := ctx.transformers[target]
 	if !ok {
-		return nil, errors.Newf(errors.CodeInvalidArgument, "unknown target: %s (available: cursor, copilot-instr, copilot-prompt, opencode-rules)", target)
+		return nil, errors.Newf(errors.CodeInvalidArgument, "unknown target: %s (available: cursor, copilot-instr, copilot-prompt, opencode-rules, claude-rules, agents-md, windsurf, cline-rules, roo-rules, gemini-md)", target)
 	}
 	return t, nil
 }
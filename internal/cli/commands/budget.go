@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
+	"github.com/spf13/cobra"
+)
+
+// NewBudgetCmd returns the budget command
+func NewBudgetCmd(ctx *cli.AppContext) *cobra.Command {
+	var formatFlag string
+	var tokenizerFlag string
+	var limitFlag int
+
+	cmd := &cobra.Command{
+		Use:   "budget",
+		Short: "Report the token cost of all installed rules",
+		Long: `Count the tokens of every rule installed in the current project, per target
+(cursor, copilot-instr, opencode-rules, ...), largest first, with the project total.
+
+Tokens are counted with an offline BPE-style tokenizer by default; --tokenizer chars
+uses the four-characters-per-token heuristic. With --limit the command exits non-zero
+when the total exceeds the budget, so it can gate pull requests.`,
+		Example: `  # Token cost per target and rule
+  cursor-rules budget
+
+  # Fail when installed rules exceed 8000 tokens
+  cursor-rules budget --limit 8000 --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			format := strings.TrimSpace(formatFlag)
+			switch format {
+			case "text", "json", "yaml":
+			default:
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json, yaml)", formatFlag)
+			}
+			if limitFlag < 0 {
+				return errors.Newf(errors.CodeInvalidArgument, "invalid limit: %d (must be zero or positive)", limitFlag)
+			}
+			resp, err := ctx.App().Budget(app.BudgetRequest{
+				Workdir:   cli.GetOptionalFlag(cmd, "workdir"),
+				Tokenizer: tokenizerFlag,
+				Limit:     limitFlag,
+			})
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			switch format {
+			case "json":
+				err = display.RenderJSON(out, resp)
+			case "yaml":
+				err = display.RenderYAML(out, resp)
+			default:
+				display.RenderBudgetResponse(display.NewPrinter(ctx.Messenger(), out, cmd.ErrOrStderr()), resp)
+			}
+			if err != nil {
+				return err
+			}
+			if resp.OverLimit() {
+				cmd.SilenceUsage = true
+				return errors.Newf(errors.CodeFailedPrecondition, "token budget exceeded: %d > %d", resp.TotalTokens, resp.Limit)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json|yaml")
+	cmd.Flags().StringVar(&tokenizerFlag, "tokenizer", tokens.DefaultName, "token estimator: "+strings.Join(tokens.Names(), "|"))
+	cmd.Flags().IntVar(&limitFlag, "limit", 0, "token budget for all installed rules (0: no limit)")
+	return cmd
+}
@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
@@ -375,3 +378,97 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestEffectiveCommandFormats(t *testing.T) {
+	workdir := t.TempDir()
+	testutil.CreateTestFile(t, filepath.Join(workdir, ".cursor", "rules"), "go.mdc", "---\ndescription: Go\nglobs: \"**/*.go\"\n---\nUse gofmt.\n")
+
+	v := viper.New()
+	v.Set("workdir", workdir)
+	ctx := cli.NewAppContext(v, nil)
+
+	run := func(format string) string {
+		t.Helper()
+		cmd := NewEffectiveCmd(ctx)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--format", format})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("effective --format %s failed: %v", format, err)
+		}
+		return out.String()
+	}
+
+	var resp struct {
+		Target string `json:"target"`
+		Rules  []struct {
+			Name   string   `json:"name"`
+			Globs  []string `json:"globs"`
+			Body   string   `json:"body"`
+			Tokens int      `json:"tokens"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal([]byte(run("json")), &resp); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if resp.Target != "cursor" || len(resp.Rules) != 1 || resp.Rules[0].Name != "go.mdc" || resp.Rules[0].Body != "Use gofmt." || resp.Rules[0].Tokens == 0 {
+		t.Fatalf("unexpected json response %+v", resp)
+	}
+	if out := run("yaml"); !strings.Contains(out, "name: go.mdc") || !strings.Contains(out, "- '**/*.go'") {
+		t.Fatalf("unexpected yaml output:\n%s", out)
+	}
+	if out := run("markdown"); !strings.Contains(out, "## go.mdc") || !strings.Contains(out, "| Globs | `**/*.go` |") {
+		t.Fatalf("unexpected markdown output:\n%s", out)
+	}
+
+	cmd := NewEffectiveCmd(ctx)
+	cmd.SetArgs([]string{"--format", "xml"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Fatalf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestBudgetCommandLimit(t *testing.T) {
+	workdir := t.TempDir()
+	testutil.CreateTestFile(t, filepath.Join(workdir, ".cursor", "rules"), "go.mdc", "---\ndescription: Go\n---\nUse gofmt and handle every error.\n")
+
+	v := viper.New()
+	v.Set("workdir", workdir)
+	ctx := cli.NewAppContext(v, nil)
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		cmd := NewBudgetCmd(ctx)
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--format", "json", "--tokenizer", "chars")
+	if err != nil {
+		t.Fatalf("budget failed: %v", err)
+	}
+	var resp struct {
+		Tokenizer   string `json:"tokenizer"`
+		TotalTokens int    `json:"totalTokens"`
+		Targets     []struct {
+			Target string `json:"target"`
+		} `json:"targets"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if resp.Tokenizer != "chars" || resp.TotalTokens != 9 || len(resp.Targets) != 1 || resp.Targets[0].Target != "cursor" {
+		t.Fatalf("unexpected budget %+v", resp)
+	}
+
+	if _, err := run("--limit", "5", "--tokenizer", "chars"); err == nil || !strings.Contains(err.Error(), "token budget exceeded: 9 > 5") {
+		t.Fatalf("expected budget exceeded error, got %v", err)
+	}
+	if _, err := run("--format", "xml"); err == nil {
+		t.Fatal("expected unknown format error")
+	}
+}
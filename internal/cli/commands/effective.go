@@ -1,15 +1,20 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 	"github.com/spf13/cobra"
 )
 
 // NewEffectiveCmd returns the effective command with multi-target support.
 func NewEffectiveCmd(ctx *cli.AppContext) *cobra.Command {
 	var targetFlag string
+	var formatFlag string
+	var forFlag string
 
 	cmd := &cobra.Command{
 		Use:   "effective",
@@ -17,6 +22,9 @@ func NewEffectiveCmd(ctx *cli.AppContext) *cobra.Command {
 		Long: `Display the merged rules that would be active in the current workspace.
 For Copilot targets, simulates the non-deterministic merge order.
 
+--format json|yaml|markdown reports each rule with its source file, @file target,
+description, globs, alwaysApply, body, size and estimated token count.
+
 Examples:
   # Show Cursor rules
   cursor-rules effective
@@ -25,10 +33,26 @@ Examples:
   cursor-rules effective --target copilot-instr
 
   # Show OpenCode rule files
-  cursor-rules effective --target opencode-rules`,
+  cursor-rules effective --target opencode-rules
+
+  # Rules with metadata, for scripts
+  cursor-rules effective --format json
+
+  # Which rules apply when editing a file (cursor, copilot-instr, opencode-rules, claude-rules,
+  # windsurf, cline-rules and roo-rules)
+  cursor-rules effective --for web/src/app.tsx`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			format := strings.TrimSpace(formatFlag)
+			switch format {
+			case "text", "json", "yaml", "markdown":
+			default:
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json, yaml, markdown)", formatFlag)
+			}
 			workdir := cli.GetOptionalFlag(cmd, "workdir")
+			if strings.TrimSpace(forFlag) != "" {
+				return runEffectiveFor(ctx, cmd, workdir, forFlag, format, targetFlag)
+			}
 			req := app.EffectiveRequest{
 				Target:  targetFlag,
 				Workdir: workdir,
@@ -37,13 +61,48 @@ Examples:
 			if err != nil {
 				return err
 			}
-			p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			out := cmd.OutOrStdout()
+			switch format {
+			case "json":
+				return display.RenderJSON(out, resp)
+			case "yaml":
+				return display.RenderYAML(out, resp)
+			case "markdown":
+				display.RenderEffectiveMarkdown(out, resp)
+				return nil
+			}
+			p := display.NewPrinter(ctx.Messenger(), out, cmd.ErrOrStderr())
 			display.RenderEffectiveResponse(p, resp)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "target format to show: cursor|copilot-instr|copilot-prompt|opencode-rules")
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "target format to show: cursor|copilot-instr|copilot-prompt|opencode-rules|claude-rules|agents-md|windsurf|cline-rules|roo-rules|gemini-md")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json|yaml|markdown")
+	cmd.Flags().StringVar(&forFlag, "for", "", "show the rules that apply when editing this file (relative to the project root)")
 
 	return cmd
 }
+
+func runEffectiveFor(ctx *cli.AppContext, cmd *cobra.Command, workdir, path, format, target string) error {
+	req := app.EffectiveForRequest{Workdir: workdir, Path: path}
+	if cmd.Flags().Changed("target") {
+		req.Target = target
+	}
+	resp, err := ctx.App().EffectiveFor(req)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		return display.RenderJSON(out, resp)
+	case "yaml":
+		return display.RenderYAML(out, resp)
+	case "markdown":
+		display.RenderEffectiveForMarkdown(out, resp)
+		return nil
+	}
+	display.RenderEffectiveForResponse(display.NewPrinter(ctx.Messenger(), out, cmd.ErrOrStderr()), resp)
+	return nil
+}
@@ -1,25 +1,49 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 	"github.com/spf13/cobra"
 )
 
 // NewInfoCmd returns the info command which prints detailed diagnostics.
 func NewInfoCmd(ctx *cli.AppContext) *cobra.Command {
+	var profileFlag bool
+	var formatFlag string
+
 	cmd := &cobra.Command{
 		Use:   "info",
 		Short: "Show detailed information about the CLI binary and workspace",
+		Example: `  # Show configuration and workspace diagnostics
+  cursor-rules info
+
+  # Profile the project: languages, frameworks, workspaces, test frameworks and CI
+  cursor-rules info --profile
+
+  # Machine-readable project profile
+  cursor-rules info --profile --format json`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			out := cmd.OutOrStdout()
 			style := display.StyleForWriter(out)
+			format := strings.TrimSpace(formatFlag)
+			if format != "text" && format != "json" {
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json)", formatFlag)
+			}
+			if format == "json" && !profileFlag {
+				return errors.New(errors.CodeInvalidArgument, "--format json requires --profile")
+			}
 			cfgPath := cli.GetOptionalFlag(cmd, "config")
-			resp, err := ctx.App().Info(app.InfoRequest{ConfigPath: cfgPath})
+			resp, err := ctx.App().Info(app.InfoRequest{ConfigPath: cfgPath, Profile: profileFlag})
 			if err != nil {
 				return err
 			}
+			if format == "json" {
+				return display.RenderJSON(out, resp.Profile)
+			}
 
 			view := display.InfoView{
 				Binary: display.BinaryInfo{
@@ -41,10 +65,13 @@ func NewInfoCmd(ctx *cli.AppContext) *cobra.Command {
 				Workdir:      resp.Workdir,
 				Presets:      resp.Presets,
 				Commands:     resp.Commands,
+				Profile:      resp.Profile,
 			}
 			display.RenderInfo(out, style, &view)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&profileFlag, "profile", false, "profile the project: languages, frameworks, workspaces, test frameworks and CI")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json (json requires --profile)")
 	return cmd
 }
@@ -2,6 +2,9 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -36,3 +39,36 @@ func TestInfoCommandPrintsSections(t *testing.T) {
 		}
 	}
 }
+
+func TestInfoCommandProfileJSON(t *testing.T) {
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module example.com/x\n"), 0o644); err != nil {
+		t.Fatalf("write go.mod: %v", err)
+	}
+	v := viper.New()
+	v.Set("workdir", tmp)
+	ctx := cli.NewAppContext(v, nil)
+
+	root := &cobra.Command{Use: "cursor-rules"}
+	root.PersistentFlags().StringP("workdir", "w", "", "workspace root")
+	root.AddCommand(NewInfoCmd(ctx))
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs([]string{"info", "--workdir", tmp, "--profile", "--format", "json"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("info --profile failed: %v", err)
+	}
+	var profile struct {
+		Type      string   `json:"type"`
+		Languages []string `json:"languages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &profile); err != nil {
+		t.Fatalf("decode profile: %v; output=%s", err, buf.String())
+	}
+	if profile.Type != "go" || len(profile.Languages) != 1 || profile.Languages[0] != "go" {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+}
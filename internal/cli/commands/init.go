@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"bufio"
+	"fmt"
+	"strings"
+
 	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
@@ -9,9 +13,27 @@ import (
 
 // NewInitCmd returns the init command
 func NewInitCmd(ctx *cli.AppContext) *cobra.Command {
+	var yesFlag bool
+	var noRecommendFlag bool
+	var packageDirFlag string
+
 	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize project with .cursor/rules, commands, skills, agents, and hooks",
+		Long: `Initialize the project's .cursor directories, then profile the project and recommend
+rules, commands, skills, agents and hooks from the package sources whose name, tags or
+keywords match the detected languages, frameworks, test frameworks and CI systems.
+
+Each recommendation is confirmed on stdin; --yes installs all of them without prompting.
+Recommendations left unanswered when stdin ends are not installed.`,
+		Example: `  # Initialize and choose from the recommended presets
+  cursor-rules init
+
+  # Initialize and install every recommendation (for scripts)
+  cursor-rules init --yes
+
+  # Only create the directories
+  cursor-rules init --no-recommend`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			workdir := cli.GetOptionalFlag(cmd, "workdir")
 			resp, err := ctx.App().InitProject(app.InitRequest{Workdir: workdir})
@@ -20,8 +42,59 @@ func NewInitCmd(ctx *cli.AppContext) *cobra.Command {
 			}
 			p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
 			display.RenderInitResponse(p, resp)
+			if noRecommendFlag {
+				return nil
+			}
+
+			recs, err := ctx.App().Recommend(app.RecommendRequest{Workdir: resp.Workdir, PackageDir: packageDirFlag})
+			if err != nil {
+				return err
+			}
+			display.RenderRecommendResponse(p, recs)
+			if len(recs.Recommendations) == 0 {
+				return nil
+			}
+
+			accepted := recs.Recommendations
+			if !yesFlag {
+				accepted = confirmRecommendations(cmd, recs.Recommendations)
+			}
+			if len(accepted) == 0 {
+				p.Info("Nothing installed; re-run with --yes to install all recommendations\n")
+				return nil
+			}
+			results, err := ctx.App().InstallRecommendations(app.InstallRecommendationsRequest{
+				Workdir:         resp.Workdir,
+				Recommendations: accepted,
+			})
+			if err != nil {
+				return err
+			}
+			display.RenderInstallResults(p, results)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "install all recommendations without prompting")
+	cmd.Flags().BoolVar(&noRecommendFlag, "no-recommend", false, "only create the project directories")
+	cmd.Flags().StringVar(&packageDirFlag, "package-dir", "", "package directory to recommend from (default: configured sources)")
 	return cmd
 }
+
+// confirmRecommendations asks for each recommendation on stdin. Input ending early (for example
+// a non-interactive stdin) declines the remaining ones.
+func confirmRecommendations(cmd *cobra.Command, recs []app.Recommendation) []app.Recommendation {
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	var accepted []app.Recommendation
+	for _, rec := range recs {
+		fmt.Fprintf(cmd.OutOrStdout(), "Install %s %s? [Y/n] ", rec.Kind, rec.Label)
+		if !scanner.Scan() {
+			fmt.Fprintln(cmd.OutOrStdout())
+			break
+		}
+		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if answer == "" || answer == "y" || answer == "yes" {
+			accepted = append(accepted, rec)
+		}
+	}
+	return accepted
+}
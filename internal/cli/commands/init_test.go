@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+	"github.com/spf13/viper"
+)
+
+func setupInitFixture(t *testing.T) (workdir string, ctx *cli.AppContext) {
+	t.Helper()
+	packageDir := t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+	testutil.CreateTestFile(t, packageDir, "golang.mdc", "---\ndescription: Go\n---\nGo rules")
+	testutil.CreateTestFile(t, packageDir, "python.mdc", "---\ndescription: Python\n---\nPython rules")
+	testutil.CreateTestFile(t, packageDir, "api.mdc", "---\ndescription: API\ntags: [gin]\n---\nAPI rules")
+
+	workdir = t.TempDir()
+	testutil.CreateTestFile(t, workdir, "go.mod", "module example.com/api\n\nrequire github.com/gin-gonic/gin v1.9.1\n")
+	v := viper.New()
+	v.Set("workdir", workdir)
+	return workdir, cli.NewAppContext(v, nil)
+}
+
+func TestInitYesInstallsRecommendations(t *testing.T) {
+	workdir, ctx := setupInitFixture(t)
+
+	cmd := NewInitCmd(ctx)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init --yes failed: %v", err)
+	}
+
+	expectExists(t, filepath.Join(workdir, ".cursor", "rules", "golang.mdc"))
+	expectExists(t, filepath.Join(workdir, ".cursor", "rules", "api.mdc"))
+	if _, err := os.Stat(filepath.Join(workdir, ".cursor", "rules", "python.mdc")); !os.IsNotExist(err) {
+		t.Fatalf("python.mdc should not be recommended for a Go project: %v", err)
+	}
+}
+
+func TestInitPromptsForEachRecommendation(t *testing.T) {
+	workdir, ctx := setupInitFixture(t)
+
+	cmd := NewInitCmd(ctx)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	// Recommendations are listed in package order: api, then golang.
+	cmd.SetIn(strings.NewReader("n\ny\n"))
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	expectExists(t, filepath.Join(workdir, ".cursor", "rules", "golang.mdc"))
+	if _, err := os.Stat(filepath.Join(workdir, ".cursor", "rules", "api.mdc")); !os.IsNotExist(err) {
+		t.Fatalf("declined api.mdc should not be installed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Install rule api? [Y/n]") {
+		t.Fatalf("expected a prompt per recommendation, got:\n%s", out.String())
+	}
+}
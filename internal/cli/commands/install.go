@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
@@ -12,10 +13,7 @@ import (
 
 // NewInstallCmd returns the install command with subcommands for rules, commands, skills, agents, hooks.
 func NewInstallCmd(ctx *cli.AppContext) *cobra.Command {
-	var excludeFlag []string
-	var noFlattenFlag bool
-	var targetFlag string
-	var allTargetsFlag bool
+	var opts installRulesOptions
 
 	cmd := &cobra.Command{
 		Use:   "install [name]",
@@ -27,14 +25,28 @@ Examples:
   cursor-rules install frontend
   cursor-rules install frontend --target copilot-instr
   cursor-rules install frontend --target opencode-rules
+  cursor-rules install frontend --target claude-rules
+  cursor-rules install frontend --target agents-md
+  cursor-rules install frontend --target windsurf
+  cursor-rules install frontend --target cline-rules
+  cursor-rules install frontend --target roo-rules
+  cursor-rules install frontend --target gemini-md
+
+  # Pick a package source explicitly when several are configured
+  cursor-rules install org:frontend
+
+  # Show the dependency plan from the package manifest without installing
+  cursor-rules install frontend --dry-run
 
   # Install via subcommands (no --target needed)
   cursor-rules install commands my-cmd
   cursor-rules install commands all
+  cursor-rules install commands all --target gemini
   cursor-rules install skills deploy
   cursor-rules install skills all
   cursor-rules install agents code-reviewer
   cursor-rules install hooks my-hooks
+  cursor-rules install hooks my-hooks --target claude
   cursor-rules install all`,
 		Args: cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -44,14 +56,17 @@ Examples:
 			if len(args) == 0 {
 				return cmd.Help()
 			}
-			return runInstallRules(ctx, cmd, args[0], excludeFlag, noFlattenFlag, targetFlag, allTargetsFlag)
+			return runInstallRules(ctx, cmd, args[0], opts)
 		},
 	}
 
-	cmd.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude when installing a package (can be repeated)")
-	cmd.Flags().BoolVarP(&noFlattenFlag, "no-flatten", "n", false, "preserve package directory structure")
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "rules output target: cursor|copilot-instr|copilot-prompt|opencode-rules")
-	cmd.Flags().BoolVar(&allTargetsFlag, "all-targets", false, "install to all targets in manifest")
+	cmd.Flags().StringArrayVar(&opts.exclude, "exclude", []string{}, "patterns to exclude when installing a package (can be repeated)")
+	cmd.Flags().BoolVarP(&opts.noFlatten, "no-flatten", "n", false, "preserve package directory structure")
+	cmd.Flags().StringVar(&opts.target, "target", "cursor", "rules output target: cursor|copilot-instr|copilot-prompt|opencode-rules|claude-rules|agents-md|windsurf|cline-rules|roo-rules|gemini-md")
+	cmd.Flags().BoolVar(&opts.allTargets, "all-targets", false, "install to all targets in manifest")
+	cmd.Flags().BoolVar(&opts.noDeps, "no-deps", false, "do not install dependencies declared in the package manifest")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the install plan without installing")
+	cmd.Flags().BoolVar(&opts.strict, "strict-templates", false, "fail on template placeholders that cannot be rendered")
 
 	cmd.AddCommand(newInstallRulesCmd(ctx))
 	cmd.AddCommand(newInstallCommandsCmd(ctx))
@@ -63,7 +78,18 @@ Examples:
 	return cmd
 }
 
-func runInstallRules(ctx *cli.AppContext, cmd *cobra.Command, name string, exclude []string, noFlatten bool, target string, allTargets bool) error {
+// installRulesOptions holds the flags shared by `install <name>` and `install rules <name>`.
+type installRulesOptions struct {
+	exclude    []string
+	noFlatten  bool
+	target     string
+	allTargets bool
+	noDeps     bool
+	dryRun     bool
+	strict     bool
+}
+
+func runInstallRules(ctx *cli.AppContext, cmd *cobra.Command, name string, opts installRulesOptions) error {
 	workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
 	if err != nil {
 		return err
@@ -72,11 +98,14 @@ func runInstallRules(ctx *cli.AppContext, cmd *cobra.Command, name string, exclu
 		Name:              name,
 		Workdir:           workdir,
 		Global:            isUser,
-		Excludes:          exclude,
-		NoFlatten:         noFlatten,
-		Target:            target,
-		AllTargets:        allTargets,
+		Excludes:          opts.exclude,
+		NoFlatten:         opts.noFlatten,
+		Target:            opts.target,
+		AllTargets:        opts.allTargets,
 		ShowInstallMethod: true,
+		NoDeps:            opts.noDeps,
+		DryRun:            opts.dryRun,
+		StrictTemplates:   opts.strict,
 	}
 	resp, err := ctx.App().Install(req)
 	if err != nil {
@@ -88,10 +117,7 @@ func runInstallRules(ctx *cli.AppContext, cmd *cobra.Command, name string, exclu
 }
 
 func newInstallRulesCmd(ctx *cli.AppContext) *cobra.Command {
-	var excludeFlag []string
-	var noFlattenFlag bool
-	var targetFlag string
-	var allTargetsFlag bool
+	var opts installRulesOptions
 
 	c := &cobra.Command{
 		Use:   "rules [name|all]",
@@ -110,10 +136,10 @@ func newInstallRulesCmd(ctx *cli.AppContext) *cobra.Command {
 				req := &app.InstallAllRequest{
 					Workdir:                workdir,
 					Global:                 isUser,
-					Excludes:               excludeFlag,
-					NoFlatten:              noFlattenFlag,
-					Target:                 targetFlag,
-					AllTargets:             allTargetsFlag,
+					Excludes:               opts.exclude,
+					NoFlatten:              opts.noFlatten,
+					Target:                 opts.target,
+					AllTargets:             opts.allTargets,
 					ShowInstallMethodFirst: true,
 				}
 				resp, err := ctx.App().InstallAll(req)
@@ -124,13 +150,16 @@ func newInstallRulesCmd(ctx *cli.AppContext) *cobra.Command {
 				display.RenderInstallAllResponse(p, resp)
 				return nil
 			}
-			return runInstallRules(ctx, cmd, args[0], excludeFlag, noFlattenFlag, targetFlag, allTargetsFlag)
+			return runInstallRules(ctx, cmd, args[0], opts)
 		},
 	}
-	c.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude")
-	c.Flags().BoolVarP(&noFlattenFlag, "no-flatten", "n", false, "preserve package structure")
-	c.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|copilot-instr|copilot-prompt|opencode-rules")
-	c.Flags().BoolVar(&allTargetsFlag, "all-targets", false, "install to all targets in manifest")
+	c.Flags().StringArrayVar(&opts.exclude, "exclude", []string{}, "patterns to exclude")
+	c.Flags().BoolVarP(&opts.noFlatten, "no-flatten", "n", false, "preserve package structure")
+	c.Flags().StringVar(&opts.target, "target", "cursor", "output target: cursor|copilot-instr|copilot-prompt|opencode-rules|claude-rules|agents-md|windsurf|cline-rules|roo-rules|gemini-md")
+	c.Flags().BoolVar(&opts.allTargets, "all-targets", false, "install to all targets in manifest")
+	c.Flags().BoolVar(&opts.noDeps, "no-deps", false, "do not install dependencies declared in the package manifest")
+	c.Flags().BoolVar(&opts.dryRun, "dry-run", false, "print the install plan without installing")
+	c.Flags().BoolVar(&opts.strict, "strict-templates", false, "fail on template placeholders that cannot be rendered")
 	return c
 }
 
@@ -142,7 +171,7 @@ func newInstallCommandsCmd(ctx *cli.AppContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "commands [name|all]",
 		Short: "Install a command or all commands",
-		Long:  `Install a command from the package dir. Cursor target installs commands as Cursor-compatible skills in .cursor/skills/. OpenCode target installs native command markdown files or directories in .opencode/commands/. Use "all" to install the entire commands collection.`,
+		Long:  `Install a command from the package dir. Cursor target installs commands as Cursor-compatible skills in .cursor/skills/. OpenCode and Claude targets install native command markdown files or directories in .opencode/commands/ or .claude/commands/. The Gemini target converts commands to TOML files in .gemini/commands/. Use "all" to install the entire commands collection.`,
 		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cli.ShowHelpIfReservedArg(cmd, args) {
@@ -156,7 +185,7 @@ func newInstallCommandsCmd(ctx *cli.AppContext) *cobra.Command {
 			if len(args) > 0 {
 				name = args[0]
 			}
-			target, err := resolveNativeInstallTarget(targetFlag, "commands", "opencode-commands")
+			target, err := resolveNativeInstallTarget(targetFlag, "commands", "opencode", "claude", "gemini")
 			if err != nil {
 				return err
 			}
@@ -197,7 +226,7 @@ func newInstallCommandsCmd(ctx *cli.AppContext) *cobra.Command {
 	}
 	cmd.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude")
 	cmd.Flags().BoolVarP(&noFlattenFlag, "no-flatten", "n", false, "preserve package structure")
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode")
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode|claude|gemini")
 	return cmd
 }
 
@@ -208,7 +237,7 @@ func newInstallSkillsCmd(ctx *cli.AppContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "skills [name|all]",
 		Short: "Install a skill or all skills",
-		Long:  `Install a skill from the package dir. Cursor target installs to .cursor/skills/<name>/. OpenCode target installs natively to .opencode/skills/<name>/SKILL.md. With no name, installs all skills.`,
+		Long:  `Install a skill from the package dir. Cursor target installs to .cursor/skills/<name>/. OpenCode and Claude targets install natively to .opencode/skills/<name>/SKILL.md or .claude/skills/<name>/SKILL.md. With no name, installs all skills.`,
 		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cli.ShowHelpIfReservedArg(cmd, args) {
@@ -222,7 +251,7 @@ func newInstallSkillsCmd(ctx *cli.AppContext) *cobra.Command {
 			if len(args) > 0 {
 				name = args[0]
 			}
-			target, err := resolveNativeInstallTarget(targetFlag, "skills", "opencode-skills")
+			target, err := resolveNativeInstallTarget(targetFlag, "skills", "opencode", "claude")
 			if err != nil {
 				return err
 			}
@@ -260,7 +289,7 @@ func newInstallSkillsCmd(ctx *cli.AppContext) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude")
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode")
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode|claude")
 	return cmd
 }
 
@@ -271,7 +300,7 @@ func newInstallAgentsCmd(ctx *cli.AppContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "agents [name|all]",
 		Short: "Install an agent or all agents",
-		Long:  `Install an agent from the package dir. Cursor target installs to .cursor/agents/<name>.md. OpenCode target installs natively to .opencode/agents/<name>.md. With no name, installs all agents.`,
+		Long:  `Install an agent from the package dir. Cursor target installs to .cursor/agents/<name>.md. OpenCode target installs natively to .opencode/agents/<name>.md; Claude target installs to .claude/agents/<name>.md with the frontmatter translated to Claude Code subagent fields. With no name, installs all agents.`,
 		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cli.ShowHelpIfReservedArg(cmd, args) {
@@ -285,7 +314,7 @@ func newInstallAgentsCmd(ctx *cli.AppContext) *cobra.Command {
 			if len(args) > 0 {
 				name = args[0]
 			}
-			target, err := resolveNativeInstallTarget(targetFlag, "agents", "opencode-agents")
+			target, err := resolveNativeInstallTarget(targetFlag, "agents", "opencode", "claude")
 			if err != nil {
 				return err
 			}
@@ -323,15 +352,17 @@ func newInstallAgentsCmd(ctx *cli.AppContext) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude")
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode")
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|opencode|claude")
 	return cmd
 }
 
 func newInstallHooksCmd(ctx *cli.AppContext) *cobra.Command {
+	var targetFlag string
+
 	cmd := &cobra.Command{
 		Use:   "hooks [preset|all]",
 		Short: "Install a hook preset or all hook presets",
-		Long:  `Install a hook preset from the package dir into .cursor/hooks.json and .cursor/hooks/. With no preset, installs all hook presets.`,
+		Long:  `Install a hook preset from the package dir into .cursor/hooks.json and .cursor/hooks/. Claude target translates the preset's events into the hooks of .claude/settings.json and copies scripts to .claude/hooks/. With no preset, installs all hook presets.`,
 		Args:  cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if cli.ShowHelpIfReservedArg(cmd, args) {
@@ -341,11 +372,15 @@ func newInstallHooksCmd(ctx *cli.AppContext) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			target, err := resolveNativeInstallTarget(targetFlag, "hooks", "claude")
+			if err != nil {
+				return err
+			}
 			if len(args) == 0 || args[0] == "all" {
 				req := &app.InstallAllRequest{
 					Workdir:                workdir,
 					Global:                 isUser,
-					Target:                 "hooks",
+					Target:                 target,
 					ShowInstallMethodFirst: true,
 				}
 				resp, err := ctx.App().InstallAll(req)
@@ -360,7 +395,7 @@ func newInstallHooksCmd(ctx *cli.AppContext) *cobra.Command {
 				Name:              args[0],
 				Workdir:           workdir,
 				Global:            isUser,
-				Target:            "hooks",
+				Target:            target,
 				ShowInstallMethod: true,
 			}
 			resp, err := ctx.App().Install(req)
@@ -372,6 +407,7 @@ func newInstallHooksCmd(ctx *cli.AppContext) *cobra.Command {
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|claude")
 	return cmd
 }
 
@@ -411,18 +447,21 @@ func newInstallAllCmd(ctx *cli.AppContext) *cobra.Command {
 	}
 	cmd.Flags().StringArrayVar(&excludeFlag, "exclude", []string{}, "patterns to exclude")
 	cmd.Flags().BoolVarP(&noFlattenFlag, "no-flatten", "n", false, "preserve package structure")
-	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|copilot-instr|copilot-prompt|opencode-rules|commands|skills|agents|hooks")
+	cmd.Flags().StringVar(&targetFlag, "target", "cursor", "output target: cursor|copilot-instr|copilot-prompt|opencode-rules|claude-rules|agents-md|windsurf|cline-rules|roo-rules|gemini-md|commands|skills|agents|hooks")
 	cmd.Flags().BoolVar(&allTargetsFlag, "all-targets", false, "install to all targets in manifest")
 	return cmd
 }
 
-func resolveNativeInstallTarget(target, cursorTarget, opencodeTarget string) (string, error) {
-	switch strings.TrimSpace(target) {
-	case "", "cursor":
-		return cursorTarget, nil
-	case "opencode":
-		return opencodeTarget, nil
+// resolveNativeInstallTarget maps a --target tool to the native target for kind: "cursor" (or
+// empty) selects kind itself, any of tools selects "<tool>-<kind>".
+func resolveNativeInstallTarget(target, kind string, tools ...string) (string, error) {
+	target = strings.TrimSpace(target)
+	switch {
+	case target == "" || target == "cursor":
+		return kind, nil
+	case slices.Contains(tools, target):
+		return target + "-" + kind, nil
 	default:
-		return "", errors.Newf(errors.CodeInvalidArgument, "unknown target: %s (available: cursor, opencode)", target)
+		return "", errors.Newf(errors.CodeInvalidArgument, "unknown target: %s (available: %s)", target, strings.Join(append([]string{"cursor"}, tools...), ", "))
 	}
 }
@@ -11,12 +11,14 @@ import (
 func NewListCmd(ctx *cli.AppContext) *cobra.Command {
 	var targetFlag string
 	var kindFlag string
+	var installedFlag bool
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List shared content grouped by concrete target",
 		Long: `List shared package content grouped by the concrete install targets it can feed.
 By default this reads from the configured package directory. With --global (or --dir user),
-	it lists installed user resources instead.`,
+	it lists installed user resources instead. With --installed, it lists resources installed in the
+project and marks items not recorded in .cursor/cursor-rules.lock as unmanaged.`,
 		Example: `  # Show all targets
   cursor-rules list
 
@@ -27,13 +29,22 @@ By default this reads from the configured package directory. With --global (or -
   cursor-rules list --kind command
 
   # Show installed global Copilot prompts
-  cursor-rules list --global --target copilot-prompt`,
+  cursor-rules list --global --target copilot-prompt
+
+  # Show resources installed in this project (hand-written files are marked unmanaged)
+  cursor-rules list --installed`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			_, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
+			workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
 			if err != nil {
 				return err
 			}
-			resp, err := ctx.App().ListRules(app.ListRequest{Global: isUser, Target: targetFlag, Kind: kindFlag})
+			resp, err := ctx.App().ListRules(app.ListRequest{
+				Global:    isUser,
+				Installed: installedFlag && !isUser,
+				Workdir:   workdir,
+				Target:    targetFlag,
+				Kind:      kindFlag,
+			})
 			if err != nil {
 				return err
 			}
@@ -44,5 +55,6 @@ By default this reads from the configured package directory. With --global (or -
 	}
 	cmd.Flags().StringVar(&targetFlag, "target", "", "list only the specified concrete target")
 	cmd.Flags().StringVar(&kindFlag, "kind", "", "filter targets by kind: rule|command|skill|agent|hooks")
+	cmd.Flags().BoolVar(&installedFlag, "installed", false, "list resources installed in the project instead of the package directory")
 	return cmd
 }
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewManifestCmd groups package manifest helpers under `cursor-rules manifest`.
+func NewManifestCmd(ctx *cli.AppContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Inspect package manifests (cursor-rules-manifest.yaml)",
+	}
+
+	cmd.AddCommand(newManifestValidateCmd(ctx))
+	return cmd
+}
+
+func newManifestValidateCmd(ctx *cli.AppContext) *cobra.Command {
+	var packageDirFlag string
+	var formatFlag string
+
+	cmd := &cobra.Command{
+		Use:   "validate <package>",
+		Short: "Validate a package manifest against the manifest schema",
+		Long: `Validate the cursor-rules-manifest.yaml of a package.
+
+Unknown keys, unknown targets, unsupported schema versions, invalid prompt modes and
+malformed exclude patterns are reported as file:line:column errors. The command exits
+non-zero when any issue is found. <package> may be a package name, a "source:name"
+reference or a path to a package directory.`,
+		Example: `  # Validate the manifest of the frontend package
+  cursor-rules manifest validate frontend
+
+  # Validate a package directory before publishing it
+  cursor-rules manifest validate ./rules/frontend --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cli.ShowHelpIfReservedArg(cmd, args) {
+				return nil
+			}
+			format := strings.TrimSpace(formatFlag)
+			if format != "text" && format != "json" {
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json)", formatFlag)
+			}
+			resp, err := ctx.App().ValidateManifest(app.ManifestValidateRequest{
+				Name:       args[0],
+				PackageDir: packageDirFlag,
+				ConfigPath: cli.GetOptionalFlag(cmd, "config"),
+			})
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				if err := display.RenderJSON(cmd.OutOrStdout(), resp); err != nil {
+					return err
+				}
+			} else {
+				p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+				display.RenderManifestValidateResponse(p, resp)
+			}
+			if !resp.Valid() {
+				cmd.SilenceUsage = true
+				return errors.Newf(errors.CodeFailedPrecondition, "manifest validation failed: %d issue(s)", len(resp.Issues))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&packageDirFlag, "package-dir", "", "package directory to look the package up in (default: configured sources)")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json")
+	return cmd
+}
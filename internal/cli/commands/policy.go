@@ -1,22 +1,73 @@
 package commands
 
 import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 	"github.com/spf13/cobra"
 )
 
 // NewPolicyCmd returns the policy command
 func NewPolicyCmd(ctx *cli.AppContext) *cobra.Command {
+	var fileFlag string
+	var formatFlag string
+
 	cmd := &cobra.Command{
 		Use:   "policy",
-		Short: "Manage application policy for presets",
+		Short: "Evaluate installed rules against a declarative policy file",
+		Long: `Evaluate the rules installed in the current project against a policy file.
+
+By default the policy is read from policy.yaml in the package directory. The command
+exits non-zero when any violation is found, so it can be used to gate pull requests.
+
+Policy file example:
+  version: "1"
+  targets: [cursor, copilot-instr]
+  requiredPresets: [git, security]
+  forbiddenPresets: [legacy]
+  requiredFrontmatter: [description]
+  maxBodyBytes:
+    default: 16000
+    copilot-instr: 8000`,
+		Example: `  # Evaluate the package policy against the current project
+  cursor-rules policy
+
+  # Evaluate an explicit policy file and emit JSON for CI
+  cursor-rules policy --file ./policy.yaml --format json`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			resp := ctx.App().Policy()
-			p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
-			p.Info("%s\n", resp.Message)
+			format := strings.TrimSpace(formatFlag)
+			if format != "text" && format != "json" {
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json)", formatFlag)
+			}
+			req := app.PolicyRequest{
+				PolicyPath: fileFlag,
+				Workdir:    cli.GetOptionalFlag(cmd, "workdir"),
+				ConfigPath: cli.GetOptionalFlag(cmd, "config"),
+			}
+			resp, err := ctx.App().Policy(req)
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				if err := display.RenderJSON(cmd.OutOrStdout(), resp); err != nil {
+					return err
+				}
+			} else {
+				p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+				display.RenderPolicyResponse(p, resp)
+			}
+			if !resp.Passed() {
+				cmd.SilenceUsage = true
+				return errors.Newf(errors.CodeFailedPrecondition, "policy check failed: %d violation(s)", len(resp.Violations))
+			}
 			return nil
 		},
 	}
+	cmd.Flags().StringVarP(&fileFlag, "file", "f", "", "policy file path (default: <packageDir>/policy.yaml)")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json")
 	return cmd
 }
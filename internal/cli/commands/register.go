@@ -7,11 +7,16 @@ func RegisterAll() {
 	cli.Register(
 		NewInstallCmd,
 		NewRemoveCmd,
+		NewRestoreCmd,
+		NewStatusCmd,
+		NewUpdateCmd,
 		NewSyncCmd,
 		NewWatchCmd,
 		NewListCmd,
 		NewEffectiveCmd,
+		NewBudgetCmd,
 		NewPolicyCmd,
+		NewManifestCmd,
 		NewInitCmd,
 		NewTransformCmd,
 		NewConfigCmd,
@@ -32,6 +32,9 @@ only when exactly one installed target matches the given name; if multiple targe
   # Remove configured hooks
   cursor-rules remove --type hooks
 
+  # Remove a hook preset installed into .claude/settings.json
+  cursor-rules remove guard --target claude-hooks
+
   # Remove a global OpenCode skill install
   cursor-rules remove deploy --target opencode-skills --global`,
 		Args: cobra.RangeArgs(0, 1),
@@ -43,7 +46,7 @@ only when exactly one installed target matches the given name; if multiple targe
 			if len(args) > 0 {
 				name = args[0]
 			}
-			if targetFlag == "" && typeFlag != "hooks" && name == "" {
+			if (targetFlag == "" && typeFlag != "hooks" || targetFlag == "claude-hooks") && name == "" {
 				return errNameRequired
 			}
 			workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
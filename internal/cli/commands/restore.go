@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewRestoreCmd returns the restore command. Accepts AppContext for parity.
+func NewRestoreCmd(ctx *cli.AppContext) *cobra.Command {
+	var packageDirFlag string
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Reinstall every resource recorded in .cursor/cursor-rules.lock",
+		Long: `Reinstall every rule, command, skill, agent and hook preset recorded in the project's
+.cursor/cursor-rules.lock, for every recorded target.
+
+All sources are checked before anything is written. If a recorded preset no longer exists in
+the package directory, restore fails and lists every missing source.`,
+		Example: `  # Reproduce a teammate's setup after cloning
+  cursor-rules restore
+
+  # Restore from a different package checkout
+  cursor-rules restore --package-dir ~/src/team-rules`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
+			if err != nil {
+				return err
+			}
+			if isUser {
+				return errors.New(errors.CodeInvalidArgument, "restore reads the project lockfile; --global is not supported")
+			}
+			resp, err := ctx.App().Restore(app.RestoreRequest{
+				Workdir:    workdir,
+				PackageDir: packageDirFlag,
+			})
+			if err != nil {
+				return err
+			}
+			p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			display.RenderRestoreResponse(p, resp)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&packageDirFlag, "package-dir", "", "package directory to restore from (overrides the recorded one)")
+	return cmd
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewStatusCmd returns the status command. Accepts AppContext for parity.
+func NewStatusCmd(ctx *cli.AppContext) *cobra.Command {
+	var targetFlag string
+	var formatFlag string
+	var packageDirFlag string
+	var exitCodeFlag bool
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report drift between installed resources and their package sources",
+		Long: `Compare every resource recorded in .cursor/cursor-rules.lock against its package source.
+
+Each resource is re-rendered in memory and reported as:
+  up-to-date        installed files match the current source
+  outdated          the source changed since install; files were not edited locally
+  locally-modified  installed files were edited or deleted after install
+  orphaned          the source no longer exists in the package directory`,
+		Example: `  # Show drift for every installed resource
+  cursor-rules status
+
+  # Machine-readable output for scripts
+  cursor-rules status --format json
+
+  # Fail in CI when anything drifted
+  cursor-rules status --exit-code`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			format := strings.TrimSpace(formatFlag)
+			if format != "text" && format != "json" {
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json)", formatFlag)
+			}
+			workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
+			if err != nil {
+				return err
+			}
+			if isUser {
+				return errors.New(errors.CodeInvalidArgument, "status reads the project lockfile; --global is not supported")
+			}
+			resp, err := ctx.App().Status(app.StatusRequest{
+				Workdir:    workdir,
+				Target:     targetFlag,
+				PackageDir: packageDirFlag,
+			})
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				if err := display.RenderJSON(cmd.OutOrStdout(), resp); err != nil {
+					return err
+				}
+			} else {
+				p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+				display.RenderStatusResponse(p, resp)
+			}
+			if exitCodeFlag {
+				if drifted := len(resp.Resources) - resp.Count(app.StatusUpToDate); drifted > 0 {
+					cmd.SilenceUsage = true
+					return errors.Newf(errors.CodeFailedPrecondition, "%d resource(s) drifted from their sources", drifted)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&targetFlag, "target", "", "report only the specified concrete target")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json")
+	cmd.Flags().StringVar(&packageDirFlag, "package-dir", "", "package directory to compare against (overrides the recorded one)")
+	cmd.Flags().BoolVar(&exitCodeFlag, "exit-code", false, "exit non-zero when any resource is not up-to-date")
+	return cmd
+}
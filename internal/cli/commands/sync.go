@@ -15,7 +15,9 @@ func NewSyncCmd(ctx *cli.AppContext) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Sync shared presets and optionally apply to a project",
-		Args:  cobra.NoArgs,
+		Long: `Sync every configured package source. Local git checkouts are fast-forwarded; sources
+declared with a url are cloned or fetched into the managed cache and checked out at their ref.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			workdir := cli.GetOptionalFlag(cmd, "workdir")
 			cfgPath := cli.GetOptionalFlag(cmd, "config")
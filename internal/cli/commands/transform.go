@@ -10,6 +10,7 @@ import (
 // NewTransformCmd returns a command for previewing transformations.
 func NewTransformCmd(ctx *cli.AppContext) *cobra.Command {
 	var targetFlag string
+	var strictFlag bool
 
 	cmd := &cobra.Command{
 		Use:   "transform <preset>",
@@ -26,8 +27,10 @@ Example:
 				return nil
 			}
 			req := app.TransformRequest{
-				Name:   args[0],
-				Target: targetFlag,
+				Name:            args[0],
+				Target:          targetFlag,
+				Workdir:         cli.GetOptionalFlag(cmd, "workdir"),
+				StrictTemplates: strictFlag,
 			}
 			resp, err := ctx.App().TransformPreview(req)
 			if err != nil {
@@ -39,7 +42,8 @@ Example:
 		},
 	}
 
-	cmd.Flags().StringVar(&targetFlag, "target", "copilot-instr", "target format: copilot-instr|copilot-prompt|opencode-rules|cursor")
+	cmd.Flags().StringVar(&targetFlag, "target", "copilot-instr", "target format: copilot-instr|copilot-prompt|opencode-rules|claude-rules|agents-md|windsurf|cline-rules|roo-rules|gemini-md|cursor")
+	cmd.Flags().BoolVar(&strictFlag, "strict-templates", false, "fail on template placeholders that cannot be rendered")
 
 	return cmd
 }
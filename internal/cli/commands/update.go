@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/cli/display"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// NewUpdateCmd returns the update command. Accepts AppContext for parity.
+func NewUpdateCmd(ctx *cli.AppContext) *cobra.Command {
+	var targetFlag string
+	var formatFlag string
+	var packageDirFlag string
+	var forceFlag bool
+	var dryRunFlag bool
+	cmd := &cobra.Command{
+		Use:   "update [name...]",
+		Short: "Refresh installed resources whose package source changed",
+		Long: `Rewrite only the installed rules, commands, skills, agents and hooks whose package source
+changed since they were installed (see 'cursor-rules status').
+
+Locally modified resources are skipped unless --force is given. Resources whose source no
+longer exists are reported and left untouched.`,
+		Example: `  # Refresh everything that is outdated
+  cursor-rules update
+
+  # Preview what would change
+  cursor-rules update --dry-run
+
+  # Refresh one resource, overwriting local edits
+  cursor-rules update frontend --force`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := strings.TrimSpace(formatFlag)
+			if format != "text" && format != "json" {
+				return errors.Newf(errors.CodeInvalidArgument, "unknown format: %s (available: text, json)", formatFlag)
+			}
+			workdir, isUser, err := cli.ResolveDestination(ctx.App(), cmd)
+			if err != nil {
+				return err
+			}
+			if isUser {
+				return errors.New(errors.CodeInvalidArgument, "update reads the project lockfile; --global is not supported")
+			}
+			resp, err := ctx.App().Update(app.UpdateRequest{
+				Workdir:    workdir,
+				Target:     targetFlag,
+				Names:      args,
+				Force:      forceFlag,
+				DryRun:     dryRunFlag,
+				PackageDir: packageDirFlag,
+			})
+			if err != nil {
+				return err
+			}
+			if format == "json" {
+				return display.RenderJSON(cmd.OutOrStdout(), resp)
+			}
+			p := display.NewPrinter(ctx.Messenger(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			display.RenderUpdateResponse(p, resp)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&targetFlag, "target", "", "update only the specified concrete target")
+	cmd.Flags().StringVar(&formatFlag, "format", "text", "output format: text|json")
+	cmd.Flags().StringVar(&packageDirFlag, "package-dir", "", "package directory to update from (overrides the recorded one)")
+	cmd.Flags().BoolVar(&forceFlag, "force", false, "overwrite locally modified files")
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "report what would be updated without writing")
+	return cmd
+}
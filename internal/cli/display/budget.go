@@ -0,0 +1,33 @@
+package display
+
+import (
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+)
+
+// RenderBudgetResponse writes the token budget report: per-target totals with their rules,
+// largest first, then the project total against the limit.
+func RenderBudgetResponse(p Printer, resp *app.BudgetResponse) {
+	if resp == nil {
+		return
+	}
+	p.Info("Token budget for %s (tokenizer: %s)\n", resp.Workdir, resp.Tokenizer)
+	if len(resp.Targets) == 0 {
+		p.Info("No installed rules found\n")
+		return
+	}
+	for _, target := range resp.Targets {
+		p.Info("\n%s (%s): %d tokens in %d rule(s)\n", target.Target, target.SourceDir, target.Tokens, len(target.Rules))
+		for _, rule := range target.Rules {
+			p.Info("  %7d  %s\n", rule.Tokens, rule.Name)
+		}
+	}
+	p.Info("\n")
+	switch {
+	case resp.Limit <= 0:
+		p.Info("Total: %d tokens\n", resp.TotalTokens)
+	case resp.OverLimit():
+		p.Error("❌ Total: %d tokens exceeds the limit of %d (%d%%)\n", resp.TotalTokens, resp.Limit, resp.TotalTokens*100/resp.Limit)
+	default:
+		p.Success("✅ Total: %d tokens within the limit of %d (%d%%)\n", resp.TotalTokens, resp.Limit, resp.TotalTokens*100/resp.Limit)
+	}
+}
@@ -0,0 +1,110 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
+)
+
+// RenderEffectiveMarkdown writes effective rules as a markdown report: one section per rule
+// with its metadata table followed by its body.
+func RenderEffectiveMarkdown(w io.Writer, resp *app.EffectiveResponse) {
+	if resp == nil {
+		return
+	}
+	fmt.Fprintf(w, "# Effective Rules (%s)\n\n", resp.Target)
+	fmt.Fprintf(w, "Source: `%s`\n\n", resp.SourceDir)
+	if resp.Missing {
+		fmt.Fprintf(w, "> %s\n", resp.MissingReason)
+		return
+	}
+	fmt.Fprintf(w, "%d rule(s), ~%d tokens\n", len(resp.Rules), resp.TotalTokens)
+	for _, rule := range resp.Rules {
+		fmt.Fprintf(w, "\n## %s\n\n", rule.Name)
+		fmt.Fprintln(w, "| Field | Value |")
+		fmt.Fprintln(w, "| --- | --- |")
+		fmt.Fprintf(w, "| Source | `%s` |\n", rule.Source)
+		if rule.Resolved != "" {
+			fmt.Fprintf(w, "| Resolved | `%s` |\n", rule.Resolved)
+		}
+		if len(rule.Includes) > 1 {
+			fmt.Fprintf(w, "| Includes | `%s` |\n", strings.Join(rule.Includes, "`, `"))
+		}
+		if rule.Description != "" {
+			fmt.Fprintf(w, "| Description | %s |\n", markdownCell(rule.Description))
+		}
+		if len(rule.Globs) > 0 {
+			fmt.Fprintf(w, "| Globs | `%s` |\n", strings.Join(rule.Globs, "`, `"))
+		}
+		fmt.Fprintf(w, "| Always apply | %t |\n", rule.AlwaysApply)
+		fmt.Fprintf(w, "| Size | %d bytes, ~%d tokens |\n\n", rule.Size, rule.Tokens)
+		fmt.Fprintln(w, rule.Body)
+	}
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+// RenderEffectiveResponse writes effective output.
+func RenderEffectiveResponse(p Printer, resp *app.EffectiveResponse) {
+	if resp == nil {
+		return
+	}
+	if resp.Target == "cursor" {
+		var b strings.Builder
+		for _, rule := range resp.Rules {
+			b.WriteString("\n\n---\n# " + filepath.Base(rule.Source) + "\n\n" + rule.Text())
+		}
+		p.Info("%s\n", b.String())
+		return
+	}
+
+	p.Info("# Effective Rules (%s)\n\n", resp.Target)
+	p.Info("Source: %s\n\n", resp.SourceDir)
+	if resp.Missing {
+		p.Warn("%s\n", resp.MissingReason)
+		return
+	}
+	for _, rule := range resp.Rules {
+		p.Info("## %s\n\n", rule.Name)
+		p.Info("%s\n", rule.Content)
+		p.Info("\n---\n")
+	}
+}
+
+// RenderEffectiveForResponse writes the rules that apply to one file.
+func RenderEffectiveForResponse(p Printer, resp *app.EffectiveForResponse) {
+	if resp == nil {
+		return
+	}
+	if len(resp.Matches) == 0 {
+		p.Info("No installed rules apply to %s\n", resp.Path)
+		return
+	}
+	p.Info("Rules applied when editing %s (~%d tokens):\n", resp.Path, resp.TotalTokens)
+	for _, match := range resp.Matches {
+		p.Info("  %-15s %s (%s)\n", match.Target, match.Rule.Name, match.Reason)
+	}
+}
+
+// RenderEffectiveForMarkdown writes the rules that apply to one file as a markdown report.
+func RenderEffectiveForMarkdown(w io.Writer, resp *app.EffectiveForResponse) {
+	if resp == nil {
+		return
+	}
+	fmt.Fprintf(w, "# Effective Rules for `%s`\n\n", resp.Path)
+	if len(resp.Matches) == 0 {
+		fmt.Fprintln(w, "> No installed rules apply.")
+		return
+	}
+	fmt.Fprintf(w, "%d rule(s), ~%d tokens\n", len(resp.Matches), resp.TotalTokens)
+	for _, match := range resp.Matches {
+		fmt.Fprintf(w, "\n## %s (%s)\n\n", match.Rule.Name, match.Target)
+		fmt.Fprintf(w, "Matched by `%s`\n\n", match.Reason)
+		fmt.Fprintln(w, match.Rule.Body)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/core"
 )
 
 // InfoView captures structured info output.
@@ -19,6 +21,8 @@ type InfoView struct {
 	Workdir      string
 	Presets      []string
 	Commands     []string
+	// Profile is rendered when the project was profiled (info --profile).
+	Profile *core.ProjectProfile
 }
 
 // RenderInfo renders the info command output.
@@ -62,6 +66,38 @@ func RenderInfo(out io.Writer, style StyleOptions, view *InfoView) {
 			fmt.Fprintf(out, "    • %s\n", c)
 		}
 	}
+
+	if view.Profile != nil {
+		fmt.Fprintln(out, Heading("Project profile", style))
+		writeKeyValues(out, profileEntries(view.Profile))
+		for _, ws := range view.Profile.Workspaces {
+			fmt.Fprintf(out, "  workspace %s:\n", ws.Path)
+			for _, entry := range profileEntries(ws) {
+				fmt.Fprintf(out, "    %-15s : %s\n", entry.Label, entry.Value)
+			}
+		}
+	}
+}
+
+func profileEntries(p *core.ProjectProfile) []kv {
+	entries := []kv{
+		{"Type", p.Type},
+		{"Languages", joinOrNone(p.Languages)},
+		{"Frameworks", joinOrNone(p.Frameworks)},
+		{"Test frameworks", joinOrNone(p.TestFrameworks)},
+		{"CI", joinOrNone(p.CI)},
+	}
+	if p.Monorepo {
+		entries = append(entries, kv{"Workspaces", fmt.Sprintf("%d", len(p.Workspaces))})
+	}
+	return entries
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "(none)"
+	}
+	return strings.Join(items, ", ")
 }
 
 func summarizeList(items []string) string {
@@ -0,0 +1,13 @@
+package display
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// RenderJSON writes v as indented JSON for machine-readable output modes.
+func RenderJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
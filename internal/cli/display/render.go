@@ -2,6 +2,7 @@ package display
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/app"
 )
@@ -11,9 +12,27 @@ func RenderInstallResponse(p Printer, resp *app.InstallResponse) {
 	if resp == nil {
 		return
 	}
+	if resp.DryRun || len(resp.Plan) > 1 {
+		renderInstallPlan(p, resp.Plan)
+	}
+	if resp.DryRun {
+		p.Info("Dry run: nothing installed\n")
+		return
+	}
 	renderInstallResults(p, resp.Results)
 }
 
+func renderInstallPlan(p Printer, plan []app.InstallPlanStep) {
+	p.Info("Install plan:\n")
+	for idx, step := range plan {
+		if step.RequiredBy != "" {
+			p.Info("  %d. %s %s (%s) required by %s\n", idx+1, step.Kind, step.Name, step.Target, step.RequiredBy)
+			continue
+		}
+		p.Info("  %d. %s %s (%s)\n", idx+1, step.Kind, step.Name, step.Target)
+	}
+}
+
 // RenderInstallAllResponse writes install-all output.
 func RenderInstallAllResponse(p Printer, resp *app.InstallAllResponse) {
 	if resp == nil {
@@ -24,6 +43,9 @@ func RenderInstallAllResponse(p Printer, resp *app.InstallAllResponse) {
 		return
 	}
 	renderInstallResults(p, resp.Results)
+	for _, skip := range resp.Skipped {
+		p.Info("⏭️  Skipped %q (%s): %s\n", skip.Name, skip.Target, skip.Reason)
+	}
 }
 
 func renderInstallResults(p Printer, results []app.InstallResult) {
@@ -32,6 +54,9 @@ func renderInstallResults(p Printer, results []app.InstallResult) {
 			p.Info("Install method: %s\n", result.Strategy)
 		}
 		p.Success("✅ Installed %q to %s\n", result.Name, result.OutputDir)
+		for _, warning := range result.Warnings {
+			p.Warn("⚠️  %s\n", warning)
+		}
 	}
 }
 
@@ -51,14 +76,29 @@ func RenderListResponse(p Printer, resp *app.ListResponse) {
 		p.Warn("warning: %s\n", e)
 	}
 	if resp.IncludesRules() {
-		p.Info("%s\n", FormatRulesTree(resp.Tree))
+		if len(resp.Sources) > 1 {
+			for _, src := range resp.Sources {
+				p.Info("source %s:\n", src.Name)
+				p.Info("%s\n", FormatRulesTree(src.Tree))
+			}
+		} else {
+			p.Info("%s\n", FormatRulesTree(resp.Tree))
+		}
 	}
 	for _, entry := range resp.Targets {
 		if len(entry.Items) == 0 {
 			continue
 		}
 		p.Info("%s:\n", listHeading(entry))
+		unmanaged := make(map[string]struct{}, len(entry.Unmanaged))
+		for _, item := range entry.Unmanaged {
+			unmanaged[item] = struct{}{}
+		}
 		for _, item := range entry.Items {
+			if _, ok := unmanaged[item]; ok {
+				p.Info("  - %s (unmanaged)\n", item)
+				continue
+			}
 			p.Info("  - %s\n", item)
 		}
 	}
@@ -69,7 +109,16 @@ func RenderSyncResponse(p Printer, resp *app.SyncResponse) {
 	if resp == nil {
 		return
 	}
-	p.Success("Package dir: %s\n", resp.PackageDir)
+	if len(resp.Sources) > 1 || (len(resp.Sources) == 1 && resp.Sources[0].URL != "") {
+		for _, src := range resp.Sources {
+			p.Success("Source %s: %s\n", src.Name, syncSourceLocation(src))
+		}
+	} else {
+		p.Success("Package dir: %s\n", resp.PackageDir)
+	}
+	for _, src := range resp.Sources {
+		renderSyncRevision(p, src, len(resp.Sources) > 1)
+	}
 	for _, preset := range resp.Presets {
 		p.Info("- %s\n", preset)
 	}
@@ -99,39 +148,72 @@ func RenderSyncResponse(p Printer, resp *app.SyncResponse) {
 	}
 
 	for _, applied := range resp.Applied {
-		if applied.DryRun {
-			p.Info("would apply %s -> %s/.cursor/rules/\n", applied.Name, applied.Workdir)
+		if applied.Skipped != "" {
+			p.Info("skipped %s: %s\n", applied.Name, applied.Skipped)
 			continue
 		}
 		if applied.Error != "" {
 			p.Error("failed to apply %s: %s\n", applied.Name, applied.Error)
 			continue
 		}
+		if applied.DryRun {
+			p.Info("would apply %s -> %s/.cursor/rules/\n", applied.Name, applied.Workdir)
+			continue
+		}
 		p.Success("applied %s -> %s/.cursor/rules/ (method: %s)\n", applied.Name, applied.Workdir, applied.Strategy)
 	}
 }
 
-// RenderEffectiveResponse writes effective output.
-func RenderEffectiveResponse(p Printer, resp *app.EffectiveResponse) {
-	if resp == nil {
-		return
+func renderSyncRevision(p Printer, src app.SyncSource, qualify bool) {
+	label := ""
+	if qualify {
+		label = src.Name + ": "
 	}
-	if resp.Target == "cursor" {
-		p.Info("%s\n", resp.CursorContent)
+	switch {
+	case src.After == "":
 		return
+	case src.Before == "":
+		p.Info("%schecked out %s\n", label, shortCommit(src.After))
+	case src.Before == src.After:
+		p.Info("%salready up to date at %s\n", label, shortCommit(src.After))
+	default:
+		p.Info("%supdated %s -> %s\n", label, shortCommit(src.Before), shortCommit(src.After))
+		if len(src.Changes) == 0 {
+			p.Info("  no changes to rules, commands, skills, agents or hooks\n")
+		}
+		for _, change := range src.Changes {
+			p.Info("  %s %s %s\n", syncChangeSymbol(change.Change), change.Kind, change.Name)
+		}
 	}
+}
 
-	p.Info("# Effective Rules (%s)\n\n", resp.Target)
-	p.Info("Source: %s\n\n", resp.SourceDir)
-	if resp.Missing {
-		p.Warn("%s\n", resp.MissingReason)
-		return
+func syncChangeSymbol(change string) string {
+	switch change {
+	case app.SyncChangeAdded:
+		return "+"
+	case app.SyncChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
 	}
-	for _, file := range resp.Files {
-		p.Info("## %s\n\n", file.Name)
-		p.Info("%s\n", file.Content)
-		p.Info("\n---\n")
+	return commit
+}
+
+func syncSourceLocation(src app.SyncSource) string {
+	if src.URL == "" {
+		return src.PackageDir
+	}
+	ref := src.Ref
+	if ref == "" {
+		ref = "default branch"
 	}
+	return src.PackageDir + " (" + src.URL + " @ " + ref + ")"
 }
 
 // RenderTransformResponse writes transform preview output.
@@ -163,6 +245,29 @@ func RenderInitResponse(p Printer, resp *app.InitResponse) {
 	p.Success("Initialized project at %s/.cursor/rules/\n", resp.Workdir)
 }
 
+// RenderRecommendResponse writes the project profile summary and recommended resources.
+func RenderRecommendResponse(p Printer, resp *app.RecommendResponse) {
+	if resp == nil {
+		return
+	}
+	if profile := resp.Profile; profile != nil {
+		p.Info("Detected %s project (languages: %s; frameworks: %s)\n", profile.Type, joinOrNone(profile.Languages), joinOrNone(profile.Frameworks))
+	}
+	if len(resp.Recommendations) == 0 {
+		p.Info("No matching rules, commands, skills, agents or hooks found in the package sources\n")
+		return
+	}
+	p.Info("Recommended:\n")
+	for idx, rec := range resp.Recommendations {
+		p.Info("  %d. %s %s (matches %s)\n", idx+1, rec.Kind, rec.Label, strings.Join(rec.Matches, ", "))
+	}
+}
+
+// RenderInstallResults writes the outcome of installing recommendations.
+func RenderInstallResults(p Printer, results []app.InstallResult) {
+	renderInstallResults(p, results)
+}
+
 // RenderRemoveResponse writes remove output.
 func RenderRemoveResponse(p Printer, resp *app.RemoveResponse) {
 	if resp == nil {
@@ -174,9 +279,12 @@ func RenderRemoveResponse(p Printer, resp *app.RemoveResponse) {
 		}
 		if match.Kind == "hooks" {
 			p.Success("Removed hooks from target %s (%s)\n", match.Target, match.Path)
-			return
+		} else {
+			p.Success("Removed %s %q from target %s (%s)\n", match.Kind, match.Name, match.Target, match.Path)
+		}
+		if !match.Managed && !resp.Global {
+			p.Warn("note: %q was not recorded in the project lockfile\n", match.Name)
 		}
-		p.Success("Removed %s %q from target %s (%s)\n", match.Kind, match.Name, match.Target, match.Path)
 		return
 	}
 	for _, match := range resp.Matches {
@@ -190,6 +298,19 @@ func RenderRemoveResponse(p Printer, resp *app.RemoveResponse) {
 	p.Info("Nothing removed.\n")
 }
 
+// RenderRestoreResponse writes restore output.
+func RenderRestoreResponse(p Printer, resp *app.RestoreResponse) {
+	if resp == nil {
+		return
+	}
+	if len(resp.Results) == 0 {
+		p.Info("Nothing to restore from %s\n", resp.Lockfile)
+		return
+	}
+	renderInstallResults(p, resp.Results)
+	p.Success("Restored %d resource(s) from %s\n", len(resp.Results), resp.Lockfile)
+}
+
 // RenderConfigInitResponse writes config init output.
 func RenderConfigInitResponse(p Printer, resp *app.ConfigInitResponse) {
 	if resp == nil {
@@ -215,3 +336,98 @@ func RenderLinkGlobalResponse(p Printer, resp *app.LinkGlobalResponse) {
 		p.Success("  %s -> %s\n", r.Link, r.Target)
 	}
 }
+
+// RenderPolicyResponse writes policy evaluation output.
+func RenderPolicyResponse(p Printer, resp *app.PolicyResponse) {
+	if resp == nil {
+		return
+	}
+	p.Info("Policy: %s\n", resp.PolicyPath)
+	p.Info("Checked %d installed rule(s) in %s\n", resp.Checked, resp.Workdir)
+	if resp.Passed() {
+		p.Success("✅ No policy violations\n")
+		return
+	}
+	for _, v := range resp.Violations {
+		if v.Target != "" {
+			p.Error("❌ [%s] %s (%s): %s\n", v.Rule, v.Name, v.Target, v.Message)
+			continue
+		}
+		p.Error("❌ [%s] %s: %s\n", v.Rule, v.Name, v.Message)
+	}
+}
+
+// RenderManifestValidateResponse writes manifest validation output.
+func RenderManifestValidateResponse(p Printer, resp *app.ManifestValidateResponse) {
+	if resp == nil {
+		return
+	}
+	if !resp.Found {
+		p.Info("No manifest for %s (%s not found); defaults apply\n", resp.Package, resp.Path)
+		return
+	}
+	if resp.Valid() {
+		p.Success("✅ %s is valid (schema version %s)\n", resp.Path, resp.Version)
+		return
+	}
+	for _, issue := range resp.Issues {
+		p.Error("❌ %s\n", issue.String())
+	}
+}
+
+// RenderStatusResponse writes drift detection output.
+func RenderStatusResponse(p Printer, resp *app.StatusResponse) {
+	if resp == nil {
+		return
+	}
+	if len(resp.Resources) == 0 {
+		p.Info("No resources recorded in %s\n", resp.Lockfile)
+		return
+	}
+	for _, res := range resp.Resources {
+		label := res.Kind + " " + res.Name + " (" + res.Target + ")"
+		switch res.State {
+		case app.StatusUpToDate:
+			p.Success("✅ %s: %s\n", label, res.State)
+		case app.StatusOutdated:
+			p.Warn("⚠️  %s: %s\n", label, res.State)
+		default:
+			p.Error("❌ %s: %s\n", label, res.State)
+		}
+		if res.Message != "" {
+			p.Info("     %s\n", res.Message)
+		}
+		for _, f := range res.Files {
+			if f.State == app.StatusUpToDate {
+				continue
+			}
+			p.Info("     %s: %s\n", f.Path, f.State)
+		}
+	}
+	p.Info("%d up-to-date, %d outdated, %d locally-modified, %d orphaned\n",
+		resp.Count(app.StatusUpToDate),
+		resp.Count(app.StatusOutdated),
+		resp.Count(app.StatusLocallyModified),
+		resp.Count(app.StatusOrphaned))
+}
+
+// RenderUpdateResponse writes update output.
+func RenderUpdateResponse(p Printer, resp *app.UpdateResponse) {
+	if resp == nil {
+		return
+	}
+	for _, res := range resp.Results {
+		label := res.Kind + " " + res.Name + " (" + res.Target + ")"
+		switch res.Action {
+		case app.UpdateActionUpdated:
+			p.Success("✅ Updated %s\n", label)
+		case app.UpdateActionWouldUpdate:
+			p.Info("would update %s (%s)\n", label, res.State)
+		case app.UpdateActionSkipped:
+			p.Warn("⚠️  Skipped %s: %s\n", label, res.Message)
+		}
+	}
+	if resp.Updated() == 0 {
+		p.Info("Everything is up to date.\n")
+	}
+}
@@ -0,0 +1,17 @@
+package display
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenderYAML writes v as YAML for machine-readable output modes.
+func RenderYAML(w io.Writer, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
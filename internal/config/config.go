@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
@@ -10,7 +11,9 @@ import (
 )
 
 type Config struct {
-	PackageDir   string
+	PackageDir string
+	// Sources lists package sources in precedence order. When set, it replaces PackageDir.
+	Sources      []PackageSource
 	SkillsSubdir string // default "skills"
 	AgentsSubdir string // default "agents"
 	HooksSubdir  string // default "hooks"
@@ -21,6 +24,25 @@ type Config struct {
 	LogLevel     string
 }
 
+// PackageSource is a named package directory. Earlier sources take precedence over later ones.
+// A source with a URL is a git repository that sync clones into Path (by default a managed
+// cache directory) and checks out at Ref.
+type PackageSource struct {
+	Name string `mapstructure:"name"`
+	Path string `mapstructure:"path"`
+	URL  string `mapstructure:"url"`
+	// Ref is a branch, tag or commit. Empty follows the remote's default branch.
+	Ref string `mapstructure:"ref"`
+}
+
+// IsRemote reports whether the source is cloned from a git URL.
+func (s PackageSource) IsRemote() bool {
+	return strings.TrimSpace(s.URL) != ""
+}
+
+// DefaultSourceName names the implicit source used when no sources are configured.
+const DefaultSourceName = "default"
+
 const defaultLogLevel = "info"
 
 // LoadConfig reads config from provided file or default location
@@ -79,10 +101,79 @@ func LoadConfig(cfgFile string) (*Config, error) {
 		Presets:      v.GetStringSlice("presets"),
 		LogLevel:     NormalizeLogLevel(v.GetString("logLevel")),
 	}
+	if v.IsSet("sources") {
+		if err := v.UnmarshalKey("sources", &cfg.Sources); err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "parse sources")
+		}
+		sources, err := normalizeSources(cfg.Sources)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Sources = sources
+	}
 	enableStowIfRequested(cfg)
 	return cfg, nil
 }
 
+// normalizeSources trims and validates configured sources. Names must be unique and must not
+// contain ':' because it separates the source from the item in qualified names. Remote sources
+// without a path are checked out into the managed cache.
+func normalizeSources(sources []PackageSource) ([]PackageSource, error) {
+	out := make([]PackageSource, 0, len(sources))
+	seen := make(map[string]struct{}, len(sources))
+	for i, src := range sources {
+		name := strings.TrimSpace(src.Name)
+		path := strings.TrimSpace(src.Path)
+		if name == "" {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "sources[%d]: missing name", i)
+		}
+		if strings.ContainsAny(name, ":/\\") {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "sources[%d]: name %q must not contain ':' or path separators", i, name)
+		}
+		if _, dup := seen[name]; dup {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "sources[%d]: duplicate source name %q", i, name)
+		}
+		url := strings.TrimSpace(src.URL)
+		ref := strings.TrimSpace(src.Ref)
+		if path == "" && url == "" {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "source %q: missing path or url", name)
+		}
+		if ref != "" && url == "" {
+			return nil, errors.Newf(errors.CodeInvalidArgument, "source %q: ref requires url", name)
+		}
+		if path == "" {
+			path = SourceCacheDir(name)
+		}
+		seen[name] = struct{}{}
+		out = append(out, PackageSource{Name: name, Path: expandHome(path), URL: url, Ref: ref})
+	}
+	return out, nil
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// FindSource returns the configured source with the given name.
+func (c *Config) FindSource(name string) (PackageSource, bool) {
+	if c == nil {
+		return PackageSource{}, false
+	}
+	for _, src := range c.Sources {
+		if src.Name == name {
+			return src, true
+		}
+	}
+	return PackageSource{}, false
+}
+
 // resolveSubdir returns the subdir name, or default if empty.
 func resolveSubdir(configured, defaultVal string) string {
 	if s := strings.TrimSpace(configured); s != "" {
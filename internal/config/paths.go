@@ -17,6 +17,8 @@ const (
 	EnvUserHooks     = "CURSOR_HOOKS_DIR"    // user hooks script dir (default <user-dir>/hooks)
 	EnvUserHooksJSON = "CURSOR_HOOKS_JSON"   // user hooks.json path (default <user-dir>/hooks.json)
 	EnvOpenCodeDir   = "OPENCODE_CONFIG_DIR"
+	EnvClaudeDir     = "CLAUDE_CONFIG_DIR"      // Claude Code user dir (default ~/.claude)
+	EnvCacheDir      = "CURSOR_RULES_CACHE_DIR" // managed clones of remote sources
 )
 
 // defaultCursorRulesBase returns ~/.cursor (or cwd/.cursor / ".cursor") for fallback.
@@ -60,12 +62,15 @@ func DefaultConfigPath() string {
 	return filepath.Join(DefaultConfigDir(), "config.yaml")
 }
 
-// ResolvePackageDir returns the effective package directory (env > config > default).
+// ResolvePackageDir returns the effective package directory (env > first source > config > default).
 func ResolvePackageDir(cfg *Config) string {
 	if v := strings.TrimSpace(os.Getenv(EnvPackageDir)); v != "" {
 		return v
 	}
 	if cfg != nil {
+		if len(cfg.Sources) > 0 {
+			return cfg.Sources[0].Path
+		}
 		if v := strings.TrimSpace(cfg.PackageDir); v != "" {
 			return v
 		}
@@ -73,6 +78,27 @@ func ResolvePackageDir(cfg *Config) string {
 	return DefaultPackageDir()
 }
 
+// DefaultCacheDir returns the cache directory. Precedence: CURSOR_RULES_CACHE_DIR >
+// XDG_CACHE_HOME/cursor-rules > ~/.cache/cursor-rules.
+func DefaultCacheDir() string {
+	if v := strings.TrimSpace(os.Getenv(EnvCacheDir)); v != "" {
+		return v
+	}
+	if v := strings.TrimSpace(os.Getenv("XDG_CACHE_HOME")); v != "" {
+		return filepath.Join(v, "cursor-rules")
+	}
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" {
+		return filepath.Join(home, ".cache", "cursor-rules")
+	}
+	return filepath.Join(".cache", "cursor-rules")
+}
+
+// SourceCacheDir returns the managed checkout directory for a remote source.
+func SourceCacheDir(name string) string {
+	return filepath.Join(DefaultCacheDir(), "sources", name)
+}
+
 // ResolveConfigPath returns an explicit config file path if provided, otherwise default.
 func ResolveConfigPath(cfgFile string) string {
 	if v := strings.TrimSpace(cfgFile); v != "" {
@@ -207,6 +233,90 @@ func EffectiveOpenCodeAgentsDir(projectRoot string, isUser bool) string {
 	return ProjectOpenCodeAgentsDir(projectRoot)
 }
 
+// DefaultClaudeConfigDir returns the global Claude Code config directory.
+// Precedence: CLAUDE_CONFIG_DIR > ~/.claude.
+func DefaultClaudeConfigDir() string {
+	if v := strings.TrimSpace(os.Getenv(EnvClaudeDir)); v != "" {
+		return v
+	}
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" {
+		return filepath.Join(home, ".claude")
+	}
+	if env := os.Getenv("HOME"); env != "" {
+		return filepath.Join(env, ".claude")
+	}
+	return ".claude"
+}
+
+// ProjectClaudeDir returns the project's .claude path.
+func ProjectClaudeDir(projectRoot string) string {
+	return filepath.Join(projectRoot, ".claude")
+}
+
+// EffectiveClaudeDir returns the project .claude dir or the global Claude Code config dir.
+func EffectiveClaudeDir(projectRoot string, isUser bool) string {
+	if isUser {
+		return DefaultClaudeConfigDir()
+	}
+	return ProjectClaudeDir(projectRoot)
+}
+
+// EffectiveClaudeRulesDir returns the project or global Claude Code rules dir (.claude/rules).
+func EffectiveClaudeRulesDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "rules")
+}
+
+// EffectiveClaudeCommandsDir returns the project or global Claude Code commands dir.
+func EffectiveClaudeCommandsDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "commands")
+}
+
+// EffectiveClaudeSkillsDir returns the project or global Claude Code skills dir.
+func EffectiveClaudeSkillsDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "skills")
+}
+
+// EffectiveClaudeAgentsDir returns the project or global Claude Code agents dir.
+func EffectiveClaudeAgentsDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "agents")
+}
+
+// EffectiveClaudeHooksDir returns the directory hook scripts are copied to (.claude/hooks).
+func EffectiveClaudeHooksDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "hooks")
+}
+
+// EffectiveClaudeSettingsJSON returns the settings.json that holds Claude Code hooks.
+func EffectiveClaudeSettingsJSON(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveClaudeDir(projectRoot, isUser), "settings.json")
+}
+
+// DefaultGeminiConfigDir returns the global Gemini CLI config directory (~/.gemini).
+func DefaultGeminiConfigDir() string {
+	home, err := os.UserHomeDir()
+	if err == nil && home != "" {
+		return filepath.Join(home, ".gemini")
+	}
+	if env := os.Getenv("HOME"); env != "" {
+		return filepath.Join(env, ".gemini")
+	}
+	return ".gemini"
+}
+
+// EffectiveGeminiDir returns the project .gemini dir or the global Gemini CLI config dir.
+func EffectiveGeminiDir(projectRoot string, isUser bool) string {
+	if isUser {
+		return DefaultGeminiConfigDir()
+	}
+	return filepath.Join(projectRoot, ".gemini")
+}
+
+// EffectiveGeminiCommandsDir returns the project or global Gemini CLI commands dir.
+func EffectiveGeminiCommandsDir(projectRoot string, isUser bool) string {
+	return filepath.Join(EffectiveGeminiDir(projectRoot, isUser), "commands")
+}
+
 // DefaultUserCursorDir returns the default user/global Cursor base directory (~/.cursor).
 func DefaultUserCursorDir() string {
 	home, err := os.UserHomeDir()
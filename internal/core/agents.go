@@ -64,10 +64,10 @@ func InstallAgentToProject(projectRoot, packageDir, agentName, agentsSubdir stri
 	if err != nil {
 		return StrategyUnknown, err
 	}
-	return InstallAgentToDir(filepath.Join(projectRoot, ".cursor", "agents"), agentsRoot, agentName, ".md")
+	return InstallAgentToDir(filepath.Join(projectRoot, ".cursor", "agents"), agentsRoot, agentName, ".md", nil)
 }
 
-// InstallAgentToDir installs an agent file into the given agents directory.
-func InstallAgentToDir(agentsDir, agentsRoot, agentName, ext string) (InstallStrategy, error) {
-	return installNamedFileResourceTo(agentsDir, agentsRoot, agentName, ext)
+// InstallAgentToDir installs an agent file into the given agents directory, recording it in written.
+func InstallAgentToDir(agentsDir, agentsRoot, agentName, ext string, written *Written) (InstallStrategy, error) {
+	return installNamedFileResourceTo(agentsDir, agentsRoot, agentName, ext, written)
 }
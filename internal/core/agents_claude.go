@@ -0,0 +1,129 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/security"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// claudeReadonlyTools is the tool allowlist given to agents marked readonly.
+const claudeReadonlyTools = "Read, Grep, Glob"
+
+// claudeAgentFrontmatter is the subagent frontmatter Claude Code reads, in its documented order.
+type claudeAgentFrontmatter struct {
+	Name           string `yaml:"name"`
+	Description    string `yaml:"description"`
+	Tools          string `yaml:"tools,omitempty"`
+	Model          string `yaml:"model,omitempty"`
+	PermissionMode string `yaml:"permissionMode,omitempty"`
+	Color          string `yaml:"color,omitempty"`
+}
+
+// InstallClaudeAgentToDir installs an agent into Claude Code's agents directory, translating its
+// frontmatter into Claude Code's subagent fields. The agent file is recorded in written.
+func InstallClaudeAgentToDir(agentsDir, agentsRoot, agentName string, written *Written) (InstallStrategy, error) {
+	if err := security.ValidatePackageName(agentName); err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid resource name")
+	}
+	src, err := security.SafeJoin(agentsRoot, agentName+".md")
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid source path")
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StrategyUnknown, errors.Newf(errors.CodeNotFound, "agent not found: %s", agentName)
+		}
+		return StrategyUnknown, err
+	}
+	content, err := translateClaudeAgent(agentName, data)
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "translate agent %s", agentName)
+	}
+	dest, err := security.SafeJoin(agentsDir, agentName+".md")
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid agent destination")
+	}
+	return StrategyCopy, written.writeIfChanged(dest, content)
+}
+
+// translateClaudeAgent maps Cursor (and OpenCode) agent frontmatter to Claude Code's:
+//   - name defaults to the file name, description to a generic one (Claude Code requires both);
+//   - a tools list becomes a comma-separated allowlist; readonly: true without tools allows
+//     only the read tools;
+//   - model "fast" becomes haiku, "inherit" and Claude model names are kept, others dropped;
+//   - permissionMode and color are kept, every other field is dropped.
+func translateClaudeAgent(name string, data []byte) ([]byte, error) {
+	fm := map[string]interface{}{}
+	body := strings.TrimSpace(string(data))
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("---")) {
+		node, mdBody, err := transform.SplitFrontmatter(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := node.Decode(&fm); err != nil {
+			return nil, err
+		}
+		body = mdBody
+	}
+
+	out := claudeAgentFrontmatter{
+		Name:           stringField(fm, "name"),
+		Description:    stringField(fm, "description"),
+		Model:          claudeAgentModel(stringField(fm, "model")),
+		PermissionMode: stringField(fm, "permissionMode"),
+		Color:          stringField(fm, "color"),
+	}
+	if out.Name == "" {
+		out.Name = name
+	}
+	if out.Description == "" {
+		out.Description = fmt.Sprintf("Use the %s agent when its instructions apply to the task.", out.Name)
+	}
+	switch tools := fm["tools"].(type) {
+	case string:
+		out.Tools = strings.TrimSpace(tools)
+	case []interface{}:
+		out.Tools = joinStrings(tools, ", ")
+	}
+	if readonly, _ := fm["readonly"].(bool); readonly && out.Tools == "" {
+		out.Tools = claudeReadonlyTools
+	}
+
+	fmBytes, err := yaml.Marshal(&out)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "marshal agent frontmatter")
+	}
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(fmBytes)
+	buf.WriteString("---\n\n")
+	buf.WriteString(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// claudeAgentModel maps an agent model setting to a Claude Code model alias; "" inherits.
+func claudeAgentModel(model string) string {
+	switch model = strings.TrimSpace(model); {
+	case model == "fast":
+		return "haiku"
+	case model == "inherit", model == "sonnet", model == "opus", model == "haiku":
+		return model
+	case strings.HasPrefix(model, "claude-"):
+		return model
+	default:
+		return ""
+	}
+}
+
+func stringField(fm map[string]interface{}, key string) string {
+	s, _ := fm[key].(string)
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallClaudeAgentTranslatesFrontmatter(t *testing.T) {
+	agentsRoot := t.TempDir()
+	src := "---\ndescription: Reviews diffs\nmodel: fast\nreadonly: true\nis_background: true\n---\n\nReview the staged changes.\n"
+	if err := os.WriteFile(filepath.Join(agentsRoot, "reviewer.md"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(agentsRoot, "plain.md"), []byte("Just instructions.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	agentsDir := filepath.Join(t.TempDir(), ".claude", "agents")
+	for _, name := range []string{"reviewer", "plain"} {
+		if _, err := InstallClaudeAgentToDir(agentsDir, agentsRoot, name, nil); err != nil {
+			t.Fatalf("InstallClaudeAgentToDir(%s): %v", name, err)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(agentsDir, "reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\nname: reviewer\ndescription: Reviews diffs\ntools: Read, Grep, Glob\nmodel: haiku\n---\n\nReview the staged changes.\n"
+	if string(got) != want {
+		t.Fatalf("reviewer.md =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = os.ReadFile(filepath.Join(agentsDir, "plain.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "name: plain\ndescription: ") || !strings.HasSuffix(string(got), "\n\nJust instructions.\n") {
+		t.Fatalf("plain.md = %q", got)
+	}
+
+	if _, err := InstallClaudeAgentToDir(agentsDir, agentsRoot, "missing", nil); err == nil {
+		t.Fatal("expected an error for a missing agent")
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+)
+
+// RuleApplies reports whether rule, installed for target, is attached when editing file and
+// why. file is relative to the project root and uses forward slashes.
+//
+//   - cursor: alwaysApply, then globs and apply_to.
+//   - copilot-instr: applyTo.
+//   - opencode-rules: globs; a rule without globs or keywords applies everywhere.
+//   - claude-rules: paths; a rule without paths applies everywhere.
+//   - windsurf: trigger always_on, or globs for trigger glob; model_decision and manual rules
+//     are only attached on request.
+//   - cline-rules: paths; a rule without paths applies everywhere.
+//   - roo-rules: always applies; Roo Code rules carry no frontmatter.
+func RuleApplies(target string, rule EffectiveRule, file string) (string, bool) {
+	switch target {
+	case "cursor":
+		if rule.AlwaysApply {
+			return "alwaysApply: true", true
+		}
+		if reason, ok := matchAnyGlob("globs", rule.Globs, file); ok {
+			return reason, true
+		}
+		return matchAnyGlob("apply_to", globList(rule.Frontmatter["apply_to"]), file)
+	case "copilot-instr":
+		return matchAnyGlob("applyTo", globList(rule.Frontmatter["applyTo"]), file)
+	case "opencode-rules":
+		if len(rule.Globs) == 0 && len(globList(rule.Frontmatter["keywords"])) == 0 {
+			return "no globs or keywords (always applies)", true
+		}
+		return matchAnyGlob("globs", rule.Globs, file)
+	case "claude-rules", "cline-rules":
+		paths := globList(rule.Frontmatter["paths"])
+		if len(paths) == 0 {
+			return "no paths (always applies)", true
+		}
+		return matchAnyGlob("paths", paths, file)
+	case "roo-rules":
+		return "no frontmatter (always applies)", true
+	case "windsurf":
+		switch rule.Frontmatter["trigger"] {
+		case "always_on":
+			return "trigger: always_on", true
+		case "glob":
+			return matchAnyGlob("globs", rule.Globs, file)
+		}
+	}
+	return "", false
+}
+
+func matchAnyGlob(field string, patterns []string, file string) (string, bool) {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, file) {
+			return fmt.Sprintf("%s: %s", field, pattern), true
+		}
+	}
+	return "", false
+}
+
+// matchGlob matches a slash-separated path against a glob. Patterns without a slash match the
+// base name at any depth, as in .gitignore. Malformed patterns never match.
+func matchGlob(pattern, name string) bool {
+	p, err := glob.Compile(pattern)
+	if err != nil || p.Negated() {
+		return false
+	}
+	if !strings.Contains(pattern, "/") && strings.TrimSpace(pattern) != "**" {
+		name = path.Base(name)
+	}
+	return p.Match(name)
+}
@@ -0,0 +1,66 @@
+package core
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"**/*.tsx", "web/src/app.tsx", true},
+		{"**/*.tsx", "app.tsx", true},
+		{"web/**", "web/src/app.tsx", true},
+		{"web/**/*.ts", "web/src/app.tsx", false},
+		{"web/*/app.tsx", "web/src/app.tsx", true},
+		{"web/*.tsx", "web/src/app.tsx", false},
+		{"*.tsx", "web/src/app.tsx", true},
+		{"./go.mod", "go.mod", true},
+		{"**", "any/file", true},
+		{"", "go.mod", false},
+		{"**/*.{ts,tsx}", "web/src/app.tsx", true},
+		{"*.{go,mod}", "cmd/main.go", true},
+		{"src/[a-c]*/*.ts", "src/api/x.ts", true},
+		{"src/[!a-c]*/*.ts", "src/api/x.ts", false},
+		{"[broken", "[broken", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRuleApplies(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		rule       EffectiveRule
+		wantReason string
+		wantOK     bool
+	}{
+		{"cursor always", "cursor", EffectiveRule{AlwaysApply: true}, "alwaysApply: true", true},
+		{"cursor globs", "cursor", EffectiveRule{Globs: []string{"**/*.go", "web/**/*.tsx"}}, "globs: web/**/*.tsx", true},
+		{"cursor apply_to", "cursor", EffectiveRule{Frontmatter: map[string]interface{}{"apply_to": []interface{}{"web/**"}}}, "apply_to: web/**", true},
+		{"cursor manual", "cursor", EffectiveRule{Description: "Ask for me"}, "", false},
+		{"copilot applyTo", "copilot-instr", EffectiveRule{Frontmatter: map[string]interface{}{"applyTo": "**/*.py,**/*.tsx"}}, "applyTo: **/*.tsx", true},
+		{"copilot no match", "copilot-instr", EffectiveRule{Frontmatter: map[string]interface{}{"applyTo": "**/*.py"}}, "", false},
+		{"opencode unconditional", "opencode-rules", EffectiveRule{}, "no globs or keywords (always applies)", true},
+		{"opencode keywords only", "opencode-rules", EffectiveRule{Frontmatter: map[string]interface{}{"keywords": []interface{}{"deploy"}}}, "", false},
+		{"opencode globs", "opencode-rules", EffectiveRule{Globs: []string{"web/**"}}, "globs: web/**", true},
+		{"windsurf always_on", "windsurf", EffectiveRule{Frontmatter: map[string]interface{}{"trigger": "always_on"}}, "trigger: always_on", true},
+		{"windsurf glob", "windsurf", EffectiveRule{Globs: []string{"**/*.tsx"}, Frontmatter: map[string]interface{}{"trigger": "glob"}}, "globs: **/*.tsx", true},
+		{"cline paths", "cline-rules", EffectiveRule{Frontmatter: map[string]interface{}{"paths": []interface{}{"web/**"}}}, "paths: web/**", true},
+		{"cline no paths", "cline-rules", EffectiveRule{}, "no paths (always applies)", true},
+		{"roo", "roo-rules", EffectiveRule{}, "no frontmatter (always applies)", true},
+		{"windsurf model decision", "windsurf", EffectiveRule{Description: "React", Frontmatter: map[string]interface{}{"trigger": "model_decision"}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, ok := RuleApplies(tt.target, tt.rule, "web/src/app.tsx")
+			if ok != tt.wantOK || reason != tt.wantReason {
+				t.Fatalf("RuleApplies = (%q, %v), want (%q, %v)", reason, ok, tt.wantReason, tt.wantOK)
+			}
+		})
+	}
+}
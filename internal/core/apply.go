@@ -38,5 +38,5 @@ func ApplyPresetToProject(projectRoot, preset, packageDir string) (InstallStrate
 		return StrategyCopy, nil
 	}
 	// Delegate to shared ApplySourceToDest which handles stow -> symlink -> stub
-	return ApplySourceToDest(packageDir, src, dest, normalizedPreset)
+	return ApplySourceToDest(packageDir, src, dest, normalizedPreset, nil)
 }
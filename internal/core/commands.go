@@ -232,7 +232,7 @@ func installCommandFileToProject(projectRoot, sourceDir, command string) (Instal
 }
 
 func installCommandFileToCommandsDir(commandsDir, sourceDir, command string) (InstallStrategy, error) {
-	return installNamedFileResourceTo(commandsDir, sourceDir, command, ".md")
+	return installNamedFileResourceTo(commandsDir, sourceDir, command, ".md", nil)
 }
 
 func installCommandFileToCommandsDirIfExists(commandsDir, sourceDir, command string) (InstallStrategy, bool, error) {
@@ -299,7 +299,7 @@ func installCommandSubdirToCommandsDir(commandsDir, sourceDir, command string, e
 	if !commandDirContainsMarkdown(srcDir) {
 		return StrategyUnknown, false, nil
 	}
-	strategy, err := InstallPackageGenericToDest(commandsDir, commandsRoot, command, []string{".md"}, ".cursor-commands-ignore", excludes, true)
+	strategy, err := InstallPackageGenericToDest(commandsDir, commandsRoot, command, []string{".md"}, ".cursor-commands-ignore", excludes, true, nil)
 	return strategy, true, err
 }
 
@@ -321,7 +321,7 @@ func installLegacyCommandPackageToCommandsDir(commandsDir, sourceDir, command st
 	if !commandDirContainsMarkdown(srcDir) {
 		return StrategyUnknown, false, nil
 	}
-	strategy, err := InstallPackageGenericToDest(commandsDir, sourceDir, command, []string{".md"}, ".cursor-commands-ignore", excludes, noFlatten)
+	strategy, err := InstallPackageGenericToDest(commandsDir, sourceDir, command, []string{".md"}, ".cursor-commands-ignore", excludes, noFlatten, nil)
 	return strategy, true, err
 }
 
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+	"gopkg.in/yaml.v3"
+)
+
+// claudeCommandFields are the command frontmatter fields Claude Code understands.
+var claudeCommandFields = []string{"description", "argument-hint", "allowed-tools", "model"}
+
+// InstallClaudeCommandToDir installs a command into Claude Code's commands layout
+// (.claude/commands/<name>.md, or .claude/commands/<name>/ for bundles, which Claude Code
+// exposes as /<name>:<command>). The installed files are recorded in written.
+func InstallClaudeCommandToDir(commandsDir, packageDir, command string, excludes []string, written *Written) (InstallStrategy, error) {
+	return installMarkdownCommandToDir(commandsDir, packageDir, command, excludes, readClaudeCommandSource, written)
+}
+
+// InstallClaudeCommandCollectionToDir installs all compatible commands into Claude Code's commands directory.
+func InstallClaudeCommandCollectionToDir(commandsDir, packageDir string, excludes []string, written *Written) (InstallStrategy, error) {
+	return installMarkdownCommandCollectionToDir(commandsDir, packageDir, excludes, readClaudeCommandSource, written)
+}
+
+// readClaudeCommandSource keeps the frontmatter fields Claude Code supports and drops the rest.
+func readClaudeCommandSource(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".mdc") {
+		return data, nil
+	}
+	node, body, err := transform.SplitFrontmatter(data)
+	if err != nil {
+		return nil, err
+	}
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "decode command frontmatter")
+	}
+
+	out := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range claudeCommandFields {
+		value, ok := fm[key]
+		if !ok || value == nil {
+			continue
+		}
+		if list, isList := value.([]interface{}); isList && key == "allowed-tools" {
+			value = joinStrings(list, ", ")
+		}
+		var valueNode yaml.Node
+		if err := valueNode.Encode(value); err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInternal, "encode command frontmatter")
+		}
+		out.Content = append(out.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &valueNode)
+	}
+
+	body = strings.TrimSpace(body)
+	if len(out.Content) == 0 {
+		if body == "" {
+			return []byte{}, nil
+		}
+		return []byte(body + "\n"), nil
+	}
+	content, err := transform.MarshalMarkdown(out, body)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	return content, nil
+}
+
+// joinStrings joins the string items of a YAML list, skipping anything else.
+func joinStrings(items []interface{}, sep string) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			parts = append(parts, strings.TrimSpace(s))
+		}
+	}
+	return strings.Join(parts, sep)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/security"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 	"gopkg.in/yaml.v3"
@@ -66,21 +67,22 @@ func ListCursorCompatibleCommands(packageDir string) ([]string, error) {
 }
 
 // InstallCommandAsSkillToDir installs a source command into Cursor's skills
-// layout, converting it to a SKILL.md with explicit invocation semantics.
-func InstallCommandAsSkillToDir(skillsDir, packageDir, command string, excludes []string) (InstallStrategy, error) {
+// layout, converting it to a SKILL.md with explicit invocation semantics. The installed files
+// are recorded in written.
+func InstallCommandAsSkillToDir(skillsDir, packageDir, command string, excludes []string, written *Written) (InstallStrategy, error) {
 	name, srcPath, isDir, err := locateCommandCompatSource(packageDir, command)
 	if err != nil {
 		return StrategyUnknown, err
 	}
 	if isDir {
-		return installCommandBundleAsSkillToDir(skillsDir, srcPath, name, excludes)
+		return installCommandBundleAsSkillToDir(skillsDir, srcPath, name, excludes, written)
 	}
-	return installCommandFileAsSkillToDir(skillsDir, srcPath, name)
+	return installCommandFileAsSkillToDir(skillsDir, srcPath, name, written)
 }
 
 // InstallCommandCollectionAsSkillsToDir installs all compatible commands from
 // packageDir/commands into Cursor's skills directory.
-func InstallCommandCollectionAsSkillsToDir(skillsDir, packageDir string, excludes []string) (InstallStrategy, error) {
+func InstallCommandCollectionAsSkillsToDir(skillsDir, packageDir string, excludes []string, written *Written) (InstallStrategy, error) {
 	names, err := ListCursorCompatibleCommands(packageDir)
 	if err != nil {
 		return StrategyUnknown, err
@@ -90,7 +92,7 @@ func InstallCommandCollectionAsSkillsToDir(skillsDir, packageDir string, exclude
 	}
 
 	for _, name := range names {
-		if _, err := InstallCommandAsSkillToDir(skillsDir, packageDir, name, excludes); err != nil {
+		if _, err := InstallCommandAsSkillToDir(skillsDir, packageDir, name, excludes, written); err != nil {
 			return StrategyUnknown, err
 		}
 	}
@@ -212,7 +214,7 @@ func commandBundleContainsContent(dir string) bool {
 	return found || err == fs.SkipAll
 }
 
-func installCommandFileAsSkillToDir(skillsDir, srcPath, commandName string) (InstallStrategy, error) {
+func installCommandFileAsSkillToDir(skillsDir, srcPath, commandName string, written *Written) (InstallStrategy, error) {
 	body, description, err := readCommandSource(srcPath)
 	if err != nil {
 		return StrategyUnknown, err
@@ -229,10 +231,14 @@ func installCommandFileAsSkillToDir(skillsDir, srcPath, commandName string) (Ins
 	if err != nil {
 		return StrategyUnknown, err
 	}
-	return StrategyCopy, writeIfChanged(skillPath, content)
+	return StrategyCopy, written.writeIfChanged(skillPath, content)
 }
 
-func installCommandBundleAsSkillToDir(skillsDir, srcDir, commandName string, excludes []string) (InstallStrategy, error) {
+func installCommandBundleAsSkillToDir(skillsDir, srcDir, commandName string, excludes []string, written *Written) (InstallStrategy, error) {
+	excluded, err := excludeSet(excludes)
+	if err != nil {
+		return StrategyUnknown, err
+	}
 	primary, err := choosePrimaryCommandDoc(srcDir, commandName)
 	if err != nil {
 		return StrategyUnknown, err
@@ -264,7 +270,7 @@ func installCommandBundleAsSkillToDir(skillsDir, srcDir, commandName string, exc
 		if rel == filepath.Base(primary) || path == primary {
 			return nil
 		}
-		if shouldExcludeCompat(rel, excludes) {
+		if excluded.Matches(rel) {
 			return nil
 		}
 		if err := security.ValidatePath(rel); err != nil {
@@ -275,7 +281,7 @@ func installCommandBundleAsSkillToDir(skillsDir, srcDir, commandName string, exc
 		if err != nil {
 			return err
 		}
-		return writeFileWithDirs(dest, data, 0o644)
+		return written.writeFile(dest, data, 0o644)
 	})
 	if err != nil {
 		return StrategyUnknown, err
@@ -285,7 +291,7 @@ func installCommandBundleAsSkillToDir(skillsDir, srcDir, commandName string, exc
 	if err != nil {
 		return StrategyUnknown, err
 	}
-	return StrategyCopy, writeIfChanged(filepath.Join(destRoot, "SKILL.md"), content)
+	return StrategyCopy, written.writeIfChanged(filepath.Join(destRoot, "SKILL.md"), content)
 }
 
 func choosePrimaryCommandDoc(srcDir, commandName string) (string, error) {
@@ -379,18 +385,14 @@ func marshalCommandAsSkill(name, description, body string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func shouldExcludeCompat(relPath string, patterns []string) bool {
-	for _, pattern := range patterns {
-		matched, err := filepath.Match(pattern, relPath)
-		if err == nil && matched {
-			return true
-		}
-		matched, err = filepath.Match(pattern, filepath.Dir(relPath))
-		if err == nil && matched {
-			return true
-		}
+// excludeSet compiles manifest exclude patterns; a pattern also excludes everything under a
+// matching directory.
+func excludeSet(excludes []string) (*glob.Set, error) {
+	set, err := glob.CompileSet(excludes)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid exclude pattern")
 	}
-	return false
+	return set, nil
 }
 
 func writeIfChanged(path string, data []byte) error {
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/security"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// geminiCommandExt is the extension of Gemini CLI custom command files.
+const geminiCommandExt = ".toml"
+
+// geminiCommand is the layout of a Gemini CLI custom command file.
+type geminiCommand struct {
+	Description string `toml:"description,omitempty"`
+	Prompt      string `toml:"prompt,multiline"`
+}
+
+var (
+	// argumentsPlaceholder matches $ARGUMENTS and ${ARGUMENTS}, the placeholder Cursor and
+	// Claude Code commands use for everything typed after the command.
+	argumentsPlaceholder = regexp.MustCompile(`\$(?:ARGUMENTS\b|\{ARGUMENTS\})`)
+	// shellPlaceholder matches !`command`, which Claude Code replaces with the command's output.
+	shellPlaceholder = regexp.MustCompile("!`([^`\n]+)`")
+)
+
+// InstallGeminiCommandToDir installs a command into Gemini CLI's commands layout
+// (.gemini/commands/<name>.toml, or .gemini/commands/<name>/ for bundles, which Gemini CLI
+// exposes as /<name>:<command>). The installed files are recorded in written.
+func InstallGeminiCommandToDir(commandsDir, packageDir, command string, excludes []string, written *Written) (InstallStrategy, error) {
+	name, srcPath, isDir, err := locateCommandCompatSource(packageDir, command)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	if isDir {
+		return installGeminiCommandBundleToDir(commandsDir, srcPath, name, excludes, written)
+	}
+	dest, err := security.SafeJoin(commandsDir, name+geminiCommandExt)
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid command destination")
+	}
+	content, err := readGeminiCommandSource(srcPath)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	return StrategyCopy, written.writeIfChanged(dest, content)
+}
+
+// InstallGeminiCommandCollectionToDir installs all compatible commands into Gemini CLI's commands directory.
+func InstallGeminiCommandCollectionToDir(commandsDir, packageDir string, excludes []string, written *Written) (InstallStrategy, error) {
+	names, err := ListCursorCompatibleCommands(packageDir)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	if len(names) == 0 {
+		return StrategyUnknown, errors.Newf(errors.CodeNotFound, "commands collection not found: %s", filepath.Join(packageDir, defaultCommandsSubdir))
+	}
+
+	for _, name := range names {
+		if _, err := InstallGeminiCommandToDir(commandsDir, packageDir, name, excludes, written); err != nil {
+			return StrategyUnknown, err
+		}
+	}
+	return StrategyCopy, nil
+}
+
+// ListInstalledGeminiCommands returns the command files (<name>.toml) and bundle directories
+// in a Gemini CLI commands directory.
+func ListInstalledGeminiCommands(commandsDir string) ([]string, error) {
+	entries, err := os.ReadDir(commandsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+	for _, e := range entries {
+		if e.IsDir() {
+			if geminiCommandDirContainsCommands(filepath.Join(commandsDir, e.Name())) {
+				names[e.Name()] = struct{}{}
+			}
+			continue
+		}
+		if filepath.Ext(e.Name()) == geminiCommandExt {
+			names[e.Name()] = struct{}{}
+		}
+	}
+	return sortedCommandNames(names), nil
+}
+
+// RemoveGeminiCommand removes a command file or bundle directory from a Gemini CLI commands directory.
+func RemoveGeminiCommand(commandsDir, command string) error {
+	name := strings.TrimSuffix(strings.TrimSpace(command), geminiCommandExt)
+	if removed, err := removeInstalledNamedFileResourceFrom(commandsDir, name, geminiCommandExt); err != nil {
+		return err
+	} else if removed {
+		return nil
+	}
+	_, err := removeInstalledNamedDirResourceFrom(commandsDir, name)
+	return err
+}
+
+func installGeminiCommandBundleToDir(commandsDir, srcDir, commandName string, excludes []string, written *Written) (InstallStrategy, error) {
+	excluded, err := excludeSet(excludes)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	destRoot, err := security.SafeJoin(commandsDir, commandName)
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid command destination")
+	}
+	if err := os.MkdirAll(destRoot, 0o755); err != nil {
+		return StrategyUnknown, err
+	}
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if excluded.Matches(rel) {
+			return nil
+		}
+		if err := security.ValidatePath(rel); err != nil {
+			return errors.Wrapf(err, errors.CodeInvalidArgument, "invalid path in command bundle")
+		}
+
+		destRel := rel
+		var content []byte
+		if name, ok := commandNameFromFilename(rel); ok {
+			destRel = name + geminiCommandExt
+			content, err = readGeminiCommandSource(path)
+		} else {
+			content, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		return written.writeIfChanged(filepath.Join(destRoot, destRel), content)
+	})
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	return StrategyCopy, nil
+}
+
+// readGeminiCommandSource converts a markdown command into a Gemini CLI command: the
+// frontmatter description becomes `description`, and the body, with its placeholders
+// translated, the `prompt`.
+func readGeminiCommandSource(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	body := string(data)
+	var description string
+	if strings.HasSuffix(path, ".mdc") || bytes.HasPrefix(data, []byte("---\n")) {
+		node, mdBody, err := transform.SplitFrontmatter(data)
+		if err != nil {
+			return nil, err
+		}
+		var fm map[string]interface{}
+		if err := node.Decode(&fm); err != nil {
+			return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "decode command frontmatter")
+		}
+		if desc, ok := fm["description"].(string); ok {
+			description = strings.TrimSpace(desc)
+		}
+		body = mdBody
+	}
+
+	content, err := toml.Marshal(geminiCommand{
+		Description: description,
+		Prompt:      translateGeminiPlaceholders(strings.TrimSpace(body)),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "encode Gemini command %s", path)
+	}
+	return content, nil
+}
+
+// translateGeminiPlaceholders rewrites command placeholders into Gemini CLI's syntax:
+// $ARGUMENTS becomes {{args}} and !`command` becomes !{command}. Gemini CLI has no positional
+// arguments, so $1, $2, ... are left as written.
+func translateGeminiPlaceholders(prompt string) string {
+	prompt = argumentsPlaceholder.ReplaceAllLiteralString(prompt, "{{args}}")
+	return shellPlaceholder.ReplaceAllString(prompt, "!{$1}")
+}
+
+func geminiCommandDirContainsCommands(dir string) bool {
+	found := false
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(d.Name()) == geminiCommandExt {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found || err == fs.SkipAll
+}
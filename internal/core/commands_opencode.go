@@ -10,20 +10,34 @@ import (
 	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
 )
 
-// InstallOpenCodeCommandToDir installs a command into OpenCode's native commands layout.
-func InstallOpenCodeCommandToDir(commandsDir, packageDir, command string, excludes []string) (InstallStrategy, error) {
+// InstallOpenCodeCommandToDir installs a command into OpenCode's native commands layout,
+// recording the installed files in written.
+func InstallOpenCodeCommandToDir(commandsDir, packageDir, command string, excludes []string, written *Written) (InstallStrategy, error) {
+	return installMarkdownCommandToDir(commandsDir, packageDir, command, excludes, readOpenCodeCommandSource, written)
+}
+
+// InstallOpenCodeCommandCollectionToDir installs all compatible commands into OpenCode's native commands directory.
+func InstallOpenCodeCommandCollectionToDir(commandsDir, packageDir string, excludes []string, written *Written) (InstallStrategy, error) {
+	return installMarkdownCommandCollectionToDir(commandsDir, packageDir, excludes, readOpenCodeCommandSource, written)
+}
+
+// commandSourceReader renders a command source file as the destination tool's markdown.
+type commandSourceReader func(path string) ([]byte, error)
+
+// installMarkdownCommandToDir installs a command as <name>.md, or a bundle as <name>/, into a
+// tool whose commands are plain markdown files.
+func installMarkdownCommandToDir(commandsDir, packageDir, command string, excludes []string, read commandSourceReader, written *Written) (InstallStrategy, error) {
 	name, srcPath, isDir, err := locateCommandCompatSource(packageDir, command)
 	if err != nil {
 		return StrategyUnknown, err
 	}
 	if isDir {
-		return installOpenCodeCommandBundleToDir(commandsDir, srcPath, name, excludes)
+		return installMarkdownCommandBundleToDir(commandsDir, srcPath, name, excludes, read, written)
 	}
-	return installOpenCodeCommandFileToDir(commandsDir, srcPath, name)
+	return installMarkdownCommandFileToDir(commandsDir, srcPath, name, read, written)
 }
 
-// InstallOpenCodeCommandCollectionToDir installs all compatible commands into OpenCode's native commands directory.
-func InstallOpenCodeCommandCollectionToDir(commandsDir, packageDir string, excludes []string) (InstallStrategy, error) {
+func installMarkdownCommandCollectionToDir(commandsDir, packageDir string, excludes []string, read commandSourceReader, written *Written) (InstallStrategy, error) {
 	names, err := ListCursorCompatibleCommands(packageDir)
 	if err != nil {
 		return StrategyUnknown, err
@@ -33,26 +47,30 @@ func InstallOpenCodeCommandCollectionToDir(commandsDir, packageDir string, exclu
 	}
 
 	for _, name := range names {
-		if _, err := InstallOpenCodeCommandToDir(commandsDir, packageDir, name, excludes); err != nil {
+		if _, err := installMarkdownCommandToDir(commandsDir, packageDir, name, excludes, read, written); err != nil {
 			return StrategyUnknown, err
 		}
 	}
 	return StrategyCopy, nil
 }
 
-func installOpenCodeCommandFileToDir(commandsDir, srcPath, commandName string) (InstallStrategy, error) {
+func installMarkdownCommandFileToDir(commandsDir, srcPath, commandName string, read commandSourceReader, written *Written) (InstallStrategy, error) {
 	dest, err := security.SafeJoin(commandsDir, commandName+".md")
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid command destination")
 	}
-	content, err := readOpenCodeCommandSource(srcPath)
+	content, err := read(srcPath)
 	if err != nil {
 		return StrategyUnknown, err
 	}
-	return StrategyCopy, writeIfChanged(dest, content)
+	return StrategyCopy, written.writeIfChanged(dest, content)
 }
 
-func installOpenCodeCommandBundleToDir(commandsDir, srcDir, commandName string, excludes []string) (InstallStrategy, error) {
+func installMarkdownCommandBundleToDir(commandsDir, srcDir, commandName string, excludes []string, read commandSourceReader, written *Written) (InstallStrategy, error) {
+	excluded, err := excludeSet(excludes)
+	if err != nil {
+		return StrategyUnknown, err
+	}
 	destRoot, err := security.SafeJoin(commandsDir, commandName)
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid command destination")
@@ -73,7 +91,7 @@ func installOpenCodeCommandBundleToDir(commandsDir, srcDir, commandName string,
 		if err != nil {
 			return err
 		}
-		if shouldExcludeCompat(rel, excludes) {
+		if excluded.Matches(rel) {
 			return nil
 		}
 		if err := security.ValidatePath(rel); err != nil {
@@ -85,7 +103,7 @@ func installOpenCodeCommandBundleToDir(commandsDir, srcDir, commandName string,
 		switch {
 		case strings.HasSuffix(rel, ".command.mdc"):
 			destRel = strings.TrimSuffix(rel, ".command.mdc") + ".md"
-			content, err = readOpenCodeCommandSource(path)
+			content, err = read(path)
 		case strings.HasSuffix(rel, ".md"):
 			content, err = os.ReadFile(path)
 		default:
@@ -98,7 +116,7 @@ func installOpenCodeCommandBundleToDir(commandsDir, srcDir, commandName string,
 		if err := security.ValidatePath(destRel); err != nil {
 			return errors.Wrapf(err, errors.CodeInvalidArgument, "invalid destination path in command bundle")
 		}
-		return writeFileWithDirs(filepath.Join(destRoot, destRel), content, 0o644)
+		return written.writeFile(filepath.Join(destRoot, destRel), content, 0o644)
 	})
 	if err != nil {
 		return StrategyUnknown, err
@@ -188,3 +188,82 @@ func TestInstallAndRemoveCommandDirectory(t *testing.T) {
 		t.Fatalf("expected command directory removed, got: %v", err)
 	}
 }
+
+func TestInstallClaudeCommandKeepsSupportedFrontmatter(t *testing.T) {
+	packageDir := t.TempDir()
+	commandsRoot := filepath.Join(packageDir, "commands")
+	if err := os.MkdirAll(filepath.Join(commandsRoot, "release"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "---\ndescription: Review a pull request\nargument-hint: <pr>\nallowed-tools:\n  - Bash(gh:*)\n  - Read\nalwaysApply: false\n---\n\nReview PR $ARGUMENTS.\n"
+	if err := os.WriteFile(filepath.Join(commandsRoot, "review.command.mdc"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(commandsRoot, "release", "tag.command.mdc"), []byte("---\nglobs: \"*\"\n---\n\nTag $1.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	commandsDir := filepath.Join(t.TempDir(), ".claude", "commands")
+	if _, err := InstallClaudeCommandCollectionToDir(commandsDir, packageDir, nil, nil); err != nil {
+		t.Fatalf("InstallClaudeCommandCollectionToDir: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(commandsDir, "review.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "---\ndescription: Review a pull request\nargument-hint: <pr>\nallowed-tools: Bash(gh:*), Read\n---\n\nReview PR $ARGUMENTS.\n"
+	if string(got) != want {
+		t.Fatalf("review.md =\n%q\nwant\n%q", got, want)
+	}
+	got, err = os.ReadFile(filepath.Join(commandsDir, "release", "tag.md"))
+	if err != nil || string(got) != "Tag $1.\n" {
+		t.Fatalf("release/tag.md = %q (%v)", got, err)
+	}
+}
+
+func TestInstallGeminiCommandConvertsToTOML(t *testing.T) {
+	packageDir := t.TempDir()
+	commandsRoot := filepath.Join(packageDir, "commands")
+	if err := os.MkdirAll(filepath.Join(commandsRoot, "git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := "---\ndescription: Review a pull request\nallowed-tools: Read\n---\n\nReview PR $ARGUMENTS.\n\nDiff: !`gh pr diff ${ARGUMENTS}`\n"
+	if err := os.WriteFile(filepath.Join(commandsRoot, "review.command.mdc"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(commandsRoot, "git", "commit.md"), []byte("Commit $1 with \"quotes\".\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	commandsDir := filepath.Join(t.TempDir(), ".gemini", "commands")
+	if _, err := InstallGeminiCommandCollectionToDir(commandsDir, packageDir, nil, nil); err != nil {
+		t.Fatalf("InstallGeminiCommandCollectionToDir: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(commandsDir, "review.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "description = 'Review a pull request'\nprompt = \"\"\"\nReview PR {{args}}.\n\nDiff: !{gh pr diff {{args}}}\"\"\"\n"
+	if string(got) != want {
+		t.Fatalf("review.toml =\n%q\nwant\n%q", got, want)
+	}
+	got, err = os.ReadFile(filepath.Join(commandsDir, "git", "commit.toml"))
+	if err != nil || string(got) != "prompt = 'Commit $1 with \"quotes\".'\n" {
+		t.Fatalf("git/commit.toml = %q (%v)", got, err)
+	}
+
+	installed, err := ListInstalledGeminiCommands(commandsDir)
+	if err != nil || len(installed) != 2 || installed[0] != "git" || installed[1] != "review.toml" {
+		t.Fatalf("ListInstalledGeminiCommands = %v, %v", installed, err)
+	}
+	for _, name := range []string{"review", "git"} {
+		if err := RemoveGeminiCommand(commandsDir, name); err != nil {
+			t.Fatalf("RemoveGeminiCommand(%s): %v", name, err)
+		}
+	}
+	if installed, _ := ListInstalledGeminiCommands(commandsDir); len(installed) != 0 {
+		t.Fatalf("commands left after remove: %v", installed)
+	}
+}
@@ -77,7 +77,10 @@ func TestWatcherAutoApplyMapping(t *testing.T) {
 	// start watcher with context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	if err := StartWatcher(ctx, packageDir, true); err != nil {
+	apply := func(projectRoot, preset string) (InstallStrategy, error) {
+		return ApplyPresetToProject(projectRoot, preset, packageDir)
+	}
+	if err := StartWatcher(ctx, packageDir, true, apply); err != nil {
 		t.Fatalf("failed to start watcher: %v", err)
 	}
 
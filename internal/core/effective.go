@@ -0,0 +1,282 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/transform"
+)
+
+// EffectiveRule is one installed rule file with its parsed metadata.
+type EffectiveRule struct {
+	// Name is the file path relative to the rules directory, using forward slashes.
+	Name string `json:"name" yaml:"name"`
+	// Source is the absolute path of the installed file.
+	Source string `json:"source" yaml:"source"`
+	// Resolved is the target of the file's first @file reference, for stubs written by installs.
+	Resolved string `json:"resolved,omitempty" yaml:"resolved,omitempty"`
+	// Includes lists every file pulled in through @file references, in inclusion order.
+	Includes    []string               `json:"includes,omitempty" yaml:"includes,omitempty"`
+	Description string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Globs       []string               `json:"globs,omitempty" yaml:"globs,omitempty"`
+	AlwaysApply bool                   `json:"alwaysApply" yaml:"alwaysApply"`
+	Frontmatter map[string]interface{} `json:"frontmatter,omitempty" yaml:"frontmatter,omitempty"`
+	Body        string                 `json:"body" yaml:"body"`
+	// Size is the body size in bytes.
+	Size int `json:"size" yaml:"size"`
+	// Tokens estimates the tokens the body costs in an agent's context.
+	Tokens int `json:"tokens" yaml:"tokens"`
+
+	// Content is the raw file content.
+	Content string `json:"-" yaml:"-"`
+}
+
+// EffectiveRules returns the rules in .cursor/rules merged into one text, in path order, with
+// @file references resolved. Use LoadEffectiveRules for the rules with their metadata.
+func EffectiveRules(projectRoot string) (string, error) {
+	rules, err := LoadEffectiveRules(filepath.Join(projectRoot, ".cursor", "rules"), ".md", ".mdc")
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i := range rules {
+		if err := ResolveReferences(&rules[i], projectRoot); err != nil {
+			return "", err
+		}
+		b.WriteString("\n\n---\n# " + filepath.Base(rules[i].Source) + "\n\n" + rules[i].Text())
+	}
+	return b.String(), nil
+}
+
+// Text returns what an agent reads for the rule: the resolved body when the rule pulls in
+// other files, otherwise the file as written.
+func (r EffectiveRule) Text() string {
+	if len(r.Includes) > 0 {
+		return r.Body
+	}
+	return r.Content
+}
+
+// EstimateTokens estimates the token count of text with the default tokenizer.
+func EstimateTokens(text string) int {
+	return tokens.Count(text)
+}
+
+// LoadEffectiveRules reads every file under rulesDir whose name ends with one of extensions,
+// in path order. A missing directory yields no rules.
+func LoadEffectiveRules(rulesDir string, extensions ...string) ([]EffectiveRule, error) {
+	if _, err := os.Stat(rulesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var files []string
+	err := filepath.Walk(rulesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		for _, ext := range extensions {
+			if strings.HasSuffix(path, ext) {
+				files = append(files, path)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInternal, "walk %s", rulesDir)
+	}
+	sort.Strings(files)
+
+	rules := make([]EffectiveRule, 0, len(files))
+	for _, path := range files {
+		rule, err := ReadEffectiveRule(path)
+		if err != nil {
+			return nil, err
+		}
+		if rel, relErr := filepath.Rel(rulesDir, path); relErr == nil {
+			rule.Name = filepath.ToSlash(rel)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ReadEffectiveRule reads and parses a single rule file.
+func ReadEffectiveRule(path string) (EffectiveRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return EffectiveRule{}, errors.Wrapf(err, errors.CodeInternal, "read %s", path)
+	}
+	rule := EffectiveRule{
+		Name:    filepath.Base(path),
+		Source:  path,
+		Content: string(data),
+		Body:    strings.TrimSpace(string(data)),
+	}
+	if bytes.HasPrefix(data, []byte("---")) {
+		node, body, splitErr := transform.SplitFrontmatter(data)
+		var fm map[string]interface{}
+		switch {
+		case splitErr == nil && node.Decode(&fm) == nil:
+			rule.Frontmatter = fm
+			rule.Body = body
+		case splitErr != nil:
+			// Install stubs open a frontmatter block that never closes: "---\n@file <path>".
+			rule.Body = strings.TrimSpace(strings.TrimPrefix(string(data), "---"))
+		}
+	}
+	if rule.Frontmatter != nil {
+		if description, ok := rule.Frontmatter["description"].(string); ok {
+			rule.Description = description
+		}
+		if alwaysApply, ok := rule.Frontmatter["alwaysApply"].(bool); ok {
+			rule.AlwaysApply = alwaysApply
+		}
+		rule.Globs = globList(rule.Frontmatter["globs"])
+	}
+	rule.Resolved = fileReference(rule.Body)
+	rule.Size = len(rule.Body)
+	rule.Tokens = EstimateTokens(rule.Body)
+	return rule, nil
+}
+
+// ResolveReferences replaces every `@file <path>` line in rule's body with the body of the
+// referenced file, recursively, so the rule shows what the agent will see. Relative paths
+// resolve against projectRoot. A rule without frontmatter takes the metadata of the file it
+// references first. Reference cycles and missing targets are errors.
+func ResolveReferences(rule *EffectiveRule, projectRoot string) error {
+	r := &referenceResolver{projectRoot: projectRoot}
+	source := r.path(rule.Source)
+	r.stack = []string{source}
+	body, includes, err := r.expand(rule.Body, source)
+	if err != nil {
+		return err
+	}
+	if len(includes) == 0 {
+		return nil
+	}
+	if rule.Frontmatter == nil && rule.Resolved != "" {
+		target, err := ReadEffectiveRule(r.path(rule.Resolved))
+		if err != nil {
+			return err
+		}
+		rule.Frontmatter = target.Frontmatter
+		rule.Description = target.Description
+		rule.Globs = target.Globs
+		rule.AlwaysApply = target.AlwaysApply
+	}
+	rule.Body = body
+	rule.Includes = includes
+	rule.Size = len(body)
+	rule.Tokens = EstimateTokens(body)
+	return nil
+}
+
+type referenceResolver struct {
+	projectRoot string
+	stack       []string // files being expanded, for cycle detection
+}
+
+// expand replaces each "@file <path>" line outside fenced code with the referenced rule's body,
+// expanded in turn, and returns the files it included.
+func (r *referenceResolver) expand(body, from string) (string, []string, error) {
+	lines := strings.Split(body, "\n")
+	fenced := tokens.FencedLines(body)
+	var includes []string
+	for i, line := range lines {
+		ref, ok := strings.CutPrefix(strings.TrimSpace(line), "@file ")
+		if !ok || fenced[i] {
+			continue
+		}
+		path := r.path(strings.TrimSpace(ref))
+		if idx := slices.Index(r.stack, path); idx >= 0 {
+			cycle := make([]string, 0, len(r.stack)-idx+1)
+			for _, p := range append(r.stack[idx:], path) {
+				cycle = append(cycle, r.display(p))
+			}
+			return "", nil, errors.Newf(errors.CodeInvalidArgument, "@file cycle: %s", strings.Join(cycle, " -> "))
+		}
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return "", nil, errors.Newf(errors.CodeNotFound, "%s: @file target %s not found", r.display(from), r.display(path))
+			}
+			return "", nil, errors.Wrapf(err, errors.CodeInternal, "stat %s", path)
+		}
+		target, err := ReadEffectiveRule(path)
+		if err != nil {
+			return "", nil, err
+		}
+		r.stack = append(r.stack, path)
+		expanded, nested, err := r.expand(target.Body, path)
+		r.stack = r.stack[:len(r.stack)-1]
+		if err != nil {
+			return "", nil, err
+		}
+		lines[i] = expanded
+		includes = append(includes, path)
+		includes = append(includes, nested...)
+	}
+	return strings.Join(lines, "\n"), includes, nil
+}
+
+// path returns ref as a clean absolute path; relative references are relative to the project.
+func (r *referenceResolver) path(ref string) string {
+	if !filepath.IsAbs(ref) {
+		ref = filepath.Join(r.projectRoot, filepath.FromSlash(ref))
+	}
+	if abs, err := filepath.Abs(ref); err == nil {
+		return abs
+	}
+	return filepath.Clean(ref)
+}
+
+// display shortens paths inside the project for error messages.
+func (r *referenceResolver) display(path string) string {
+	if root, err := filepath.Abs(r.projectRoot); err == nil {
+		if rel, relErr := filepath.Rel(root, path); relErr == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+// globList normalizes a frontmatter glob value: a comma-separated string or a list.
+func globList(v interface{}) []string {
+	var raw []string
+	switch value := v.(type) {
+	case string:
+		raw = glob.SplitList(value)
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+	var out []string
+	for _, glob := range raw {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			out = append(out, glob)
+		}
+	}
+	return out
+}
+
+// fileReference returns the path of the first `@file <path>` line in content.
+func fileReference(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if ref, ok := strings.CutPrefix(strings.TrimSpace(line), "@file "); ok {
+			return strings.TrimSpace(ref)
+		}
+	}
+	return ""
+}
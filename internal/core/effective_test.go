@@ -0,0 +1,121 @@
+package core
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	apperrors "github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+)
+
+func TestLoadEffectiveRules(t *testing.T) {
+	rulesDir := testutil.CreateTestDir(t, map[string]string{
+		"go.mdc":          "---\ndescription: \"Go style\"\nglobs: \"**/*.go, go.mod\"\nalwaysApply: false\n---\nUse gofmt.\n",
+		"frontend/ui.mdc": "---\ndescription: UI\nglobs:\n  - \"web/**/*.tsx\"\nalwaysApply: true\n---\nPrefer hooks.\n",
+		"stub.mdc":        "---\n@file /pkg/shared.mdc\n",
+		"notes.txt":       "ignored",
+		"plain.md":        "No frontmatter here.",
+	})
+
+	rules, err := LoadEffectiveRules(rulesDir, ".md", ".mdc")
+	if err != nil {
+		t.Fatalf("LoadEffectiveRules: %v", err)
+	}
+	var names []string
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	if want := []string{"frontend/ui.mdc", "go.mdc", "plain.md", "stub.mdc"}; !slices.Equal(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+
+	ui, goRule, plain, stub := rules[0], rules[1], rules[2], rules[3]
+	if !ui.AlwaysApply || !slices.Equal(ui.Globs, []string{"web/**/*.tsx"}) || ui.Body != "Prefer hooks." {
+		t.Errorf("unexpected ui rule %+v", ui)
+	}
+	if goRule.Description != "Go style" || goRule.AlwaysApply || !slices.Equal(goRule.Globs, []string{"**/*.go", "go.mod"}) {
+		t.Errorf("unexpected go rule %+v", goRule)
+	}
+	if goRule.Source != filepath.Join(rulesDir, "go.mdc") || goRule.Size == 0 || goRule.Tokens != EstimateTokens("Use gofmt.") {
+		t.Errorf("unexpected go rule source/size/tokens %+v", goRule)
+	}
+	if plain.Frontmatter != nil || plain.Body != "No frontmatter here." {
+		t.Errorf("unexpected plain rule %+v", plain)
+	}
+	if stub.Resolved != "/pkg/shared.mdc" {
+		t.Errorf("stub resolved = %q, want /pkg/shared.mdc", stub.Resolved)
+	}
+}
+
+func TestLoadEffectiveRulesMissingDir(t *testing.T) {
+	rules, err := LoadEffectiveRules(filepath.Join(t.TempDir(), "missing"), ".mdc")
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules and no error, got %v, %v", rules, err)
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	project := testutil.CreateTestDir(t, map[string]string{
+		"docs/architecture.md":   "Layers: app, core.\n@file docs/glossary.md\n",
+		"docs/glossary.md":       "Glossary body.",
+		".cursor/rules/team.mdc": "---\ndescription: Team\nalwaysApply: true\n---\nTeam rules.\n\n@file docs/architecture.md\n\n~~~md\n@file docs/example.md\n~~~\n",
+	})
+	pkg := testutil.CreateTestDir(t, map[string]string{
+		"golang.mdc": "---\ndescription: \"Go\"\nglobs: \"**/*.go\"\n---\nUse gofmt.",
+	})
+	testutil.CreateTestFile(t, filepath.Join(project, ".cursor", "rules"), "golang.mdc", "---\n@file "+filepath.Join(pkg, "golang.mdc")+"\n")
+
+	rules, err := LoadEffectiveRules(filepath.Join(project, ".cursor", "rules"), ".mdc")
+	if err != nil {
+		t.Fatalf("LoadEffectiveRules: %v", err)
+	}
+	for i := range rules {
+		if err := ResolveReferences(&rules[i], project); err != nil {
+			t.Fatalf("ResolveReferences(%s): %v", rules[i].Name, err)
+		}
+	}
+
+	stub, team := rules[0], rules[1]
+	if stub.Body != "Use gofmt." || stub.Description != "Go" || !slices.Equal(stub.Globs, []string{"**/*.go"}) {
+		t.Errorf("stub should show the preset's body and metadata, got %+v", stub)
+	}
+	if want := "Team rules.\n\nLayers: app, core.\nGlossary body.\n\n~~~md\n@file docs/example.md\n~~~"; team.Body != want {
+		t.Errorf("team body = %q, want %q", team.Body, want)
+	}
+	if len(team.Includes) != 2 || team.Description != "Team" || team.Tokens != EstimateTokens(team.Body) {
+		t.Errorf("unexpected team rule %+v", team)
+	}
+}
+
+func TestResolveReferencesErrors(t *testing.T) {
+	project := testutil.CreateTestDir(t, map[string]string{
+		"a.md":                "@file b.md",
+		"b.md":                "@file a.md",
+		".cursor/rules/x.mdc": "---\ndescription: X\n---\n@file a.md\n",
+		".cursor/rules/y.mdc": "---\ndescription: Y\n---\n@file missing.md\n",
+	})
+	rulesDir := filepath.Join(project, ".cursor", "rules")
+
+	cyclic, err := ReadEffectiveRule(filepath.Join(rulesDir, "x.mdc"))
+	if err != nil {
+		t.Fatalf("ReadEffectiveRule: %v", err)
+	}
+	err = ResolveReferences(&cyclic, project)
+	if err == nil || !strings.Contains(err.Error(), "@file cycle: a.md -> b.md -> a.md") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	missing, err := ReadEffectiveRule(filepath.Join(rulesDir, "y.mdc"))
+	if err != nil {
+		t.Fatalf("ReadEffectiveRule: %v", err)
+	}
+	err = ResolveReferences(&missing, project)
+	if err == nil || !strings.Contains(err.Error(), ".cursor/rules/y.mdc: @file target missing.md not found") {
+		t.Fatalf("expected missing target error, got %v", err)
+	}
+	if apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Fatalf("expected NotFound code, got %v", apperrors.CodeOf(err))
+	}
+}
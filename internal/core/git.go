@@ -0,0 +1,80 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// GitHead returns the commit checked out in dir, or "" when dir is not a git checkout or has
+// no commits yet.
+func GitHead(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return ""
+	}
+	head, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err != nil {
+		return ""
+	}
+	return head
+}
+
+// GitChangedFiles lists paths, relative to the repository root, that differ between two commits.
+func GitChangedFiles(dir, from, to string) ([]string, error) {
+	out, err := runGit(dir, "diff", "--name-only", "--no-renames", from, to)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ExportGitTree writes the files of commit into dest without touching dir's index or worktree.
+func ExportGitTree(dir, commit, dest string) error {
+	index, err := os.CreateTemp("", "cursor-rules-index-")
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "create temporary index")
+	}
+	indexPath := index.Name()
+	_ = index.Close()
+	// git refuses to read an empty file as an index, so let read-tree create it.
+	_ = os.Remove(indexPath)
+	defer os.Remove(indexPath)
+
+	env := []string{"GIT_INDEX_FILE=" + indexPath}
+	if _, err := runGitEnv(dir, env, "read-tree", commit); err != nil {
+		return err
+	}
+	prefix, err := filepath.Abs(dest)
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "resolve export dir")
+	}
+	_, err = runGitEnv(dir, env, "checkout-index", "--all", "--force", "--prefix="+prefix+string(filepath.Separator))
+	return err
+}
+
+// runGit runs git in dir (or the current directory when dir is empty) and returns trimmed stdout.
+func runGit(dir string, args ...string) (string, error) {
+	return runGitEnv(dir, nil, args...)
+}
+
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	subcommand := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, errors.CodeInternal, "git %s failed: %s", subcommand, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
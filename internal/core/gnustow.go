@@ -76,11 +76,12 @@ func CreateSymlink(src, dest string) error {
 // CURSOR_RULES_USE_GNUSTOW and stow is available, attempt to use it (best-effort).
 func ApplyPresetWithOptionalSymlink(projectRoot, preset, packageDir string) (InstallStrategy, error) {
 	rulesDir := filepath.Join(projectRoot, ".cursor", "rules")
-	return ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir)
+	return ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir, nil)
 }
 
-// ApplyPresetWithOptionalSymlinkToRulesDir applies a preset to the given rules directory.
-func ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir string) (InstallStrategy, error) {
+// ApplyPresetWithOptionalSymlinkToRulesDir applies a preset to the given rules directory,
+// recording the installed file in written.
+func ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir string, written *Written) (InstallStrategy, error) {
 	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
 		return StrategyUnknown, err
 	}
@@ -97,6 +98,7 @@ func ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir strin
 		// #nosec G204 - packageDir, rulesDir, and preset are validated before this call
 		cmd := exec.Command("stow", "-v", "-d", packageDir, "-t", rulesDir, preset)
 		if _, err := cmd.CombinedOutput(); err == nil {
+			written.Add(dest)
 			return StrategyStow, nil
 		}
 		// else: fall through
@@ -104,11 +106,11 @@ func ApplyPresetWithOptionalSymlinkToRulesDir(rulesDir, preset, packageDir strin
 
 	// If user requested symlink behavior (explicitly or via GNU stow), create a symlink
 	if UseSymlink() || WantGNUStow() {
-		if err := CreateSymlink(src, dest); err == nil {
+		if err := written.symlink(src, dest); err == nil {
 			return StrategySymlink, nil
 		}
 	}
 
 	// Default behavior: delegate to shared helper which handles stow -> symlink -> stub
-	return ApplySourceToDest(packageDir, src, dest, preset)
+	return ApplySourceToDest(packageDir, src, dest, preset, written)
 }
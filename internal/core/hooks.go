@@ -43,41 +43,71 @@ func InstallHookPresetToProject(projectRoot, packageDir, presetName, hooksSubdir
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid project path")
 	}
-	return InstallHookPresetToDirs(destHooksDir, destJSON, packageDir, presetName, hooksSubdir)
+	return InstallHookPresetToDirs(destHooksDir, destJSON, packageDir, presetName, hooksSubdir, nil)
 }
 
-// InstallHookPresetToDirs installs a hook preset into the given hooks directory and hooks.json path.
-func InstallHookPresetToDirs(destHooksDir, destJSONPath, packageDir, presetName, hooksSubdir string) (InstallStrategy, error) {
+// InstallHookPresetToDirs installs a hook preset into the given hooks directory and hooks.json
+// path, recording the installed files in written.
+func InstallHookPresetToDirs(destHooksDir, destJSONPath, packageDir, presetName, hooksSubdir string, written *Written) (InstallStrategy, error) {
+	presetDir, cfg, err := loadHookPreset(packageDir, presetName, hooksSubdir)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	strategy, err := copyHookScripts(presetDir, destHooksDir, written)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+
+	// Rewrite command paths in cfg to .cursor/hooks/<basename>
+	rewriteHookCommands(cfg, presetDir, filepath.Join(".cursor", "hooks"))
+
+	out, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "marshal hooks.json")
+	}
+	if err := os.WriteFile(destJSONPath, out, 0o600); err != nil {
+		return StrategyUnknown, err
+	}
+	written.Add(destJSONPath)
+	return strategy, nil
+}
+
+// loadHookPreset returns the preset directory and its parsed hooks.json.
+func loadHookPreset(packageDir, presetName, hooksSubdir string) (string, *hooksConfig, error) {
 	if err := security.ValidatePackageName(presetName); err != nil {
-		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid hook preset name")
+		return "", nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid hook preset name")
 	}
 	subdir := HooksSubdir(hooksSubdir)
 	presetDir, err := security.SafeJoin(packageDir, subdir, presetName)
 	if err != nil {
-		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid path")
+		return "", nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid path")
 	}
 	jsonPath := filepath.Join(presetDir, hooksJSONName)
 	data, err := os.ReadFile(jsonPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return StrategyUnknown, errors.Newf(errors.CodeNotFound, "hook preset not found: %s", presetName)
+			return "", nil, errors.Newf(errors.CodeNotFound, "hook preset not found: %s", presetName)
 		}
-		return StrategyUnknown, err
+		return "", nil, err
 	}
 	var cfg hooksConfig
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid hooks.json")
+		return "", nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid hooks.json")
 	}
 	if cfg.Hooks == nil {
 		cfg.Hooks = make(map[string][]hookDef)
 	}
+	return presetDir, &cfg, nil
+}
+
+// copyHookScripts copies or symlinks all script files from the preset dir into destHooksDir.
+func copyHookScripts(presetDir, destHooksDir string, written *Written) (InstallStrategy, error) {
 	if err := os.MkdirAll(destHooksDir, 0o755); err != nil {
 		return StrategyUnknown, err
 	}
 
-	// Copy or symlink all script files from preset dir into .cursor/hooks/
 	strategy := StrategyCopy
-	err = filepath.WalkDir(presetDir, func(path string, d fs.DirEntry, walkErr error) error {
+	err := filepath.WalkDir(presetDir, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
@@ -93,7 +123,7 @@ func InstallHookPresetToDirs(destHooksDir, destJSONPath, packageDir, presetName,
 		}
 		dest := filepath.Join(destHooksDir, filepath.Base(path))
 		if UseSymlink() || WantGNUStow() {
-			if symErr := CreateSymlink(path, dest); symErr == nil {
+			if symErr := written.symlink(path, dest); symErr == nil {
 				strategy = StrategySymlink
 				return nil
 			}
@@ -110,22 +140,11 @@ func InstallHookPresetToDirs(destHooksDir, destJSONPath, packageDir, presetName,
 		if info.Mode()&0o111 != 0 {
 			perm = 0o700
 		}
-		return os.WriteFile(dest, content, perm)
+		return written.writeFile(dest, content, perm)
 	})
 	if err != nil {
 		return StrategyUnknown, err
 	}
-
-	// Rewrite command paths in cfg to .cursor/hooks/<basename>
-	rewriteHookCommands(&cfg, presetDir)
-
-	out, err := json.MarshalIndent(&cfg, "", "  ")
-	if err != nil {
-		return StrategyUnknown, errors.Wrapf(err, errors.CodeInternal, "marshal hooks.json")
-	}
-	if err := os.WriteFile(destJSONPath, out, 0o600); err != nil {
-		return StrategyUnknown, err
-	}
 	return strategy, nil
 }
 
@@ -144,8 +163,9 @@ type hookDef struct {
 	// allow unknown fields by using map or extra fields; json.Unmarshal will drop unknown
 }
 
-// rewriteHookCommands rewrites command paths in cfg from preset-relative to project-relative .cursor/hooks/<name>.
-func rewriteHookCommands(cfg *hooksConfig, presetDir string) {
+// rewriteHookCommands rewrites command paths in cfg that name a preset script to
+// <commandDir>/<name>, the location the scripts are installed to.
+func rewriteHookCommands(cfg *hooksConfig, presetDir, commandDir string) {
 	for event, list := range cfg.Hooks {
 		for i := range list {
 			cmd := strings.TrimSpace(list[i].Command)
@@ -164,8 +184,7 @@ func rewriteHookCommands(cfg *hooksConfig, presetDir string) {
 				continue
 			}
 			base := filepath.Base(abs)
-			// Cursor runs project hooks from project root; use .cursor/hooks/<base>
-			list[i].Command = filepath.Join(".cursor", "hooks", base)
+			list[i].Command = filepath.Join(commandDir, base)
 		}
 		cfg.Hooks[event] = list
 	}
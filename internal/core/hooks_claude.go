@@ -0,0 +1,472 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// claudeHooksKey is the settings.json key holding Claude Code hooks.
+const claudeHooksKey = "hooks"
+
+// claudeHookEvent is the Claude Code event (and tool matcher) a Cursor hook event maps to.
+type claudeHookEvent struct {
+	Event   string
+	Matcher string
+}
+
+// claudeHookEvents maps Cursor hooks.json events to Claude Code hook events. preToolUse and
+// postToolUse keep the matcher of each hook; events missing here have no Claude Code
+// equivalent and are skipped.
+var claudeHookEvents = map[string]claudeHookEvent{
+	"beforeShellExecution": {Event: "PreToolUse", Matcher: "Bash"},
+	"afterShellExecution":  {Event: "PostToolUse", Matcher: "Bash"},
+	"beforeMCPExecution":   {Event: "PreToolUse", Matcher: "mcp__.*"},
+	"afterMCPExecution":    {Event: "PostToolUse", Matcher: "mcp__.*"},
+	"beforeReadFile":       {Event: "PreToolUse", Matcher: "Read"},
+	"afterFileEdit":        {Event: "PostToolUse", Matcher: "Edit|MultiEdit|Write"},
+	"preToolUse":           {Event: "PreToolUse"},
+	"postToolUse":          {Event: "PostToolUse"},
+	"beforeSubmitPrompt":   {Event: "UserPromptSubmit"},
+	"stop":                 {Event: "Stop"},
+	"subagentStop":         {Event: "SubagentStop"},
+	"preCompact":           {Event: "PreCompact"},
+	"sessionStart":         {Event: "SessionStart"},
+	"sessionEnd":           {Event: "SessionEnd"},
+}
+
+// cursorShellTool matches Cursor's name for the shell tool in preToolUse/postToolUse matchers.
+var cursorShellTool = regexp.MustCompile(`\bShell\b`)
+
+// claudeHookGroup is one matcher entry of a Claude Code hook event.
+type claudeHookGroup struct {
+	Matcher string       `json:"matcher,omitempty"`
+	Hooks   []claudeHook `json:"hooks"`
+}
+
+type claudeHook struct {
+	Type    string `json:"type"`
+	Command string `json:"command,omitempty"`
+	Prompt  string `json:"prompt,omitempty"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+// InstallClaudeHookPresetToDirs installs a Cursor hook preset for Claude Code: scripts are copied
+// to destHooksDir, the hooks.json events are translated into Claude Code's hooks schema, and the
+// resulting hook groups are merged into the "hooks" key of settingsPath, keeping hand-written
+// hooks and every other setting. Script commands are rewritten to commandDir/<script>. The groups
+// and scripts are recorded in destHooksDir so reinstalling or removing the preset only touches
+// what it added. The scripts and settings file are recorded in written.
+func InstallClaudeHookPresetToDirs(destHooksDir, settingsPath, commandDir, packageDir, presetName, hooksSubdir string, written *Written) (InstallStrategy, error) {
+	presetDir, cfg, err := loadHookPreset(packageDir, presetName, hooksSubdir)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	rewriteHookCommands(cfg, presetDir, commandDir)
+	hooks := translateClaudeHooks(cfg)
+	if len(hooks) == 0 {
+		return StrategyUnknown, errors.Newf(errors.CodeInvalidArgument, "hook preset %s has no events Claude Code supports", presetName)
+	}
+	settings, err := readClaudeSettings(settingsPath)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	events, err := claudeSettingsHooks(settings, settingsPath)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	managed, err := readClaudeManagedHooks(destHooksDir)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	if previous, ok := managed[presetName]; ok {
+		removeClaudeHookGroups(events, previous.Hooks)
+	}
+	if err := addClaudeHookGroups(events, hooks); err != nil {
+		return StrategyUnknown, err
+	}
+
+	scripts := &Written{}
+	strategy, err := copyHookScripts(presetDir, destHooksDir, scripts)
+	if err != nil {
+		return StrategyUnknown, err
+	}
+	record := claudeManagedPreset{Hooks: hooks}
+	for _, script := range scripts.Paths() {
+		written.Add(script)
+		record.Scripts = append(record.Scripts, filepath.Base(script))
+	}
+	managed[presetName] = record
+
+	if err := setClaudeSettingsHooks(settings, events); err != nil {
+		return StrategyUnknown, err
+	}
+	if err := writeClaudeSettings(settingsPath, settings); err != nil {
+		return StrategyUnknown, err
+	}
+	written.Add(settingsPath)
+	if err := writeClaudeManagedHooks(destHooksDir, managed); err != nil {
+		return StrategyUnknown, err
+	}
+	return strategy, nil
+}
+
+// translateClaudeHooks groups hooks by Claude Code event and matcher. Cursor events are visited
+// in name order so the output is stable.
+func translateClaudeHooks(cfg *hooksConfig) map[string][]claudeHookGroup {
+	events := make([]string, 0, len(cfg.Hooks))
+	for event := range cfg.Hooks {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+
+	out := make(map[string][]claudeHookGroup)
+	for _, event := range events {
+		mapped, ok := claudeHookEvents[event]
+		if !ok {
+			continue
+		}
+		for _, def := range cfg.Hooks[event] {
+			hook := claudeHook{Type: "command", Command: strings.TrimSpace(def.Command), Timeout: def.Timeout}
+			if def.Type == "prompt" {
+				hook = claudeHook{Type: "prompt", Prompt: def.Prompt, Timeout: def.Timeout}
+			}
+			if hook.Command == "" && hook.Prompt == "" {
+				continue
+			}
+			matcher := mapped.Matcher
+			if matcher == "" && def.Matcher != "" {
+				matcher = cursorShellTool.ReplaceAllString(def.Matcher, "Bash")
+			}
+			out[mapped.Event] = appendClaudeHook(out[mapped.Event], matcher, hook)
+		}
+	}
+	return out
+}
+
+func appendClaudeHook(groups []claudeHookGroup, matcher string, hook claudeHook) []claudeHookGroup {
+	for i := range groups {
+		if groups[i].Matcher == matcher {
+			groups[i].Hooks = append(groups[i].Hooks, hook)
+			return groups
+		}
+	}
+	return append(groups, claudeHookGroup{Matcher: matcher, Hooks: []claudeHook{hook}})
+}
+
+// readClaudeSettings returns the top-level keys of settings.json; a missing file is empty.
+func readClaudeSettings(path string) (map[string]json.RawMessage, error) {
+	settings := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return settings, nil
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid Claude Code settings: %s", path)
+	}
+	return settings, nil
+}
+
+func writeClaudeSettings(path string, settings map[string]json.RawMessage) error {
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "marshal Claude Code settings")
+	}
+	return writeFileWithDirs(path, append(out, '\n'), 0o644)
+}
+
+// RemoveClaudeHooksFromDirs removes the hook groups and scripts recorded for presetName (every
+// managed preset when presetName is empty) from settings.json and hooksDir. Hand-written hooks
+// and scripts are kept; settings.json is deleted when nothing else is left in it. It reports
+// whether anything was managed.
+func RemoveClaudeHooksFromDirs(hooksDir, settingsPath, presetName string) (bool, error) {
+	managed, err := readClaudeManagedHooks(hooksDir)
+	if err != nil {
+		return false, err
+	}
+	var presets []string
+	for name := range managed {
+		if presetName == "" || name == presetName {
+			presets = append(presets, name)
+		}
+	}
+	if len(presets) == 0 {
+		return false, nil
+	}
+
+	settings, err := readClaudeSettings(settingsPath)
+	if err != nil {
+		return false, err
+	}
+	events, err := claudeSettingsHooks(settings, settingsPath)
+	if err != nil {
+		return false, err
+	}
+	scripts := make(map[string]bool)
+	for _, name := range presets {
+		removeClaudeHookGroups(events, managed[name].Hooks)
+		for _, script := range managed[name].Scripts {
+			scripts[script] = true
+		}
+		delete(managed, name)
+	}
+	// Scripts another managed preset still ships stay in place.
+	for _, record := range managed {
+		for _, script := range record.Scripts {
+			delete(scripts, script)
+		}
+	}
+
+	if err := setClaudeSettingsHooks(settings, events); err != nil {
+		return false, err
+	}
+	if len(settings) == 0 {
+		if err := os.Remove(settingsPath); err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrapf(err, errors.CodeInternal, "remove Claude Code settings")
+		}
+	} else if err := writeClaudeSettings(settingsPath, settings); err != nil {
+		return false, err
+	}
+	for script := range scripts {
+		if err := os.Remove(filepath.Join(hooksDir, script)); err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrapf(err, errors.CodeInternal, "remove hook script %s", script)
+		}
+	}
+	if err := writeClaudeManagedHooks(hooksDir, managed); err != nil {
+		return false, err
+	}
+	// Only succeeds when no hand-written scripts are left.
+	_ = os.Remove(hooksDir)
+	return true, nil
+}
+
+// ListInstalledClaudeHooksFrom returns the hook presets installed into hooksDir, ignoring
+// hand-written hooks and scripts.
+func ListInstalledClaudeHooksFrom(hooksDir string) ([]string, error) {
+	managed, err := readClaudeManagedHooks(hooksDir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(managed))
+	for name := range managed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// claudeManagedHooksName is the file in the Claude Code hooks dir recording what each installed
+// preset added, keyed by preset name.
+const claudeManagedHooksName = ".cursor-rules.json"
+
+// claudeManagedPreset is the hook groups, keyed by Claude Code event, and the script file names
+// one preset installed.
+type claudeManagedPreset struct {
+	Hooks   map[string][]claudeHookGroup `json:"hooks"`
+	Scripts []string                     `json:"scripts,omitempty"`
+}
+
+func readClaudeManagedHooks(hooksDir string) (map[string]claudeManagedPreset, error) {
+	path := filepath.Join(hooksDir, claudeManagedHooksName)
+	managed := make(map[string]claudeManagedPreset)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return managed, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &managed); err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid managed hooks record: %s", path)
+	}
+	return managed, nil
+}
+
+// writeClaudeManagedHooks saves the record, deleting it once no preset is left.
+func writeClaudeManagedHooks(hooksDir string, managed map[string]claudeManagedPreset) error {
+	path := filepath.Join(hooksDir, claudeManagedHooksName)
+	if len(managed) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, errors.CodeInternal, "remove managed hooks record")
+		}
+		return nil
+	}
+	out, err := json.MarshalIndent(managed, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "marshal managed hooks record")
+	}
+	return writeFileWithDirs(path, append(out, '\n'), 0o644)
+}
+
+// claudeSettingsHooks decodes the "hooks" key of settings into raw groups per event, so groups
+// this tool does not model pass through unchanged.
+func claudeSettingsHooks(settings map[string]json.RawMessage, settingsPath string) (map[string][]json.RawMessage, error) {
+	events := make(map[string][]json.RawMessage)
+	raw, ok := settings[claudeHooksKey]
+	if !ok {
+		return events, nil
+	}
+	if err := json.Unmarshal(raw, &events); err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid hooks in Claude Code settings: %s", settingsPath)
+	}
+	return events, nil
+}
+
+// setClaudeSettingsHooks stores events under the "hooks" key, dropping the key when no event has
+// groups left.
+func setClaudeSettingsHooks(settings map[string]json.RawMessage, events map[string][]json.RawMessage) error {
+	for event, groups := range events {
+		if len(groups) == 0 {
+			delete(events, event)
+		}
+	}
+	if len(events) == 0 {
+		delete(settings, claudeHooksKey)
+		return nil
+	}
+	raw, err := json.Marshal(events)
+	if err != nil {
+		return errors.Wrapf(err, errors.CodeInternal, "marshal Claude Code hooks")
+	}
+	settings[claudeHooksKey] = raw
+	return nil
+}
+
+func addClaudeHookGroups(events map[string][]json.RawMessage, hooks map[string][]claudeHookGroup) error {
+	for event, groups := range hooks {
+		for _, group := range groups {
+			raw, err := json.Marshal(group)
+			if err != nil {
+				return errors.Wrapf(err, errors.CodeInternal, "marshal Claude Code hooks")
+			}
+			events[event] = append(events[event], raw)
+		}
+	}
+	return nil
+}
+
+// removeClaudeHookGroups drops every group in events that matches a recorded group.
+func removeClaudeHookGroups(events map[string][]json.RawMessage, hooks map[string][]claudeHookGroup) {
+	for event, groups := range hooks {
+		for _, group := range groups {
+			want, err := json.Marshal(group)
+			if err != nil {
+				continue
+			}
+			if i := indexClaudeHookGroup(events[event], want); i >= 0 {
+				events[event] = slices.Delete(events[event], i, i+1)
+			}
+		}
+	}
+}
+
+// indexClaudeHookGroup returns the index of the first group equal to want, or -1. Groups are
+// compared as compact JSON, so a group edited by hand no longer matches.
+func indexClaudeHookGroup(groups []json.RawMessage, want []byte) int {
+	return slices.IndexFunc(groups, func(raw json.RawMessage) bool {
+		var compact bytes.Buffer
+		return json.Compact(&compact, raw) == nil && bytes.Equal(compact.Bytes(), want)
+	})
+}
+
+// claudeHookPaths returns the hooks dir and settings.json of the Claude Code dir that path, a
+// file installed for a hook preset, belongs to. Scripts and the managed hooks record live in the
+// hooks dir; settings.json sits next to it.
+func claudeHookPaths(path string) (hooksDir, settingsPath string) {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == "hooks" {
+		return dir, filepath.Join(filepath.Dir(dir), "settings.json")
+	}
+	return filepath.Join(dir, "hooks"), path
+}
+
+// ClaudeHooksOwnedContent returns the part of a file installed for presetName that the preset
+// owns: for settings.json, the preset's recorded hook groups still present in data (as compact
+// JSON keyed by event); nothing for the managed hooks record; the whole file for scripts.
+func ClaudeHooksOwnedContent(path, presetName string, data []byte) []byte {
+	hooksDir, settingsPath := claudeHookPaths(path)
+	switch path {
+	case filepath.Join(hooksDir, claudeManagedHooksName):
+		return nil
+	case settingsPath:
+	default:
+		return data
+	}
+	managed, err := readClaudeManagedHooks(hooksDir)
+	if err != nil {
+		return nil
+	}
+	record, ok := managed[presetName]
+	if !ok {
+		return nil
+	}
+	settings := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil
+	}
+	events, err := claudeSettingsHooks(settings, settingsPath)
+	if err != nil {
+		return nil
+	}
+	owned := make(map[string][]json.RawMessage)
+	for event, groups := range record.Hooks {
+		for _, group := range groups {
+			want, err := json.Marshal(group)
+			if err != nil {
+				continue
+			}
+			if i := indexClaudeHookGroup(events[event], want); i >= 0 {
+				events[event] = slices.Delete(events[event], i, i+1)
+				owned[event] = append(owned[event], want)
+			}
+		}
+	}
+	if len(owned) == 0 {
+		return nil
+	}
+	out, err := json.Marshal(owned)
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// RemoveClaudeHooksOwnedContent removes the part of a file installed for presetName that the
+// preset owns: its hook groups (and record) for settings.json, and a script unless another
+// managed preset still ships it. settings.json itself is only deleted once nothing is left in it.
+func RemoveClaudeHooksOwnedContent(path, presetName string) error {
+	hooksDir, settingsPath := claudeHookPaths(path)
+	switch path {
+	case filepath.Join(hooksDir, claudeManagedHooksName):
+		return nil
+	case settingsPath:
+		_, err := RemoveClaudeHooksFromDirs(hooksDir, settingsPath, presetName)
+		return err
+	}
+	managed, err := readClaudeManagedHooks(hooksDir)
+	if err != nil {
+		return err
+	}
+	for name, record := range managed {
+		if name != presetName && slices.Contains(record.Scripts, filepath.Base(path)) {
+			return nil
+		}
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, errors.CodeInternal, "remove hook script %s", filepath.Base(path))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallClaudeHookPresetTranslatesEvents(t *testing.T) {
+	packageDir := t.TempDir()
+	presetDir := filepath.Join(packageDir, "hooks", "guard")
+	if err := os.MkdirAll(presetDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(presetDir, "guard.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	hooksJSON := `{"version":1,"hooks":{
+		"beforeShellExecution":[{"command":"./guard.sh","timeout":10}],
+		"afterFileEdit":[{"command":"npx prettier --write"}],
+		"preToolUse":[{"command":"./guard.sh","matcher":"Shell|Write"}],
+		"stop":[{"type":"prompt","prompt":"Did you run the tests?"}],
+		"afterAgentThought":[{"command":"./guard.sh"}]
+	}}`
+	if err := os.WriteFile(filepath.Join(presetDir, "hooks.json"), []byte(hooksJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	claudeDir := filepath.Join(t.TempDir(), ".claude")
+	settingsPath := filepath.Join(claudeDir, "settings.json")
+	if err := os.MkdirAll(claudeDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	handWritten := `{"hooks":[{"type":"command","command":"mine.sh"}]}`
+	if err := os.WriteFile(settingsPath, []byte(`{"model":"opus","hooks":{"Stop":[`+handWritten+`]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hooksDir := filepath.Join(claudeDir, "hooks")
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooksDir, "mine.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Installing twice must replace the preset's groups rather than duplicate them.
+	for range 2 {
+		if _, err := InstallClaudeHookPresetToDirs(hooksDir, settingsPath, `"$CLAUDE_PROJECT_DIR"/.claude/hooks`, packageDir, "guard", "", nil); err != nil {
+			t.Fatalf("InstallClaudeHookPresetToDirs: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "guard.sh")); err != nil {
+		t.Fatalf("expected script copied: %v", err)
+	}
+
+	var settings struct {
+		Model string                       `json:"model"`
+		Hooks map[string][]claudeHookGroup `json:"hooks"`
+	}
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("settings.json: %v", err)
+	}
+	if settings.Model != "opus" {
+		t.Fatalf("expected other settings kept, got %s", data)
+	}
+	if len(settings.Hooks) != 3 {
+		t.Fatalf("expected PreToolUse, PostToolUse and Stop only, got %s", data)
+	}
+	script := filepath.Join(`"$CLAUDE_PROJECT_DIR"/.claude/hooks`, "guard.sh")
+	pre := settings.Hooks["PreToolUse"]
+	if len(pre) != 2 || pre[0].Matcher != "Bash" || pre[0].Hooks[0].Command != script || pre[0].Hooks[0].Timeout != 10 {
+		t.Fatalf("unexpected PreToolUse: %+v", pre)
+	}
+	if pre[1].Matcher != "Bash|Write" {
+		t.Fatalf("expected Cursor Shell matcher translated, got %q", pre[1].Matcher)
+	}
+	post := settings.Hooks["PostToolUse"]
+	if len(post) != 1 || post[0].Matcher != "Edit|MultiEdit|Write" || post[0].Hooks[0].Command != "npx prettier --write" {
+		t.Fatalf("unexpected PostToolUse: %+v", post)
+	}
+	stop := settings.Hooks["Stop"]
+	if len(stop) != 2 || stop[0].Hooks[0].Command != "mine.sh" {
+		t.Fatalf("expected the hand-written Stop hook kept first, got %+v", stop)
+	}
+	if stop[1].Hooks[0].Type != "prompt" || stop[1].Hooks[0].Prompt == "" {
+		t.Fatalf("unexpected Stop: %+v", stop)
+	}
+
+	if installed, err := ListInstalledClaudeHooksFrom(hooksDir); err != nil || len(installed) != 1 || installed[0] != "guard" {
+		t.Fatalf("ListInstalledClaudeHooksFrom = %v, %v", installed, err)
+	}
+	if removed, err := RemoveClaudeHooksFromDirs(hooksDir, settingsPath, "other"); err != nil || removed {
+		t.Fatalf("RemoveClaudeHooksFromDirs(other) = %v, %v", removed, err)
+	}
+	if removed, err := RemoveClaudeHooksFromDirs(hooksDir, settingsPath, "guard"); err != nil || !removed {
+		t.Fatalf("RemoveClaudeHooksFromDirs = %v, %v", removed, err)
+	}
+	data, err = os.ReadFile(settingsPath)
+	if err != nil || string(data) != "{\n  \"hooks\": {\n    \"Stop\": [\n      {\n        \"hooks\": [\n          {\n            \"type\": \"command\",\n            \"command\": \"mine.sh\"\n          }\n        ]\n      }\n    ]\n  },\n  \"model\": \"opus\"\n}\n" {
+		t.Fatalf("expected only the preset's hooks removed, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "guard.sh")); !os.IsNotExist(err) {
+		t.Fatalf("expected preset script removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "mine.sh")); err != nil {
+		t.Fatalf("expected hand-written script kept: %v", err)
+	}
+	if installed, err := ListInstalledClaudeHooksFrom(hooksDir); err != nil || len(installed) != 0 {
+		t.Fatalf("ListInstalledClaudeHooksFrom after remove = %v, %v", installed, err)
+	}
+}
+
+func TestRemoveClaudeHooksDeletesEmptySettings(t *testing.T) {
+	packageDir := t.TempDir()
+	presetDir := filepath.Join(packageDir, "hooks", "fmt")
+	if err := os.MkdirAll(presetDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(presetDir, "hooks.json"), []byte(`{"version":1,"hooks":{"afterFileEdit":[{"command":"gofmt -w ."}]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	claudeDir := t.TempDir()
+	hooksDir := filepath.Join(claudeDir, "hooks")
+	settingsPath := filepath.Join(claudeDir, "settings.json")
+	if _, err := InstallClaudeHookPresetToDirs(hooksDir, settingsPath, "hooks", packageDir, "fmt", "", nil); err != nil {
+		t.Fatalf("InstallClaudeHookPresetToDirs: %v", err)
+	}
+	if removed, err := RemoveClaudeHooksFromDirs(hooksDir, settingsPath, ""); err != nil || !removed {
+		t.Fatalf("RemoveClaudeHooksFromDirs = %v, %v", removed, err)
+	}
+	for _, path := range []string{settingsPath, hooksDir} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s removed, got %v", path, err)
+		}
+	}
+}
+
+func TestInstallClaudeHookPresetRejectsUnsupportedEvents(t *testing.T) {
+	packageDir := t.TempDir()
+	presetDir := filepath.Join(packageDir, "hooks", "tab")
+	if err := os.MkdirAll(presetDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(presetDir, "hooks.json"), []byte(`{"version":1,"hooks":{"afterTabFileEdit":[{"command":"true"}]}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	claudeDir := t.TempDir()
+	settingsPath := filepath.Join(claudeDir, "settings.json")
+	if _, err := InstallClaudeHookPresetToDirs(filepath.Join(claudeDir, "hooks"), settingsPath, "hooks", packageDir, "tab", "", nil); err == nil {
+		t.Fatal("expected an error for a preset without Claude Code events")
+	}
+	if _, err := os.Stat(settingsPath); !os.IsNotExist(err) {
+		t.Fatalf("settings.json must not be written, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// Managed sections let several installs share one hand-editable markdown file (such as
+// AGENTS.md): each install owns the text between its begin and end markers, and everything
+// outside the markers belongs to the user.
+const (
+	managedSectionBegin = "<!-- cursor-rules:begin %s -->"
+	managedSectionEnd   = "<!-- cursor-rules:end %s -->"
+)
+
+var managedSectionBeginLine = regexp.MustCompile(`(?m)^<!-- cursor-rules:begin (\S+) -->$`)
+
+// ManagedSection returns the section owned by name, markers included.
+func ManagedSection(data []byte, name string) (string, bool) {
+	start, end, ok := findManagedSection(string(data), name)
+	if !ok {
+		return "", false
+	}
+	return string(data[start:end]), true
+}
+
+// ManagedSectionNames returns the names of the sections in data, in file order.
+func ManagedSectionNames(data []byte) []string {
+	var names []string
+	for _, match := range managedSectionBeginLine.FindAllSubmatch(data, -1) {
+		name := string(match[1])
+		if _, _, ok := findManagedSection(string(data), name); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// UpsertManagedSection writes content as the section owned by name in the file at path,
+// replacing the section in place or appending it after the existing text. The file is created
+// when missing and left untouched when nothing changes.
+func UpsertManagedSection(path, name, content string) error {
+	if err := validateSectionName(name); err != nil {
+		return err
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, errors.CodeInternal, "read %s", path)
+	}
+	text := string(existing)
+	begin, closing := sectionMarkers(name)
+	block := begin + "\n" + strings.TrimSpace(content) + "\n" + closing
+
+	var out string
+	if start, end, ok := findManagedSection(text, name); ok {
+		out = text[:start] + block + text[end:]
+	} else if strings.TrimSpace(text) == "" {
+		out = block + "\n"
+	} else {
+		out = strings.TrimRight(text, "\n") + "\n\n" + block + "\n"
+	}
+	return writeIfChanged(path, []byte(out))
+}
+
+// RemoveManagedSection deletes the section owned by name from the file at path, and the file
+// itself when only whitespace is left. It reports whether a section was removed.
+func RemoveManagedSection(path, name string) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, errors.CodeInternal, "read %s", path)
+	}
+	text := string(existing)
+	start, end, ok := findManagedSection(text, name)
+	if !ok {
+		return false, nil
+	}
+	before := strings.TrimRight(text[:start], "\n")
+	after := strings.TrimLeft(text[end:], "\n")
+	var out string
+	switch {
+	case before == "":
+		out = after
+	case after == "":
+		out = before + "\n"
+	default:
+		out = before + "\n\n" + after
+	}
+	if strings.TrimSpace(out) == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrapf(err, errors.CodeInternal, "remove %s", path)
+		}
+		return true, nil
+	}
+	return true, writeIfChanged(path, []byte(out))
+}
+
+// findManagedSection returns the byte range of name's section, from its begin marker through
+// its end marker. A begin marker without a matching end marker is not a section.
+func findManagedSection(text, name string) (start, end int, ok bool) {
+	begin, closing := sectionMarkers(name)
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], begin)
+		if idx < 0 {
+			return 0, 0, false
+		}
+		start = offset + idx
+		// Only a marker on a line of its own counts.
+		if (start == 0 || text[start-1] == '\n') && lineEndsAt(text, start+len(begin)) {
+			rest := text[start+len(begin):]
+			if endIdx := strings.Index(rest, "\n"+closing); endIdx >= 0 && lineEndsAt(rest, endIdx+1+len(closing)) {
+				return start, start + len(begin) + endIdx + 1 + len(closing), true
+			}
+			return 0, 0, false
+		}
+		offset = start + len(begin)
+	}
+}
+
+func sectionMarkers(name string) (begin, end string) {
+	return fmt.Sprintf(managedSectionBegin, name), fmt.Sprintf(managedSectionEnd, name)
+}
+
+func lineEndsAt(text string, i int) bool {
+	return i == len(text) || text[i] == '\n' || (text[i] == '\r' && (i+1 == len(text) || text[i+1] == '\n'))
+}
+
+func validateSectionName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") || strings.Contains(name, "--") {
+		return errors.Newf(errors.CodeInvalidArgument, "invalid managed section name: %q", name)
+	}
+	return nil
+}
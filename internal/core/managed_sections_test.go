@@ -0,0 +1,67 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUpsertManagedSectionReplacesInPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGENTS.md")
+	if err := os.WriteFile(path, []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []struct{ name, content string }{{"a", "first"}, {"b", "second"}, {"a", "\nupdated\n"}} {
+		if err := UpsertManagedSection(path, step.name, step.content); err != nil {
+			t.Fatalf("UpsertManagedSection(%s): %v", step.name, err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	want := "# Notes\n\n" +
+		"<!-- cursor-rules:begin a -->\nupdated\n<!-- cursor-rules:end a -->\n\n" +
+		"<!-- cursor-rules:begin b -->\nsecond\n<!-- cursor-rules:end b -->\n"
+	if string(data) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", data, want)
+	}
+	if names := ManagedSectionNames(data); !slices.Equal(names, []string{"a", "b"}) {
+		t.Fatalf("ManagedSectionNames = %v", names)
+	}
+	if err := UpsertManagedSection(path, "bad name", "x"); err == nil {
+		t.Fatal("expected an error for a name with spaces")
+	}
+}
+
+func TestRemoveManagedSectionKeepsSurroundingText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGENTS.md")
+	content := "intro\n\n<!-- cursor-rules:begin a -->\nrule\n<!-- cursor-rules:end a -->\n\noutro\n" +
+		"<!-- cursor-rules:begin dangling -->\nno end marker\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if names := ManagedSectionNames([]byte(content)); !slices.Equal(names, []string{"a"}) {
+		t.Fatalf("ManagedSectionNames = %v, want only complete sections", names)
+	}
+	removed, err := RemoveManagedSection(path, "a")
+	if err != nil || !removed {
+		t.Fatalf("RemoveManagedSection = %v, %v", removed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "intro\n\noutro\n<!-- cursor-rules:begin dangling -->\nno end marker\n"; string(data) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", data, want)
+	}
+	if removed, _ := RemoveManagedSection(path, "dangling"); removed {
+		t.Fatal("a section without an end marker must not be removed")
+	}
+
+	onlyManaged := filepath.Join(t.TempDir(), "AGENTS.md")
+	if err := UpsertManagedSection(onlyManaged, "a", "rule"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RemoveManagedSection(onlyManaged, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(onlyManaged); !os.IsNotExist(err) {
+		t.Fatalf("file with nothing left should be deleted, got %v", err)
+	}
+}
@@ -103,13 +103,14 @@ func DefaultPackageDir() string {
 // .cursor-rules-ignore file placed inside the package which lists patterns to skip.
 // By default, packages are flattened into .cursor/rules/. Use noFlatten=true to preserve structure.
 func InstallPackage(projectRoot, packageName string, excludes []string, noFlatten bool) (InstallStrategy, error) {
-	return InstallPackageFromPackageDir(projectRoot, DefaultPackageDir(), packageName, excludes, noFlatten)
+	return InstallPackageFromPackageDir(projectRoot, DefaultPackageDir(), packageName, excludes, noFlatten, nil)
 }
 
 // InstallPackageFromPackageDir installs a package directory from an explicit packageDir into the project's
 // .cursor/rules. This is the same behavior as InstallPackage, but allows callers (like the CLI) to
-// honor config-provided package directories without relying on environment variables.
-func InstallPackageFromPackageDir(projectRoot, packageDir, packageName string, excludes []string, noFlatten bool) (InstallStrategy, error) {
+// honor config-provided package directories without relying on environment variables. The
+// installed files are recorded in written.
+func InstallPackageFromPackageDir(projectRoot, packageDir, packageName string, excludes []string, noFlatten bool, written *Written) (InstallStrategy, error) {
 	// Validate package name for security
 	if err := security.ValidatePackageName(packageName); err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid package name")
@@ -129,11 +130,12 @@ func InstallPackageFromPackageDir(projectRoot, packageDir, packageName string, e
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid rules directory path")
 	}
-	return InstallPackageToRulesDir(rulesDir, packageDir, packageName, excludes, noFlatten)
+	return InstallPackageToRulesDir(rulesDir, packageDir, packageName, excludes, noFlatten, written)
 }
 
-// InstallPackageToRulesDir installs a package directory into the given rules directory.
-func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes []string, noFlatten bool) (InstallStrategy, error) {
+// InstallPackageToRulesDir installs a package directory into the given rules directory,
+// recording the installed files in written.
+func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes []string, noFlatten bool, written *Written) (InstallStrategy, error) {
 	pkgDir, err := security.SafeJoin(packageDir, packageName)
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid package path")
@@ -143,26 +145,9 @@ func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes
 		return StrategyUnknown, errors.Newf(errors.CodeNotFound, "package not found: %s", pkgDir)
 	}
 
-	ignorePath, err := security.SafeJoin(pkgDir, ".cursor-rules-ignore")
+	ignored, err := packageIgnoreSet(pkgDir, ".cursor-rules-ignore", excludes)
 	if err != nil {
-		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid ignore file path")
-	}
-	var ignorePatterns []string
-	if b, err := os.ReadFile(ignorePath); err == nil {
-		lines := strings.Split(string(b), "\n")
-		for _, l := range lines {
-			l = strings.TrimSpace(l)
-			if l == "" || strings.HasPrefix(l, "#") {
-				continue
-			}
-			ignorePatterns = append(ignorePatterns, l)
-		}
-	}
-	for _, ex := range excludes {
-		ex = strings.TrimSpace(ex)
-		if ex != "" {
-			ignorePatterns = append(ignorePatterns, ex)
-		}
+		return StrategyUnknown, err
 	}
 
 	if err := os.MkdirAll(rulesDir, 0o755); err != nil {
@@ -192,12 +177,8 @@ func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes
 			return errors.Wrapf(validErr, errors.CodeInvalidArgument, "invalid file path in package")
 		}
 
-		// Check ignore patterns (simple glob match)
-		for _, pat := range ignorePatterns {
-			matched, matchErr := filepath.Match(pat, rel)
-			if matchErr == nil && matched {
-				return nil
-			}
+		if ignored.Matches(rel) {
+			return nil
 		}
 
 		// Destination path preserves package name as prefix to avoid collisions
@@ -221,7 +202,7 @@ func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes
 		// If symlink/stow requested, attempt to use ApplyPresetWithOptionalSymlink semantics
 		// For package installs, prefer creating a symlink to the source file when available.
 		if UseSymlink() || WantGNUStow() {
-			if symlinkErr := CreateSymlink(path, dest); symlinkErr == nil {
+			if symlinkErr := written.symlink(path, dest); symlinkErr == nil {
 				usedStrategy = StrategySymlink
 				return nil
 			}
@@ -242,6 +223,7 @@ func InstallPackageToRulesDir(rulesDir, packageDir, packageName string, excludes
 		if _, copyErr := io.Copy(out, in); copyErr != nil {
 			return copyErr
 		}
+		written.Add(dest)
 		return nil
 	})
 	if err != nil {
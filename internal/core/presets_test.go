@@ -216,6 +216,40 @@ func TestInstallPackageWithIgnoreAndFlatten(t *testing.T) {
 	}
 }
 
+func TestInstallPackageIgnoreDoublestarAndNegation(t *testing.T) {
+	packageDir := t.TempDir()
+	pkg := filepath.Join(packageDir, "pkg")
+	for rel, content := range map[string]string{
+		"a.mdc":                      "a",
+		"drafts/deep/wip.mdc":        "wip",
+		"templates/base.mdc":         "base",
+		"templates/nested/keep.mdc":  "keep",
+		"templates/nested/other.mdc": "other",
+		".cursor-rules-ignore":       "# local only\ndrafts\ntemplates/**\n!templates/**/keep.mdc\n",
+	} {
+		path := filepath.Join(pkg, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+
+	proj := t.TempDir()
+	if _, err := InstallPackage(proj, "pkg", nil, false); err != nil {
+		t.Fatalf("InstallPackage failed: %v", err)
+	}
+	rulesDir := filepath.Join(proj, ".cursor", "rules")
+	for name, want := range map[string]bool{"a.mdc": true, "keep.mdc": true, "wip.mdc": false, "base.mdc": false, "other.mdc": false} {
+		_, err := os.Stat(filepath.Join(rulesDir, name))
+		if got := err == nil; got != want {
+			t.Errorf("%s installed = %v, want %v", name, got, want)
+		}
+	}
+}
+
 func TestInstallPackageWithGNUStowRequestCreatesSymlinks(t *testing.T) {
 	packageDir := t.TempDir()
 	pkgDir := filepath.Join(packageDir, "pkg")
@@ -0,0 +1,556 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectProfile is a structured description of a project built from several signals: marker
+// files, source file extensions, dependency manifests, test tooling and CI configuration.
+type ProjectProfile struct {
+	// Path is the profile's location relative to the profiled root ("." for the root itself).
+	Path string `json:"path" yaml:"path"`
+	// Type is the primary project type (go, node, node-monorepo, python, rust, ...).
+	Type           string   `json:"type" yaml:"type"`
+	Languages      []string `json:"languages" yaml:"languages"`
+	Frameworks     []string `json:"frameworks" yaml:"frameworks"`
+	TestFrameworks []string `json:"testFrameworks" yaml:"testFrameworks"`
+	CI             []string `json:"ci" yaml:"ci"`
+	// Monorepo is set when workspace definitions (npm/yarn/pnpm, lerna, go.work, Cargo) are found.
+	Monorepo   bool              `json:"monorepo" yaml:"monorepo"`
+	Workspaces []*ProjectProfile `json:"workspaces,omitempty" yaml:"workspaces,omitempty"`
+}
+
+// monorepoMarkers identify a monorepo even when none of its workspace globs match yet.
+var monorepoMarkers = []string{"lerna.json", "turbo.json", "nx.json", "pnpm-workspace.yaml", "pnpm-workspace.yml", "go.work"}
+
+// profileWalkLimit caps the number of files inspected for source extensions.
+const profileWalkLimit = 5000
+
+// profileSkipDirs are never descended into when scanning sources.
+var profileSkipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true,
+	"target": true, ".venv": true, "venv": true, "__pycache__": true, ".next": true,
+	".cursor": true, ".github": true, ".idea": true, ".vscode": true,
+}
+
+var extensionLanguages = map[string]string{
+	".go": "go", ".ts": "typescript", ".tsx": "typescript", ".mts": "typescript", ".cts": "typescript",
+	".js": "javascript", ".jsx": "javascript", ".mjs": "javascript", ".cjs": "javascript",
+	".py": "python", ".rs": "rust", ".java": "java", ".kt": "kotlin", ".kts": "kotlin",
+	".rb": "ruby", ".php": "php", ".cs": "csharp", ".swift": "swift",
+	".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".hpp": "cpp",
+}
+
+// languageMarkers maps files found at a project root to the language they indicate.
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "go"},
+	{"package.json", "javascript"},
+	{"tsconfig.json", "typescript"},
+	{"pyproject.toml", "python"},
+	{"requirements.txt", "python"},
+	{"setup.py", "python"},
+	{"Cargo.toml", "rust"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"build.gradle.kts", "kotlin"},
+	{"Gemfile", "ruby"},
+	{"composer.json", "php"},
+}
+
+// npmFrameworks and npmTestFrameworks map package.json dependency names to profile names.
+var npmFrameworks = map[string]string{
+	"react": "react", "next": "nextjs", "vue": "vue", "nuxt": "nuxt", "svelte": "svelte",
+	"@sveltejs/kit": "sveltekit", "@angular/core": "angular", "express": "express",
+	"fastify": "fastify", "@nestjs/core": "nestjs", "astro": "astro", "solid-js": "solid",
+	"@remix-run/react": "remix", "electron": "electron", "tailwindcss": "tailwind",
+}
+
+var npmTestFrameworks = map[string]string{
+	"jest": "jest", "vitest": "vitest", "mocha": "mocha", "@playwright/test": "playwright",
+	"cypress": "cypress", "ava": "ava",
+}
+
+// goFrameworks and goTestFrameworks map go.mod module path prefixes to profile names.
+var goFrameworks = map[string]string{
+	"github.com/gin-gonic/gin": "gin", "github.com/labstack/echo": "echo",
+	"github.com/gofiber/fiber": "fiber", "github.com/go-chi/chi": "chi",
+	"github.com/gorilla/mux": "gorilla-mux", "github.com/spf13/cobra": "cobra",
+	"google.golang.org/grpc": "grpc", "gorm.io/gorm": "gorm",
+}
+
+var goTestFrameworks = map[string]string{
+	"github.com/stretchr/testify": "testify", "github.com/onsi/ginkgo": "ginkgo",
+}
+
+// pythonFrameworks and pythonTestFrameworks are matched against requirement names.
+var pythonFrameworks = map[string]string{
+	"django": "django", "flask": "flask", "fastapi": "fastapi", "starlette": "starlette",
+}
+
+var pythonTestFrameworks = map[string]string{
+	"pytest": "pytest", "nose2": "nose2", "hypothesis": "hypothesis",
+}
+
+var rustFrameworks = map[string]string{
+	"actix-web": "actix-web", "axum": "axum", "rocket": "rocket", "tokio": "tokio",
+}
+
+// testConfigMarkers are files whose presence identifies a test framework.
+var testConfigMarkers = []struct {
+	pattern   string
+	framework string
+}{
+	{"jest.config.*", "jest"},
+	{"vitest.config.*", "vitest"},
+	{"playwright.config.*", "playwright"},
+	{"cypress.config.*", "cypress"},
+	{".mocharc*", "mocha"},
+	{"pytest.ini", "pytest"},
+	{"conftest.py", "pytest"},
+}
+
+// ciMarkers are paths whose presence identifies a CI system.
+var ciMarkers = []struct {
+	path string
+	ci   string
+}{
+	{".github/workflows", "github-actions"},
+	{".gitlab-ci.yml", "gitlab-ci"},
+	{".circleci/config.yml", "circleci"},
+	{"Jenkinsfile", "jenkins"},
+	{"azure-pipelines.yml", "azure-pipelines"},
+	{".travis.yml", "travis"},
+	{"bitbucket-pipelines.yml", "bitbucket-pipelines"},
+	{".buildkite", "buildkite"},
+	{".drone.yml", "drone"},
+}
+
+// ProfileProject builds the profile of the project at root, including one nested profile per
+// declared monorepo workspace.
+func ProfileProject(root string) (*ProjectProfile, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, &fs.PathError{Op: "profile", Path: root, Err: fs.ErrInvalid}
+	}
+	profile := profileDir(root, ".")
+	workspaces := detectWorkspaces(root)
+	profile.Monorepo = len(workspaces) > 0
+	for _, marker := range monorepoMarkers {
+		if exists(root, marker) {
+			profile.Monorepo = true
+		}
+	}
+	for _, rel := range workspaces {
+		ws := profileDir(filepath.Join(root, rel), filepath.ToSlash(rel))
+		profile.Workspaces = append(profile.Workspaces, ws)
+	}
+	profile.Type = primaryType(root, profile)
+	return profile, nil
+}
+
+func profileDir(dir, rel string) *ProjectProfile {
+	p := &ProjectProfile{Path: rel}
+	languages := newStringSet()
+	frameworks := newStringSet()
+	tests := newStringSet()
+	ci := newStringSet()
+
+	for _, marker := range languageMarkers {
+		if exists(dir, marker.file) {
+			languages.add(marker.language)
+		}
+	}
+	hasGoTests := false
+	scanned := 0
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && (profileSkipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		scanned++
+		if scanned > profileWalkLimit {
+			return filepath.SkipAll
+		}
+		if lang, ok := extensionLanguages[strings.ToLower(filepath.Ext(d.Name()))]; ok {
+			languages.add(lang)
+		}
+		if strings.HasSuffix(d.Name(), "_test.go") {
+			hasGoTests = true
+		}
+		return nil
+	})
+
+	if deps, ok := readPackageJSONDeps(filepath.Join(dir, "package.json")); ok {
+		for _, dep := range deps {
+			if name, found := npmFrameworks[dep]; found {
+				frameworks.add(name)
+			}
+			if strings.HasPrefix(dep, "@remix-run/") {
+				frameworks.add("remix")
+			}
+			if name, found := npmTestFrameworks[dep]; found {
+				tests.add(name)
+			}
+			if dep == "typescript" {
+				languages.add("typescript")
+			}
+		}
+	}
+	for _, req := range readGoModRequires(filepath.Join(dir, "go.mod")) {
+		for prefix, name := range goFrameworks {
+			if req == prefix || strings.HasPrefix(req, prefix+"/") {
+				frameworks.add(name)
+			}
+		}
+		for prefix, name := range goTestFrameworks {
+			if req == prefix || strings.HasPrefix(req, prefix+"/") {
+				tests.add(name)
+			}
+		}
+	}
+	if hasGoTests {
+		tests.add("go-test")
+	}
+	pyReqs := append(readRequirementNames(filepath.Join(dir, "requirements.txt")), readPyprojectNames(filepath.Join(dir, "pyproject.toml"))...)
+	for _, req := range pyReqs {
+		if name, found := pythonFrameworks[req]; found {
+			frameworks.add(name)
+		}
+		if name, found := pythonTestFrameworks[req]; found {
+			tests.add(name)
+		}
+	}
+	for _, crate := range readCargoDependencies(filepath.Join(dir, "Cargo.toml")) {
+		if name, found := rustFrameworks[crate]; found {
+			frameworks.add(name)
+		}
+	}
+	for _, marker := range testConfigMarkers {
+		if matches, _ := filepath.Glob(filepath.Join(dir, marker.pattern)); len(matches) > 0 {
+			tests.add(marker.framework)
+		}
+	}
+	for _, marker := range ciMarkers {
+		if exists(dir, marker.path) {
+			ci.add(marker.ci)
+		}
+	}
+
+	p.Languages = languages.sorted()
+	p.Frameworks = frameworks.sorted()
+	p.TestFrameworks = tests.sorted()
+	p.CI = ci.sorted()
+	p.Type = primaryType(dir, p)
+	return p
+}
+
+// primaryType keeps the single-string project types earlier releases reported.
+func primaryType(dir string, p *ProjectProfile) string {
+	nodeMarkers := exists(dir, "package.json") || exists(dir, "package-lock.json") || exists(dir, "yarn.lock") ||
+		exists(dir, "pnpm-lock.yaml") || exists(dir, "pnpm-lock.yml")
+	nodeWorkspaces := exists(dir, "pnpm-workspace.yaml") || exists(dir, "pnpm-workspace.yml") || exists(dir, "lerna.json") || exists(dir, "turbo.json")
+	if p.Monorepo && (nodeMarkers || nodeWorkspaces) {
+		return "node-monorepo"
+	}
+	switch {
+	case exists(dir, "go.mod") || exists(dir, "go.work"):
+		return "go"
+	case exists(dir, "pyproject.toml") || exists(dir, "requirements.txt"):
+		return "python"
+	case exists(dir, "Cargo.toml"):
+		return "rust"
+	case nodeMarkers:
+		return "node"
+	case exists(dir, "Makefile"):
+		return "make"
+	case exists(dir, "Dockerfile"):
+		return "docker"
+	case len(p.CI) > 0:
+		return "ci"
+	}
+	return "unknown"
+}
+
+// detectWorkspaces returns the workspace directories (relative to root) declared by npm/yarn
+// workspaces, pnpm-workspace.yaml, lerna.json, go.work and Cargo workspaces.
+func detectWorkspaces(root string) []string {
+	var patterns []string
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil {
+		var pkg struct {
+			Workspaces json.RawMessage `json:"workspaces"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 {
+			var list []string
+			var obj struct {
+				Packages []string `json:"packages"`
+			}
+			if json.Unmarshal(pkg.Workspaces, &list) == nil {
+				patterns = append(patterns, list...)
+			} else if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+				patterns = append(patterns, obj.Packages...)
+			}
+		}
+	}
+	for _, name := range []string{"pnpm-workspace.yaml", "pnpm-workspace.yml"} {
+		if data, err := os.ReadFile(filepath.Join(root, name)); err == nil {
+			var ws struct {
+				Packages []string `yaml:"packages"`
+			}
+			if yaml.Unmarshal(data, &ws) == nil {
+				patterns = append(patterns, ws.Packages...)
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "lerna.json")); err == nil {
+		var lerna struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(data, &lerna) == nil {
+			patterns = append(patterns, lerna.Packages...)
+		}
+	}
+	patterns = append(patterns, readGoWorkUses(filepath.Join(root, "go.work"))...)
+	patterns = append(patterns, readCargoWorkspaceMembers(filepath.Join(root, "Cargo.toml"))...)
+
+	var negated []string
+	for _, pattern := range patterns {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(pattern), "!"); ok {
+			negated = append(negated, rest)
+		}
+	}
+	excluded, _ := glob.CompileSet(negated)
+
+	seen := newStringSet()
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/**"), "/")
+		matches, err := glob.Glob(root, pattern)
+		if err != nil {
+			continue
+		}
+		for _, rel := range matches {
+			info, statErr := os.Stat(filepath.Join(root, filepath.FromSlash(rel)))
+			if statErr != nil || !info.IsDir() || strings.HasPrefix(rel, "..") || excluded.Matches(rel) {
+				continue
+			}
+			seen.add(rel)
+		}
+	}
+	return seen.sorted()
+}
+
+func readPackageJSONDeps(path string) ([]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, false
+	}
+	var deps []string
+	for _, m := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name := range m {
+			deps = append(deps, name)
+		}
+	}
+	sort.Strings(deps)
+	return deps, true
+}
+
+// readGoModRequires returns the module paths required by a go.mod file.
+func readGoModRequires(path string) []string {
+	var out []string
+	inBlock := false
+	forEachLine(path, func(line string) {
+		switch {
+		case strings.HasPrefix(line, "require ("):
+			inBlock = true
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			if fields := strings.Fields(line); len(fields) > 0 {
+				out = append(out, fields[0])
+			}
+		case strings.HasPrefix(line, "require "):
+			if fields := strings.Fields(strings.TrimPrefix(line, "require ")); len(fields) > 0 {
+				out = append(out, fields[0])
+			}
+		}
+	})
+	return out
+}
+
+// readGoWorkUses returns the module directories listed by `use` in a go.work file.
+func readGoWorkUses(path string) []string {
+	var out []string
+	inBlock := false
+	forEachLine(path, func(line string) {
+		switch {
+		case strings.HasPrefix(line, "use ("):
+			inBlock = true
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			out = append(out, strings.Trim(line, `"`))
+		case strings.HasPrefix(line, "use "):
+			out = append(out, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	})
+	return out
+}
+
+var requirementName = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// readRequirementNames returns the lower-cased package names of a requirements.txt file.
+func readRequirementNames(path string) []string {
+	var out []string
+	forEachLine(path, func(line string) {
+		if strings.HasPrefix(line, "-") {
+			return
+		}
+		if m := requirementName.FindStringSubmatch(line); m != nil {
+			out = append(out, strings.ToLower(m[1]))
+		}
+	})
+	return out
+}
+
+var (
+	pyprojectQuotedDep = regexp.MustCompile(`["']([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(?:[<>=!~;]|["'])`)
+	tomlTableKey       = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*=`)
+)
+
+// readPyprojectNames returns dependency names found in a pyproject.toml: PEP 621 string
+// requirements and Poetry-style dependency tables.
+func readPyprojectNames(path string) []string {
+	var out []string
+	inDepsTable := false
+	forEachLine(path, func(line string) {
+		if strings.HasPrefix(line, "[") {
+			inDepsTable = strings.Contains(line, "dependencies")
+			return
+		}
+		for _, m := range pyprojectQuotedDep.FindAllStringSubmatch(line, -1) {
+			out = append(out, strings.ToLower(m[1]))
+		}
+		if inDepsTable {
+			if m := tomlTableKey.FindStringSubmatch(line); m != nil {
+				out = append(out, strings.ToLower(m[1]))
+			}
+		}
+	})
+	return out
+}
+
+// readCargoDependencies returns crate names listed in the dependency tables of a Cargo.toml.
+func readCargoDependencies(path string) []string {
+	var out []string
+	inDeps := false
+	forEachLine(path, func(line string) {
+		if strings.HasPrefix(line, "[") {
+			inDeps = strings.HasSuffix(strings.Trim(line, "[]"), "dependencies")
+			return
+		}
+		if inDeps {
+			if m := tomlTableKey.FindStringSubmatch(line); m != nil {
+				out = append(out, m[1])
+			}
+		}
+	})
+	return out
+}
+
+var quotedString = regexp.MustCompile(`"([^"]+)"`)
+
+// readCargoWorkspaceMembers returns the members of a Cargo [workspace] table.
+func readCargoWorkspaceMembers(path string) []string {
+	var out []string
+	inWorkspace, inMembers := false, false
+	forEachLine(path, func(line string) {
+		if strings.HasPrefix(line, "[") && !inMembers {
+			inWorkspace = line == "[workspace]"
+			return
+		}
+		if inWorkspace && strings.HasPrefix(line, "members") {
+			inMembers = true
+		}
+		if inMembers {
+			for _, m := range quotedString.FindAllStringSubmatch(line, -1) {
+				out = append(out, m[1])
+			}
+			if strings.Contains(line, "]") {
+				inMembers = false
+			}
+		}
+	})
+	return out
+}
+
+// forEachLine calls fn with every trimmed, non-empty, non-comment line of the file at path.
+func forEachLine(path string, fn func(line string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		fn(line)
+	}
+}
+
+func exists(dir, rel string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+type stringSet map[string]struct{}
+
+func newStringSet() stringSet { return stringSet{} }
+
+func (s stringSet) add(v string) { s[v] = struct{}{} }
+
+// sorted returns the set's values in order; an empty set yields an empty, non-nil slice so
+// JSON output shows [] rather than null.
+func (s stringSet) sorted() []string {
+	out := make([]string, 0, len(s))
+	for v := range s {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+)
+
+func TestProfileProjectNodeMonorepo(t *testing.T) {
+	root := testutil.CreateTestDir(t, map[string]string{
+		"package.json":              `{"name": "mono", "private": true, "workspaces": ["apps/*", "packages/*"], "devDependencies": {"turbo": "^2", "typescript": "^5"}}`,
+		".github/workflows/ci.yml":  "on: push\n",
+		"apps/web/package.json":     `{"dependencies": {"next": "14", "react": "18"}, "devDependencies": {"vitest": "^1", "@playwright/test": "^1"}}`,
+		"apps/web/src/page.tsx":     "export default function Page() {}\n",
+		"packages/api/package.json": `{"dependencies": {"express": "^4"}, "devDependencies": {"jest": "^29"}}`,
+		"packages/api/index.js":     "module.exports = {}\n",
+		"node_modules/x/index.py":   "ignored\n",
+	})
+
+	profile, err := ProfileProject(root)
+	if err != nil {
+		t.Fatalf("ProfileProject: %v", err)
+	}
+	if profile.Type != "node-monorepo" || !profile.Monorepo {
+		t.Fatalf("expected node-monorepo, got type=%q monorepo=%v", profile.Type, profile.Monorepo)
+	}
+	if want := []string{"javascript", "typescript"}; !slices.Equal(profile.Languages, want) {
+		t.Fatalf("languages = %v, want %v (node_modules must be skipped)", profile.Languages, want)
+	}
+	if want := []string{"github-actions"}; !slices.Equal(profile.CI, want) {
+		t.Fatalf("ci = %v, want %v", profile.CI, want)
+	}
+	if len(profile.Workspaces) != 2 {
+		t.Fatalf("expected 2 workspaces, got %+v", profile.Workspaces)
+	}
+
+	web, api := profile.Workspaces[0], profile.Workspaces[1]
+	if web.Path != "apps/web" || api.Path != "packages/api" {
+		t.Fatalf("unexpected workspace paths %q, %q", web.Path, api.Path)
+	}
+	if want := []string{"nextjs", "react"}; !slices.Equal(web.Frameworks, want) {
+		t.Fatalf("web frameworks = %v, want %v", web.Frameworks, want)
+	}
+	if want := []string{"playwright", "vitest"}; !slices.Equal(web.TestFrameworks, want) {
+		t.Fatalf("web test frameworks = %v, want %v", web.TestFrameworks, want)
+	}
+	if want := []string{"express"}; !slices.Equal(api.Frameworks, want) || api.Type != "node" {
+		t.Fatalf("api profile = %+v", api)
+	}
+}
+
+func TestProfileProjectGoAndPython(t *testing.T) {
+	goRoot := testutil.CreateTestDir(t, map[string]string{
+		"go.mod":            "module example.com/svc\n\ngo 1.22\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgithub.com/stretchr/testify v1.9.0 // indirect\n)\n",
+		"main.go":           "package main\n",
+		"main_test.go":      "package main\n",
+		".gitlab-ci.yml":    "test:\n  script: go test ./...\n",
+		"web/static/app.js": "",
+	})
+	profile, err := ProfileProject(goRoot)
+	if err != nil {
+		t.Fatalf("ProfileProject: %v", err)
+	}
+	if profile.Type != "go" {
+		t.Fatalf("type = %q, want go", profile.Type)
+	}
+	if want := []string{"go", "javascript"}; !slices.Equal(profile.Languages, want) {
+		t.Fatalf("languages = %v, want %v", profile.Languages, want)
+	}
+	if want := []string{"gin"}; !slices.Equal(profile.Frameworks, want) {
+		t.Fatalf("frameworks = %v, want %v", profile.Frameworks, want)
+	}
+	if want := []string{"go-test", "testify"}; !slices.Equal(profile.TestFrameworks, want) {
+		t.Fatalf("test frameworks = %v, want %v", profile.TestFrameworks, want)
+	}
+	if want := []string{"gitlab-ci"}; !slices.Equal(profile.CI, want) {
+		t.Fatalf("ci = %v, want %v", profile.CI, want)
+	}
+
+	pyRoot := testutil.CreateTestDir(t, map[string]string{
+		"pyproject.toml": "[project]\nname = \"svc\"\ndependencies = [\"fastapi>=0.110\", \"uvicorn[standard]\"]\n\n[project.optional-dependencies]\ntest = [\"pytest>=8\"]\n",
+		"app/main.py":    "",
+	})
+	profile, err = ProfileProject(pyRoot)
+	if err != nil {
+		t.Fatalf("ProfileProject: %v", err)
+	}
+	if profile.Type != "python" || !slices.Equal(profile.Frameworks, []string{"fastapi"}) || !slices.Equal(profile.TestFrameworks, []string{"pytest"}) {
+		t.Fatalf("unexpected python profile %+v", profile)
+	}
+}
+
+func TestProfileProjectGoWorkspaces(t *testing.T) {
+	root := testutil.CreateTestDir(t, map[string]string{
+		"go.work":         "go 1.22\n\nuse (\n\t./api\n\t./cli\n)\n",
+		"api/go.mod":      "module example.com/api\n\nrequire google.golang.org/grpc v1.60.0\n",
+		"cli/go.mod":      "module example.com/cli\n\nrequire github.com/spf13/cobra v1.8.0\n",
+		"cli/cmd/main.go": "package main\n",
+	})
+	profile, err := ProfileProject(root)
+	if err != nil {
+		t.Fatalf("ProfileProject: %v", err)
+	}
+	if profile.Type != "go" || !profile.Monorepo || len(profile.Workspaces) != 2 {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	if got := profile.Workspaces[0].Frameworks; !slices.Equal(got, []string{"grpc"}) {
+		t.Fatalf("api frameworks = %v", got)
+	}
+	if got := profile.Workspaces[1].Frameworks; !slices.Equal(got, []string{"cobra"}) {
+		t.Fatalf("cli frameworks = %v", got)
+	}
+}
+
+func TestDetectProjectTypeUsesProfile(t *testing.T) {
+	root := testutil.CreateTestDir(t, map[string]string{
+		"pnpm-workspace.yaml": "packages:\n  - 'packages/*'\n",
+		"package.json":        `{"name": "mono"}`,
+	})
+	got, err := DetectProjectType(root)
+	if err != nil {
+		t.Fatalf("DetectProjectType: %v", err)
+	}
+	if got != "node-monorepo" {
+		t.Fatalf("DetectProjectType = %q, want node-monorepo", got)
+	}
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// SyncRemoteRepo makes dir a checkout of url at ref. A missing dir is cloned; an existing
+// checkout is fetched. An empty ref follows the remote's default branch; otherwise ref may name a
+// branch, a tag or a commit. dir is a managed cache, so local changes in it are discarded.
+func SyncRemoteRepo(url, dir, ref string) error {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return errors.New(errors.CodeInvalidArgument, "missing repository url")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return errors.Wrapf(err, errors.CodeInternal, "create cache dir for %s", url)
+		}
+		// A half-finished clone from an earlier run would make git refuse the new one.
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(err, errors.CodeInternal, "clear cache dir %s", dir)
+		}
+		if _, err := runGit("", "clone", "--quiet", url, dir); err != nil {
+			return err
+		}
+	} else {
+		current, err := runGit(dir, "remote", "get-url", "origin")
+		if err != nil {
+			return err
+		}
+		if current != url {
+			if _, err := runGit(dir, "remote", "set-url", "origin", url); err != nil {
+				return err
+			}
+		}
+		if _, err := runGit(dir, "fetch", "--quiet", "--prune", "--tags", "--force", "origin"); err != nil {
+			return err
+		}
+	}
+
+	commit, err := resolveRemoteRef(dir, strings.TrimSpace(ref))
+	if err != nil {
+		return err
+	}
+	if _, err := runGit(dir, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return err
+	}
+	_, err = runGit(dir, "clean", "--quiet", "-fd")
+	return err
+}
+
+// resolveRemoteRef returns the commit ref points at after a fetch, trying remote branches first,
+// then tags, then commit ids.
+func resolveRemoteRef(dir, ref string) (string, error) {
+	if ref == "" {
+		if _, err := runGit(dir, "remote", "set-head", "origin", "--auto"); err != nil {
+			return "", err
+		}
+		return runGit(dir, "rev-parse", "--verify", "refs/remotes/origin/HEAD^{commit}")
+	}
+	for _, candidate := range []string{"refs/remotes/origin/" + ref, "refs/tags/" + ref, ref} {
+		if commit, err := runGit(dir, "rev-parse", "--verify", "--quiet", candidate+"^{commit}"); err == nil {
+			return commit, nil
+		}
+	}
+	return "", errors.Newf(errors.CodeNotFound, "ref %q not found in %s", ref, dir)
+}
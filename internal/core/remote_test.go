@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/testutil"
+)
+
+func readCacheFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestSyncRemoteRepoClonesAndFollowsDefaultBranch(t *testing.T) {
+	bare, work := testutil.InitRemoteRepo(t)
+	testutil.CommitAndPush(t, work, "v1", map[string]string{"git.mdc": "v1"})
+	cache := filepath.Join(t.TempDir(), "sources", "org")
+
+	if err := SyncRemoteRepo(bare, cache, ""); err != nil {
+		t.Fatalf("initial sync failed: %v", err)
+	}
+	if got := readCacheFile(t, cache, "git.mdc"); got != "v1" {
+		t.Fatalf("expected v1 after clone, got %q", got)
+	}
+
+	testutil.CommitAndPush(t, work, "v2", map[string]string{"git.mdc": "v2"})
+	if err := SyncRemoteRepo(bare, cache, ""); err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+	if got := readCacheFile(t, cache, "git.mdc"); got != "v2" {
+		t.Fatalf("expected v2 after fetch, got %q", got)
+	}
+}
+
+func TestSyncRemoteRepoPinsToRef(t *testing.T) {
+	bare, work := testutil.InitRemoteRepo(t)
+	first := testutil.CommitAndPush(t, work, "v1", map[string]string{"git.mdc": "v1"})
+	testutil.RunGit(t, work, "tag", "v1.0.0")
+	testutil.RunGit(t, work, "push", "--quiet", "origin", "v1.0.0")
+	testutil.CommitAndPush(t, work, "v2", map[string]string{"git.mdc": "v2"})
+	testutil.RunGit(t, work, "push", "--quiet", "origin", "HEAD:refs/heads/stable")
+	testutil.CommitAndPush(t, work, "v3", map[string]string{"git.mdc": "v3"})
+	cache := filepath.Join(t.TempDir(), "org")
+
+	cases := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "v1.0.0", want: "v1"},
+		{ref: "stable", want: "v2"},
+		{ref: first, want: "v1"},
+		{ref: "main", want: "v3"},
+	}
+	for _, tc := range cases {
+		if err := SyncRemoteRepo(bare, cache, tc.ref); err != nil {
+			t.Fatalf("sync at %s failed: %v", tc.ref, err)
+		}
+		if got := readCacheFile(t, cache, "git.mdc"); got != tc.want {
+			t.Errorf("ref %s: expected %q, got %q", tc.ref, tc.want, got)
+		}
+	}
+
+	if err := SyncRemoteRepo(bare, cache, "does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown ref")
+	}
+}
+
+func TestSyncRemoteRepoDiscardsLocalChanges(t *testing.T) {
+	bare, work := testutil.InitRemoteRepo(t)
+	testutil.CommitAndPush(t, work, "v1", map[string]string{"git.mdc": "v1"})
+	cache := filepath.Join(t.TempDir(), "org")
+	if err := SyncRemoteRepo(bare, cache, ""); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "git.mdc"), []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cache, "stray.mdc"), []byte("stray"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SyncRemoteRepo(bare, cache, ""); err != nil {
+		t.Fatalf("resync failed: %v", err)
+	}
+	if got := readCacheFile(t, cache, "git.mdc"); got != "v1" {
+		t.Errorf("expected local edit to be discarded, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "stray.mdc")); !os.IsNotExist(err) {
+		t.Errorf("expected untracked file to be removed")
+	}
+}
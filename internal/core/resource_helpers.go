@@ -52,34 +52,21 @@ func InstallPackageGeneric(projectRoot, packageDir, packageName, destSubdir stri
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid destination path")
 	}
-	return InstallPackageGenericToDest(destRoot, packageDir, packageName, exts, ignoreFileName, excludes, noFlatten)
+	return InstallPackageGenericToDest(destRoot, packageDir, packageName, exts, ignoreFileName, excludes, noFlatten, nil)
 }
 
-// InstallPackageGenericToDest installs a package into the given destRoot directory.
-func InstallPackageGenericToDest(destRoot, packageDir, packageName string, exts []string, ignoreFileName string, excludes []string, noFlatten bool) (InstallStrategy, error) {
+// InstallPackageGenericToDest installs a package into the given destRoot directory, recording
+// the installed files in written.
+func InstallPackageGenericToDest(destRoot, packageDir, packageName string, exts []string, ignoreFileName string, excludes []string, noFlatten bool, written *Written) (InstallStrategy, error) {
 	pkgDir := filepath.Join(packageDir, packageName)
 	info, err := os.Stat(pkgDir)
 	if err != nil || !info.IsDir() {
 		return StrategyUnknown, errors.Newf(errors.CodeNotFound, "package not found: %s", pkgDir)
 	}
 
-	ignorePath := filepath.Join(pkgDir, ignoreFileName)
-	var ignorePatterns []string
-	if b, err := os.ReadFile(ignorePath); err == nil {
-		lines := strings.Split(string(b), "\n")
-		for _, l := range lines {
-			l = strings.TrimSpace(l)
-			if l == "" || strings.HasPrefix(l, "#") {
-				continue
-			}
-			ignorePatterns = append(ignorePatterns, l)
-		}
-	}
-	for _, ex := range excludes {
-		ex = strings.TrimSpace(ex)
-		if ex != "" {
-			ignorePatterns = append(ignorePatterns, ex)
-		}
+	ignored, err := packageIgnoreSet(pkgDir, ignoreFileName, excludes)
+	if err != nil {
+		return StrategyUnknown, err
 	}
 
 	if err := os.MkdirAll(destRoot, 0o755); err != nil {
@@ -111,12 +98,8 @@ func InstallPackageGenericToDest(destRoot, packageDir, packageName string, exts
 			return err
 		}
 
-		// Check ignore patterns (simple glob match)
-		for _, pat := range ignorePatterns {
-			matched, matchErr := filepath.Match(pat, rel)
-			if matchErr == nil && matched {
-				return nil
-			}
+		if ignored.Matches(rel) {
+			return nil
 		}
 
 		// Destination path
@@ -132,7 +115,7 @@ func InstallPackageGenericToDest(destRoot, packageDir, packageName string, exts
 		}
 
 		// Delegate applying source to dest (stow/symlink or stub)
-		strategy, applyErr := ApplySourceToDest(packageDir, path, dest, packageName)
+		strategy, applyErr := ApplySourceToDest(packageDir, path, dest, packageName, written)
 		if applyErr == nil && strategy != StrategyCopy {
 			usedStrategy = strategy
 		}
@@ -175,7 +158,8 @@ func AtomicWriteString(tmpDir, dest, content string, perm os.FileMode) error {
 
 // ApplySourceToDest attempts to apply a source file to dest using stow (packageName),
 // then symlink, and finally falls back to writing a stub that references the source.
-func ApplySourceToDest(packageDir, src, dest, packageName string) (InstallStrategy, error) {
+// dest is recorded in written.
+func ApplySourceToDest(packageDir, src, dest, packageName string, written *Written) (InstallStrategy, error) {
 	destDir := filepath.Dir(dest)
 	// Try GNU stow if requested
 	if WantGNUStow() && HasStow() {
@@ -183,13 +167,14 @@ func ApplySourceToDest(packageDir, src, dest, packageName string) (InstallStrate
 		cmd := exec.Command("stow", "-v", "-d", packageDir, "-t", destDir, packageName)
 		if out, err := cmd.CombinedOutput(); err == nil {
 			_ = out
+			written.Add(dest)
 			return StrategyStow, nil
 		}
 		// else fallthrough
 	}
 	// Try symlink if requested
 	if UseSymlink() || WantGNUStow() {
-		if err := CreateSymlink(src, dest); err == nil {
+		if err := written.symlink(src, dest); err == nil {
 			return StrategySymlink, nil
 		}
 		// else fallthrough to stub write
@@ -199,6 +184,7 @@ func ApplySourceToDest(packageDir, src, dest, packageName string) (InstallStrate
 	if err := AtomicWriteString(destDir, dest, content, 0o644); err != nil {
 		return StrategyUnknown, err
 	}
+	written.Add(dest)
 	return StrategyCopy, nil
 }
 
@@ -68,10 +68,10 @@ func installNamedFileResource(projectRoot, sourceRoot, destSubdir, name, ext str
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid destination path")
 	}
-	return installNamedFileResourceTo(destDir, sourceRoot, name, ext)
+	return installNamedFileResourceTo(destDir, sourceRoot, name, ext, nil)
 }
 
-func installNamedFileResourceTo(destDir, sourceRoot, name, ext string) (InstallStrategy, error) {
+func installNamedFileResourceTo(destDir, sourceRoot, name, ext string, written *Written) (InstallStrategy, error) {
 	if err := security.ValidatePackageName(name); err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid resource name")
 	}
@@ -92,10 +92,10 @@ func installNamedFileResourceTo(destDir, sourceRoot, name, ext string) (InstallS
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid resource destination")
 	}
-	return ApplySourceToDest(sourceRoot, src, dest, name)
+	return ApplySourceToDest(sourceRoot, src, dest, name, written)
 }
 
-func installNamedDirectoryResourceTo(destParent, sourceRoot, name, sentinel string) (InstallStrategy, error) {
+func installNamedDirectoryResourceTo(destParent, sourceRoot, name, sentinel string, written *Written) (InstallStrategy, error) {
 	if err := security.ValidatePackageName(name); err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid resource name")
 	}
@@ -134,6 +134,7 @@ func installNamedDirectoryResourceTo(destParent, sourceRoot, name, sentinel stri
 		cmd := exec.Command("stow", "-v", "-d", sourceRoot, "-t", destParent, name)
 		if out, cmdErr := cmd.CombinedOutput(); cmdErr == nil {
 			_ = out
+			written.Add(destRoot)
 			return StrategyStow, nil
 		}
 	}
@@ -161,7 +162,7 @@ func installNamedDirectoryResourceTo(destParent, sourceRoot, name, sentinel stri
 		}
 
 		if UseSymlink() || WantGNUStow() {
-			if symErr := CreateSymlink(path, dest); symErr == nil {
+			if symErr := written.symlink(path, dest); symErr == nil {
 				strategy = StrategySymlink
 				return nil
 			}
@@ -179,7 +180,7 @@ func installNamedDirectoryResourceTo(destParent, sourceRoot, name, sentinel stri
 		if perm == 0 {
 			perm = 0o600
 		}
-		return os.WriteFile(dest, data, perm)
+		return written.writeFile(dest, data, perm)
 	})
 	if err != nil {
 		return StrategyUnknown, err
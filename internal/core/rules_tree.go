@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
 	"github.com/ZanzyTHEbar/cursor-rules/internal/security"
 )
 
@@ -103,7 +104,7 @@ func collectPackageRuleFiles(packageDir, packageName string) ([]string, error) {
 		return []string{}, nil
 	}
 
-	ignorePatterns, err := readPackageIgnorePatterns(pkgDir, ".cursor-rules-ignore")
+	ignored, err := packageIgnoreSet(pkgDir, ".cursor-rules-ignore", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -130,11 +131,8 @@ func collectPackageRuleFiles(packageDir, packageName string) ([]string, error) {
 			return errors.Wrapf(err, errors.CodeInvalidArgument, "invalid file path in package %s", packageName)
 		}
 
-		for _, pat := range ignorePatterns {
-			matched, matchErr := filepath.Match(pat, rel)
-			if matchErr == nil && matched {
-				return nil
-			}
+		if ignored.Matches(rel) {
+			return nil
 		}
 
 		files = append(files, rel)
@@ -146,24 +144,22 @@ func collectPackageRuleFiles(packageDir, packageName string) ([]string, error) {
 	return files, nil
 }
 
-func readPackageIgnorePatterns(pkgDir, ignoreFileName string) ([]string, error) {
+// packageIgnoreSet compiles the package's ignore file followed by excludes. Patterns use the
+// glob package syntax; a later `!pattern` re-includes what an earlier pattern ignored.
+func packageIgnoreSet(pkgDir, ignoreFileName string, excludes []string) (*glob.Set, error) {
 	ignorePath := filepath.Join(pkgDir, ignoreFileName)
+	var patterns []string
 	b, err := os.ReadFile(ignorePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
+	switch {
+	case err == nil:
+		patterns = strings.Split(string(b), "\n")
+	case !os.IsNotExist(err):
 		return nil, err
 	}
-
-	lines := strings.Split(string(b), "\n")
-	patterns := make([]string, 0, len(lines))
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		patterns = append(patterns, line)
+	patterns = append(patterns, excludes...)
+	set, err := glob.CompileSet(patterns)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid ignore pattern for %s", pkgDir)
 	}
-	return patterns, nil
+	return set, nil
 }
@@ -75,15 +75,16 @@ func InstallSkillToProject(projectRoot, packageDir, skillName, skillsSubdir stri
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid destination path")
 	}
-	return installNamedDirectoryResourceTo(destParent, skillsRoot, skillName, "SKILL.md")
+	return installNamedDirectoryResourceTo(destParent, skillsRoot, skillName, "SKILL.md", nil)
 }
 
-// InstallSkillToDir installs a skill directory into the given skills directory.
-func InstallSkillToDir(skillsDir, packageDir, skillName, skillsSubdir string) (InstallStrategy, error) {
+// InstallSkillToDir installs a skill directory into the given skills directory, recording its
+// files in written.
+func InstallSkillToDir(skillsDir, packageDir, skillName, skillsSubdir string, written *Written) (InstallStrategy, error) {
 	subdir := SkillsSubdir(skillsSubdir)
 	skillsRoot, err := security.SafeJoin(packageDir, subdir)
 	if err != nil {
 		return StrategyUnknown, errors.Wrapf(err, errors.CodeInvalidArgument, "invalid path")
 	}
-	return installNamedDirectoryResourceTo(skillsDir, skillsRoot, skillName, "SKILL.md")
+	return installNamedDirectoryResourceTo(skillsDir, skillsRoot, skillName, "SKILL.md", written)
 }
@@ -10,9 +10,12 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
-// StartWatcher watches packageDir recursively for changes and optionally auto-applies presets to projects.
-// It runs until ctx is canceled.
-func StartWatcher(ctx context.Context, packageDir string, autoApply bool) error {
+// ApplyFunc installs preset into the project at projectRoot and returns the strategy used.
+type ApplyFunc func(projectRoot, preset string) (InstallStrategy, error)
+
+// StartWatcher watches packageDir recursively for changes and optionally auto-applies presets to projects
+// with apply. It runs until ctx is canceled.
+func StartWatcher(ctx context.Context, packageDir string, autoApply bool, apply ApplyFunc) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return err
@@ -93,7 +96,7 @@ func StartWatcher(ctx context.Context, packageDir string, autoApply bool) error
 						if mapping != nil {
 							if projects, ok := mapping[name]; ok {
 								for _, proj := range projects {
-									strategy, err := ApplyPresetToProject(proj, name, packageDir)
+									strategy, err := apply(proj, name)
 									if err != nil {
 										slog.Warn("watcher failed to apply preset", "preset", name, "project", proj, "error", err)
 									} else {
@@ -0,0 +1,74 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Written collects the files (or, for stow installs, directories) an install created or updated,
+// so callers can record exactly what was installed, and the warnings raised while writing them.
+// A nil *Written records nothing.
+type Written struct {
+	paths    []string
+	warnings []string
+}
+
+// Add records path as written.
+func (w *Written) Add(path string) {
+	if w != nil {
+		w.paths = append(w.paths, filepath.Clean(path))
+	}
+}
+
+// Paths returns the recorded paths, sorted and without duplicates.
+func (w *Written) Paths() []string {
+	if w == nil {
+		return nil
+	}
+	out := slices.Clone(w.paths)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// Warn records a warning about the file at path, such as a rule truncated to fit its target.
+func (w *Written) Warn(path, warning string) {
+	if w != nil {
+		w.warnings = append(w.warnings, filepath.Base(path)+": "+warning)
+	}
+}
+
+// Warnings returns the recorded warnings in the order they were raised.
+func (w *Written) Warnings() []string {
+	if w == nil {
+		return nil
+	}
+	return slices.Clone(w.warnings)
+}
+
+// writeIfChanged writes data to path unless it already holds it, and records path either way.
+func (w *Written) writeIfChanged(path string, data []byte) error {
+	if err := writeIfChanged(path, data); err != nil {
+		return err
+	}
+	w.Add(path)
+	return nil
+}
+
+// writeFile writes data to path, creating parent directories, and records path.
+func (w *Written) writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := writeFileWithDirs(path, data, perm); err != nil {
+		return err
+	}
+	w.Add(path)
+	return nil
+}
+
+// symlink links dest to src and records dest.
+func (w *Written) symlink(src, dest string) error {
+	if err := CreateSymlink(src, dest); err != nil {
+		return err
+	}
+	w.Add(dest)
+	return nil
+}
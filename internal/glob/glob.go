@@ -0,0 +1,499 @@
+// Package glob matches slash-separated paths against glob patterns.
+//
+// Patterns support `*` and `?` (never crossing `/`), `**` as a whole segment for any number of
+// directories, character classes (`[abc]`, `[a-z]`, `[!a]` or `[^a]`), brace alternatives
+// (`{a,b}`, nestable), `\` escapes and, in a Set, a leading `!` to negate a pattern.
+package glob
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrBadPattern indicates a malformed pattern. Errors returned by Compile wrap it with the
+// problem found but do not repeat the pattern.
+var ErrBadPattern = errors.New("syntax error in glob pattern")
+
+// Pattern is a compiled glob pattern.
+type Pattern struct {
+	raw    string
+	negate bool
+	// alts holds one segment list per brace alternative.
+	alts [][]string
+}
+
+// Compile parses pattern. A leading "!" marks the pattern as negated; Match ignores it and
+// Set uses it to re-include paths.
+func Compile(pattern string) (*Pattern, error) {
+	raw := pattern
+	pattern = strings.TrimSpace(pattern)
+	p := &Pattern{raw: raw}
+	if strings.HasPrefix(pattern, "!") {
+		p.negate = true
+		pattern = pattern[1:]
+	}
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("%w: empty pattern", ErrBadPattern)
+	}
+	alternatives, msg := expandBraces(pattern)
+	if msg != "" {
+		return nil, fmt.Errorf("%w: %s", ErrBadPattern, msg)
+	}
+	for _, alt := range alternatives {
+		segments := compactSegments(strings.Split(alt, "/"))
+		for _, segment := range segments {
+			if msg := validateSegment(segment); msg != "" {
+				return nil, fmt.Errorf("%w: %s", ErrBadPattern, msg)
+			}
+		}
+		p.alts = append(p.alts, segments)
+	}
+	return p, nil
+}
+
+// Validate reports whether pattern is a well-formed glob.
+func Validate(pattern string) error {
+	_, err := Compile(pattern)
+	return err
+}
+
+// Match reports whether name matches pattern; a negated pattern inverts the result.
+func Match(pattern, name string) (bool, error) {
+	p, err := Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return p.Match(name) != p.negate, nil
+}
+
+// String returns the pattern as written.
+func (p *Pattern) String() string { return p.raw }
+
+// Negated reports whether the pattern starts with "!".
+func (p *Pattern) Negated() bool { return p.negate }
+
+// Dir returns the deepest directory every path matching the pattern lives under, e.g.
+// "web/src/**/*.tsx" yields "web/src" and "**/*.go" yields "". Brace alternatives in different
+// directories share their common parent.
+func (p *Pattern) Dir() string {
+	var common []string
+	for i, alt := range p.alts {
+		prefix := literalPrefix(alt)
+		if len(prefix) == len(alt) && len(prefix) > 0 {
+			// A literal pattern names a file (or directory) inside its parent.
+			prefix = prefix[:len(prefix)-1]
+		}
+		if slices.Contains(prefix, "..") {
+			return ""
+		}
+		if i == 0 {
+			common = prefix
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(prefix) && common[n] == prefix[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return unescape(strings.Join(common, "/"))
+}
+
+// Match reports whether the slash-separated path name matches the pattern, ignoring negation.
+func (p *Pattern) Match(name string) bool {
+	name = strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "./")
+	segments := strings.Split(name, "/")
+	for _, alt := range p.alts {
+		if matchSegments(alt, segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitList splits a comma-separated pattern list, keeping commas inside braces, e.g.
+// "**/*.{ts,tsx}, docs/**" yields ["**/*.{ts,tsx}", "docs/**"].
+func SplitList(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	flush := func(end int) {
+		if item := strings.TrimSpace(s[start:end]); item != "" {
+			out = append(out, item)
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				flush(i)
+				start = i + 1
+			}
+		}
+	}
+	flush(len(s))
+	return out
+}
+
+// Set is an ordered list of patterns with gitignore-style semantics: the last pattern that
+// matches a path (or one of its parent directories) decides, and negated patterns re-include.
+type Set struct {
+	patterns []*Pattern
+}
+
+// CompileSet compiles patterns in order, skipping blank lines and # comments. Errors name the
+// offending pattern.
+func CompileSet(patterns []string) (*Set, error) {
+	s := &Set{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		p, err := Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		s.patterns = append(s.patterns, p)
+	}
+	return s, nil
+}
+
+// Len returns the number of patterns in the set.
+func (s *Set) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.patterns)
+}
+
+// Matches reports whether name is selected by the set.
+func (s *Set) Matches(name string) bool {
+	if s.Len() == 0 {
+		return false
+	}
+	name = strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "./")
+	candidates := []string{name}
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		candidates = append(candidates, dir)
+	}
+	matched := false
+	for _, p := range s.patterns {
+		for _, candidate := range candidates {
+			if p.Match(candidate) {
+				matched = !p.negate
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Glob returns the files and directories under root matching pattern, as sorted
+// slash-separated paths relative to root. .git directories are never searched, nor
+// node_modules unless the pattern names it. Negated patterns are rejected.
+func Glob(root, pattern string) ([]string, error) {
+	p, err := Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if p.negate {
+		return nil, fmt.Errorf("%w: negation is only meaningful in a set", ErrBadPattern)
+	}
+	seen := map[string]bool{}
+	for _, alt := range p.alts {
+		prefix := literalPrefix(alt)
+		if len(prefix) == len(alt) {
+			rel := strings.Join(alt, "/")
+			if rel == "" {
+				continue
+			}
+			if _, statErr := os.Stat(filepath.Join(root, filepath.FromSlash(unescape(rel)))); statErr == nil {
+				seen[unescape(rel)] = true
+			}
+			continue
+		}
+		start := filepath.Join(root, filepath.FromSlash(unescape(strings.Join(prefix, "/"))))
+		maxDepth := -1
+		if !containsSegment(alt, "**") {
+			maxDepth = len(alt)
+		}
+		skipNodeModules := !strings.Contains(pattern, "node_modules")
+		walkErr := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == start {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, relErr := filepath.Rel(root, p)
+			if relErr != nil || rel == "." {
+				return nil
+			}
+			rel = filepath.ToSlash(rel)
+			if d.IsDir() {
+				if d.Name() == ".git" || (skipNodeModules && d.Name() == "node_modules") {
+					return filepath.SkipDir
+				}
+				if maxDepth >= 0 && strings.Count(rel, "/")+1 > maxDepth {
+					return filepath.SkipDir
+				}
+			}
+			if matchSegments(alt, strings.Split(rel, "/")) {
+				seen[rel] = true
+			}
+			return nil
+		})
+		if walkErr != nil {
+			return nil, fmt.Errorf("walk %s: %w", start, walkErr)
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for rel := range seen {
+		out = append(out, rel)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 || !matchSegment([]rune(pattern[0]), []rune(name[0])) {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// matchSegment matches one path segment against a validated segment pattern.
+func matchSegment(p, s []rune) bool {
+	px, sx := 0, 0
+	starPx, starSx := -1, -1
+	for px < len(p) || sx < len(s) {
+		if px < len(p) {
+			switch p[px] {
+			case '*':
+				starPx, starSx = px, sx+1
+				px++
+				continue
+			case '?':
+				if sx < len(s) {
+					px++
+					sx++
+					continue
+				}
+			case '[':
+				if sx < len(s) {
+					if ok, end := matchClass(p, px, s[sx]); ok {
+						px = end
+						sx++
+						continue
+					}
+				}
+			case '\\':
+				if sx < len(s) && px+1 < len(p) && p[px+1] == s[sx] {
+					px += 2
+					sx++
+					continue
+				}
+			default:
+				if sx < len(s) && p[px] == s[sx] {
+					px++
+					sx++
+					continue
+				}
+			}
+		}
+		if starPx >= 0 && starSx <= len(s) {
+			px, sx = starPx, starSx
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// matchClass matches r against the class starting at p[start] == '[' and returns the index
+// after the closing bracket.
+func matchClass(p []rune, start int, r rune) (bool, int) {
+	i := start + 1
+	negate := false
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		negate = true
+		i++
+	}
+	matched := false
+	first := true
+	for i < len(p) && (p[i] != ']' || first) {
+		first = false
+		lo := p[i]
+		if lo == '\\' && i+1 < len(p) {
+			i++
+			lo = p[i]
+		}
+		i++
+		hi := lo
+		if i+1 < len(p) && p[i] == '-' && p[i+1] != ']' {
+			hi = p[i+1]
+			if hi == '\\' && i+2 < len(p) {
+				hi = p[i+2]
+				i++
+			}
+			i += 2
+		}
+		if lo <= r && r <= hi {
+			matched = true
+		}
+	}
+	return matched != negate, i + 1
+}
+
+// validateSegment returns a description of what is wrong with segment, or "".
+func validateSegment(segment string) string {
+	p := []rune(segment)
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			if i+1 >= len(p) {
+				return "trailing escape"
+			}
+			i++
+		case '[':
+			j := i + 1
+			if j < len(p) && (p[j] == '!' || p[j] == '^') {
+				j++
+			}
+			first := true
+			for j < len(p) && (p[j] != ']' || first) {
+				first = false
+				if p[j] == '\\' {
+					j++
+				}
+				if j+2 < len(p) && p[j+1] == '-' && p[j+2] != ']' && p[j+2] < p[j] {
+					return "character class range " + string(p[j:j+3]) + " is reversed"
+				}
+				j++
+			}
+			if j >= len(p) {
+				return "unclosed character class"
+			}
+			i = j
+		}
+	}
+	return ""
+}
+
+// expandBraces expands {a,b} alternatives, nested groups included. A non-empty string
+// describes a malformed pattern.
+func expandBraces(pattern string) ([]string, string) {
+	open := -1
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' {
+			i++
+			continue
+		}
+		if pattern[i] == '{' {
+			open = i
+			break
+		}
+		if pattern[i] == '}' {
+			return nil, "unmatched }"
+		}
+	}
+	if open < 0 {
+		return []string{pattern}, ""
+	}
+	depth := 0
+	var parts []string
+	last := open + 1
+	for i := open; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case ',':
+			if depth == 1 {
+				parts = append(parts, pattern[last:i])
+				last = i + 1
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				parts = append(parts, pattern[last:i])
+				var out []string
+				for _, part := range parts {
+					expanded, msg := expandBraces(pattern[:open] + part + pattern[i+1:])
+					if msg != "" {
+						return nil, msg
+					}
+					out = append(out, expanded...)
+				}
+				return out, ""
+			}
+		}
+	}
+	return nil, "unclosed {"
+}
+
+// compactSegments drops empty segments and collapses repeated "**".
+func compactSegments(segments []string) []string {
+	out := segments[:0:0]
+	for _, segment := range segments {
+		if segment == "" || segment == "." {
+			continue
+		}
+		if segment == "**" && len(out) > 0 && out[len(out)-1] == "**" {
+			continue
+		}
+		out = append(out, segment)
+	}
+	return out
+}
+
+func literalPrefix(segments []string) []string {
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			return segments[:i]
+		}
+	}
+	return segments
+}
+
+func containsSegment(segments []string, want string) bool {
+	for _, segment := range segments {
+		if segment == want {
+			return true
+		}
+	}
+	return false
+}
+
+func unescape(s string) string {
+	return strings.ReplaceAll(s, `\`, "")
+}
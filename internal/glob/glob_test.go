@@ -0,0 +1,166 @@
+package glob
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/app/main.go", true},
+		{"src/**", "src/a/b.ts", true},
+		{"src/**", "src", true},
+		{"src/**/test/*.ts", "src/test/a.ts", true},
+		{"src/**/test/*.ts", "src/x/y/test/a.ts", true},
+		{"src/**/test/*.ts", "src/x/y/test/sub/a.ts", false},
+		{"**/*.{ts,tsx}", "web/app.tsx", true},
+		{"**/*.{ts,tsx}", "web/app.js", false},
+		{"{src,lib}/**/*.{js,jsx}", "lib/a/b.jsx", true},
+		{"{a,{b,c}}.md", "c.md", true},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file12.txt", false},
+		{"[a-c]*.md", "beta.md", true},
+		{"[!a-c]*.md", "beta.md", false},
+		{"[^a-c]*.md", "delta.md", true},
+		{`\*.md`, "*.md", true},
+		{`\*.md`, "a.md", false},
+		{"./docs/*.md", "docs/readme.md", true},
+		{"docs/", "docs", true},
+		{"!*.md", "a.md", false},
+		{"!*.md", "a.go", true},
+	}
+	for _, tt := range tests {
+		got, err := Match(tt.pattern, tt.name)
+		if err != nil {
+			t.Errorf("Match(%q, %q) error: %v", tt.pattern, tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, pattern := range []string{"**/*.go", "{a,b}/[x-z]?", `foo\[1\]`, "!vendor/**"} {
+		if err := Validate(pattern); err != nil {
+			t.Errorf("Validate(%q) unexpected error: %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "[abc", "*.{ts,tsx", "a}b", "[z-a]", `trailing\`} {
+		if err := Validate(pattern); !errors.Is(err, ErrBadPattern) {
+			t.Errorf("Validate(%q) = %v, want ErrBadPattern", pattern, err)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" **/*.{ts,tsx}, docs/** ,, {a,b}/*")
+	want := []string{"**/*.{ts,tsx}", "docs/**", "{a,b}/*"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("SplitList = %q, want %q", got, want)
+	}
+}
+
+func TestPatternDir(t *testing.T) {
+	cases := map[string]string{
+		"web/src/**/*.tsx":       "web/src",
+		"**/*.go":                "",
+		"*.md":                   "",
+		"cmd/tool/main.go":       "cmd/tool",
+		"docs/**":                "docs",
+		"{api,api/v2}/**/*.go":   "api",
+		"{web,server}/**":        "",
+		"../outside/**":          "",
+		"./pkg/[a-z]*/README.md": "pkg",
+	}
+	for pattern, want := range cases {
+		p, err := Compile(pattern)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", pattern, err)
+		}
+		if got := p.Dir(); got != want {
+			t.Errorf("Compile(%q).Dir() = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestSetNegationAndParents(t *testing.T) {
+	set, err := CompileSet([]string{"# drafts stay local", "drafts", "templates/**", "!templates/keep.mdc", ""})
+	if err != nil {
+		t.Fatalf("CompileSet failed: %v", err)
+	}
+	if set.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", set.Len())
+	}
+	tests := map[string]bool{
+		"drafts":                true,
+		"drafts/wip.mdc":        true,
+		"templates/base.mdc":    true,
+		"templates/keep.mdc":    false,
+		"frontend/react.mdc":    false,
+		"frontend/drafts/x.mdc": false,
+	}
+	for name, want := range tests {
+		if got := set.Matches(name); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if _, err := CompileSet([]string{"[broken"}); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+	var empty *Set
+	if empty.Matches("anything") {
+		t.Fatal("nil set must not match")
+	}
+}
+
+func TestGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{
+		"go.mod",
+		"web/package.json",
+		"web/src/app.tsx",
+		"web/node_modules/dep/package.json",
+		"api/src/main.ts",
+		".git/config",
+	} {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"go.mod", []string{"go.mod"}},
+		{"missing.txt", []string{}},
+		{"**/package.json", []string{"web/package.json"}},
+		{"**/node_modules/*/package.json", []string{"web/node_modules/dep/package.json"}},
+		{"{web,api}/src/*.{ts,tsx}", []string{"api/src/main.ts", "web/src/app.tsx"}},
+		{"*", []string{"api", "go.mod", "web"}},
+	}
+	for _, tt := range tests {
+		got, err := Glob(root, tt.pattern)
+		if err != nil {
+			t.Fatalf("Glob(%q) failed: %v", tt.pattern, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Glob(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
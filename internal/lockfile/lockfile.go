@@ -0,0 +1,196 @@
+package lockfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FileName is the lockfile name inside the project's .cursor directory.
+	FileName = "cursor-rules.lock"
+	// Version is the current lockfile format version.
+	Version = 1
+)
+
+// File records one installed output file and the hash of its content at install time.
+type File struct {
+	// Path is relative to the project root, using forward slashes.
+	Path string `yaml:"path"`
+	Hash string `yaml:"hash"`
+}
+
+// Entry records one resource installed into a project for a single target.
+type Entry struct {
+	Kind   string `yaml:"kind"`
+	Target string `yaml:"target"`
+	Name   string `yaml:"name"`
+	// Source names the configured package source the resource came from; empty means the
+	// default package dir. Path locates the resource inside it, using forward slashes. Neither
+	// depends on where the source is checked out, so the lockfile can be shared.
+	Source    string   `yaml:"source,omitempty"`
+	Path      string   `yaml:"path,omitempty"`
+	Strategy  string   `yaml:"strategy"`
+	Excludes  []string `yaml:"excludes,omitempty"`
+	NoFlatten bool     `yaml:"noFlatten,omitempty"`
+	// Hash is an aggregate of all file hashes, usable as a quick equality check.
+	Hash  string `yaml:"hash"`
+	Files []File `yaml:"files,omitempty"`
+}
+
+// Lock is the parsed content of .cursor/cursor-rules.lock.
+type Lock struct {
+	Version int     `yaml:"version"`
+	Entries []Entry `yaml:"resources"`
+}
+
+// Path returns the lockfile path for a project root.
+func Path(projectRoot string) string {
+	return filepath.Join(projectRoot, ".cursor", FileName)
+}
+
+// Load reads the project's lockfile. A missing lockfile yields an empty lock.
+func Load(projectRoot string) (*Lock, error) {
+	data, err := os.ReadFile(Path(projectRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Lock{Version: Version}, nil
+		}
+		return nil, err
+	}
+	var l Lock
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, err
+	}
+	if l.Version == 0 {
+		l.Version = Version
+	}
+	return &l, nil
+}
+
+// Save writes the lockfile with entries in a stable order. An empty lock removes the file.
+func Save(projectRoot string, l *Lock) error {
+	path := Path(projectRoot)
+	if l == nil || len(l.Entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	l.Version = Version
+	sort.SliceStable(l.Entries, func(i, j int) bool {
+		if l.Entries[i].Target != l.Entries[j].Target {
+			return l.Entries[i].Target < l.Entries[j].Target
+		}
+		return l.Entries[i].Name < l.Entries[j].Name
+	})
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// #nosec G306 - lockfile is meant to be committed and shared
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Find returns the entry recorded for target and name.
+func (l *Lock) Find(target, name string) (Entry, bool) {
+	if l == nil {
+		return Entry{}, false
+	}
+	for _, e := range l.Entries {
+		if e.Target == target && e.Name == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// ForTarget returns all entries recorded for target.
+func (l *Lock) ForTarget(target string) []Entry {
+	if l == nil {
+		return nil
+	}
+	var out []Entry
+	for _, e := range l.Entries {
+		if e.Target == target {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Upsert adds entry or replaces the existing entry with the same target and name.
+func (l *Lock) Upsert(entry Entry) {
+	for i, e := range l.Entries {
+		if e.Target == entry.Target && e.Name == entry.Name {
+			l.Entries[i] = entry
+			return
+		}
+	}
+	l.Entries = append(l.Entries, entry)
+}
+
+// Remove drops the entry for target and name. An empty name drops every entry for target.
+func (l *Lock) Remove(target, name string) bool {
+	if l == nil {
+		return false
+	}
+	kept := l.Entries[:0]
+	removed := false
+	for _, e := range l.Entries {
+		if e.Target == target && (name == "" || e.Name == name) {
+			removed = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	l.Entries = kept
+	return removed
+}
+
+// ManagesPath reports whether any entry for target recorded rel (or a file below it).
+func (l *Lock) ManagesPath(target, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, e := range l.ForTarget(target) {
+		for _, f := range e.Files {
+			if f.Path == rel || strings.HasPrefix(f.Path, rel+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HashBytes returns the content hash used in lockfiles.
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// HashFiles returns an aggregate hash over the given files, independent of their order.
+func HashFiles(files []File) string {
+	sorted := append([]File(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	var b strings.Builder
+	for _, f := range sorted {
+		b.WriteString(f.Path)
+		b.WriteByte(0)
+		b.WriteString(f.Hash)
+		b.WriteByte('\n')
+	}
+	return HashBytes([]byte(b.String()))
+}
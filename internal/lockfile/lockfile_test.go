@@ -0,0 +1,70 @@
+package lockfile
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	l := &Lock{}
+	l.Upsert(Entry{Kind: "rule", Target: "cursor", Name: "git", Strategy: "copy", Files: []File{{Path: ".cursor/rules/git.mdc", Hash: HashBytes([]byte("x"))}}})
+	l.Upsert(Entry{Kind: "command", Target: "commands", Name: "review", Strategy: "copy"})
+	if err := Save(dir, l); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Version != Version || len(loaded.Entries) != 2 {
+		t.Fatalf("unexpected lock: %+v", loaded)
+	}
+	if loaded.Entries[0].Target != "commands" {
+		t.Errorf("expected entries sorted by target, got %+v", loaded.Entries)
+	}
+	if !loaded.ManagesPath("cursor", ".cursor/rules/git.mdc") {
+		t.Error("expected recorded path to be managed")
+	}
+	if loaded.ManagesPath("cursor", ".cursor/rules/other.mdc") {
+		t.Error("unexpected managed path")
+	}
+}
+
+func TestUpsertReplacesAndRemoveDropsEntries(t *testing.T) {
+	l := &Lock{}
+	l.Upsert(Entry{Target: "cursor", Name: "git", Hash: "a"})
+	l.Upsert(Entry{Target: "cursor", Name: "git", Hash: "b"})
+	if len(l.Entries) != 1 || l.Entries[0].Hash != "b" {
+		t.Fatalf("expected replacement, got %+v", l.Entries)
+	}
+	l.Upsert(Entry{Target: "hooks", Name: "format"})
+	if !l.Remove("hooks", "") {
+		t.Fatal("expected unnamed remove to drop hooks entries")
+	}
+	if _, ok := l.Find("hooks", "format"); ok {
+		t.Fatal("hooks entry still present")
+	}
+}
+
+func TestSaveEmptyRemovesFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := Save(dir, &Lock{Entries: []Entry{{Target: "cursor", Name: "git"}}}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := Save(dir, &Lock{}); err != nil {
+		t.Fatalf("Save empty failed: %v", err)
+	}
+	if _, err := os.Stat(Path(dir)); !os.IsNotExist(err) {
+		t.Fatalf("expected lockfile removed, stat err: %v", err)
+	}
+}
+
+func TestHashFilesOrderIndependent(t *testing.T) {
+	a := []File{{Path: "a", Hash: "1"}, {Path: "b", Hash: "2"}}
+	b := []File{{Path: "b", Hash: "2"}, {Path: "a", Hash: "1"}}
+	if HashFiles(a) != HashFiles(b) {
+		t.Fatal("expected order-independent hash")
+	}
+}
@@ -7,12 +7,23 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// FileName is the manifest file name looked up in a package directory.
+const FileName = "cursor-rules-manifest.yaml"
+
 // Manifest defines the structure of cursor-rules-manifest.yaml files.
 type Manifest struct {
 	Version   string              `yaml:"version"`
 	Targets   []string            `yaml:"targets"`
 	Overrides map[string]Override `yaml:"overrides,omitempty"`
 	Exclude   []string            `yaml:"exclude,omitempty"`
+	// Dependencies lists resources that must be installed alongside the package.
+	Dependencies Dependencies `yaml:"dependencies,omitempty"`
+	// When restricts the projects `install all` and `sync --apply` install the package into.
+	When Conditions `yaml:"when,omitempty"`
+
+	// path and node keep the parsed document so Validate can report precise locations.
+	path string
+	node *yaml.Node
 }
 
 // Override defines target-specific configuration overrides.
@@ -20,12 +31,62 @@ type Override struct {
 	DefaultMode  string   `yaml:"defaultMode,omitempty"`
 	DefaultTools []string `yaml:"defaultTools,omitempty"`
 	IncludeRefs  []string `yaml:"includeRefs,omitempty"`
+	Nested       bool     `yaml:"nested,omitempty"`
+	Mode         string   `yaml:"mode,omitempty"`
+}
+
+// Dependencies declares the packages, commands, skills and agents a package builds on.
+// Names may be qualified with a package source ("org:shared").
+type Dependencies struct {
+	Packages []string `yaml:"packages,omitempty"`
+	Commands []string `yaml:"commands,omitempty"`
+	Skills   []string `yaml:"skills,omitempty"`
+	Agents   []string `yaml:"agents,omitempty"`
+}
+
+// IsZero reports whether no dependencies are declared.
+func (d Dependencies) IsZero() bool {
+	return len(d.Packages) == 0 && len(d.Commands) == 0 && len(d.Skills) == 0 && len(d.Agents) == 0
+}
+
+// Conditions describe the projects a package or preset applies to. Every non-empty list must
+// match; within a list any entry matches.
+type Conditions struct {
+	// ProjectTypes are detected project types such as "go" or "node".
+	ProjectTypes []string `yaml:"projectTypes,omitempty"`
+	// Files are paths or glob patterns relative to the project root; one must exist.
+	Files []string `yaml:"files,omitempty"`
+	// Languages are detected languages such as "go" or "typescript".
+	Languages []string `yaml:"languages,omitempty"`
+	// Frameworks are detected frameworks such as "react" or "gin".
+	Frameworks []string `yaml:"frameworks,omitempty"`
+}
+
+// IsZero reports whether no conditions are declared.
+func (c Conditions) IsZero() bool {
+	return len(c.ProjectTypes) == 0 && len(c.Files) == 0 && len(c.Languages) == 0 && len(c.Frameworks) == 0
+}
+
+// Path returns the manifest path inside pkgPath.
+func Path(pkgPath string) string {
+	return filepath.Join(pkgPath, FileName)
 }
 
 // Load reads and parses a cursor-rules-manifest.yaml file from the given package path.
-// Returns nil if the manifest file doesn't exist (it's optional).
+// Returns nil if the manifest file doesn't exist (it's optional). Syntax errors and schema
+// violations (unknown keys, wrong value types, unsupported versions, bad exclude patterns)
+// are returned as a *ValidationError carrying file:line locations.
 func Load(pkgPath string) (*Manifest, error) {
-	manifestPath := filepath.Join(pkgPath, "cursor-rules-manifest.yaml")
+	return load(pkgPath, nil)
+}
+
+// LoadAndValidate is Load followed by Validate, reporting schema and target issues together.
+func LoadAndValidate(pkgPath string, knownTargets []string) (*Manifest, error) {
+	return load(pkgPath, knownTargets)
+}
+
+func load(pkgPath string, knownTargets []string) (*Manifest, error) {
+	manifestPath := Path(pkgPath)
 
 	data, err := os.ReadFile(manifestPath)
 	if err != nil {
@@ -35,14 +96,48 @@ func Load(pkgPath string) (*Manifest, error) {
 		return nil, err
 	}
 
-	var m Manifest
-	if err := yaml.Unmarshal(data, &m); err != nil {
-		return nil, err
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &ValidationError{Issues: []Issue{syntaxIssue(manifestPath, err)}}
+	}
+	issues := checkSchema(manifestPath, &doc)
+	if knownTargets != nil {
+		issues = sortIssues(append(issues, checkTargets(manifestPath, &doc, knownTargets)...))
+	}
+	if len(issues) > 0 {
+		return nil, &ValidationError{Issues: issues}
+	}
+
+	m := Manifest{path: manifestPath, node: &doc}
+	if len(doc.Content) > 0 {
+		if err := doc.Decode(&m); err != nil {
+			return nil, &ValidationError{Issues: []Issue{syntaxIssue(manifestPath, err)}}
+		}
 	}
 
 	return &m, nil
 }
 
+// SchemaVersion returns the manifest's declared version, or CurrentVersion when unset.
+func (m *Manifest) SchemaVersion() string {
+	if m == nil || m.Version == "" {
+		return CurrentVersion
+	}
+	return m.Version
+}
+
+// Validate checks the manifest's targets and override keys against the known target names.
+// It returns a *ValidationError listing every unknown target with its location.
+func (m *Manifest) Validate(knownTargets []string) error {
+	if m == nil {
+		return nil
+	}
+	if issues := checkTargets(m.path, m.node, knownTargets); len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
+
 // HasTarget checks if the manifest includes a specific target.
 func (m *Manifest) HasTarget(target string) bool {
 	if m == nil {
@@ -0,0 +1,289 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the manifest schema version written by this release.
+const CurrentVersion = "1.0"
+
+// SupportedVersions lists the schema versions this release understands. A manifest without a
+// version is read as CurrentVersion.
+var SupportedVersions = []string{"1", "1.0"}
+
+// Issue is a single validation problem with its location in the manifest file.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as file:line:column: message.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// ValidationError reports every issue found in a manifest.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		lines = append(lines, issue.String())
+	}
+	return "invalid manifest:\n  " + strings.Join(lines, "\n  ")
+}
+
+var overrideKeys = map[string]string{
+	"defaultMode":  "scalar",
+	"defaultTools": "list",
+	"includeRefs":  "list",
+	"nested":       "bool",
+	"mode":         "scalar",
+}
+
+var dependencyKeys = []string{"packages", "commands", "skills", "agents"}
+
+var conditionKeys = []string{"projectTypes", "files", "languages", "frameworks"}
+
+var validModes = []string{"agent", "edit", "chat"}
+
+// checker collects issues while walking a manifest document.
+type checker struct {
+	file   string
+	issues []Issue
+}
+
+func (c *checker) add(n *yaml.Node, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{File: c.file, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) sorted() []Issue {
+	return sortIssues(c.issues)
+}
+
+func sortIssues(issues []Issue) []Issue {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+// checkSchema reports structural problems: unknown keys, wrong value types, unsupported
+// versions, invalid modes and malformed exclude patterns.
+func checkSchema(file string, doc *yaml.Node) []Issue {
+	c := &checker{file: file}
+	root := documentRoot(doc)
+	if root == nil {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		c.add(root, "manifest must be a mapping")
+		return c.sorted()
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "version":
+			if c.scalar(key.Value, value) && !slices.Contains(SupportedVersions, value.Value) {
+				c.add(value, "unsupported manifest version %q (supported: %s)", value.Value, strings.Join(SupportedVersions, ", "))
+			}
+		case "targets":
+			c.stringList(key.Value, value, true)
+		case "exclude":
+			for _, item := range c.stringList(key.Value, value, false) {
+				if err := glob.Validate(item.Value); err != nil {
+					c.add(item, "invalid exclude pattern %q: %v", item.Value, err)
+				}
+			}
+		case "overrides":
+			c.overrides(value)
+		case "dependencies":
+			c.dependencies(value)
+		case "when":
+			c.conditions(value)
+		default:
+			c.add(key, "unknown key %q", key.Value)
+		}
+	}
+	return c.sorted()
+}
+
+func (c *checker) overrides(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		c.add(n, "overrides must be a mapping of target to settings")
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		target, settings := n.Content[i], n.Content[i+1]
+		if settings.Kind != yaml.MappingNode {
+			c.add(settings, "overrides.%s must be a mapping", target.Value)
+			continue
+		}
+		for j := 0; j+1 < len(settings.Content); j += 2 {
+			key, value := settings.Content[j], settings.Content[j+1]
+			field := "overrides." + target.Value + "." + key.Value
+			switch overrideKeys[key.Value] {
+			case "scalar":
+				if c.scalar(field, value) && key.Value == "defaultMode" && !slices.Contains(validModes, value.Value) {
+					c.add(value, "invalid defaultMode %q (must be %s)", value.Value, strings.Join(validModes, ", "))
+				}
+			case "list":
+				c.stringList(field, value, false)
+			case "bool":
+				c.boolean(field, value)
+			default:
+				c.add(key, "unknown override key %q", key.Value)
+			}
+		}
+	}
+}
+
+func (c *checker) dependencies(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		c.add(n, "dependencies must be a mapping of %s", strings.Join(dependencyKeys, ", "))
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if !slices.Contains(dependencyKeys, key.Value) {
+			c.add(key, "unknown dependency kind %q (must be %s)", key.Value, strings.Join(dependencyKeys, ", "))
+			continue
+		}
+		for _, item := range c.stringList("dependencies."+key.Value, value, true) {
+			if strings.TrimSpace(item.Value) == "" {
+				c.add(item, "dependencies.%s entries must not be empty", key.Value)
+			}
+		}
+	}
+}
+
+func (c *checker) conditions(n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		c.add(n, "when must be a mapping of %s", strings.Join(conditionKeys, ", "))
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if !slices.Contains(conditionKeys, key.Value) {
+			c.add(key, "unknown condition %q (must be %s)", key.Value, strings.Join(conditionKeys, ", "))
+			continue
+		}
+		items := c.stringList("when."+key.Value, value, true)
+		if key.Value != "files" {
+			continue
+		}
+		for _, item := range items {
+			if err := glob.Validate(item.Value); err != nil {
+				c.add(item, "invalid file pattern %q: %v", item.Value, err)
+			}
+		}
+	}
+}
+
+func (c *checker) scalar(field string, n *yaml.Node) bool {
+	if n.Kind != yaml.ScalarNode {
+		c.add(n, "%s must be a string", field)
+		return false
+	}
+	return true
+}
+
+func (c *checker) boolean(field string, n *yaml.Node) {
+	if n.Kind != yaml.ScalarNode || n.ShortTag() != "!!bool" {
+		c.add(n, "%s must be true or false", field)
+	}
+}
+
+// stringList checks that n is a list of strings and returns its items.
+func (c *checker) stringList(field string, n *yaml.Node, unique bool) []*yaml.Node {
+	if n.Kind != yaml.SequenceNode {
+		c.add(n, "%s must be a list", field)
+		return nil
+	}
+	seen := make(map[string]bool, len(n.Content))
+	items := make([]*yaml.Node, 0, len(n.Content))
+	for _, item := range n.Content {
+		if item.Kind != yaml.ScalarNode {
+			c.add(item, "%s entries must be strings", field)
+			continue
+		}
+		if unique && seen[item.Value] {
+			c.add(item, "duplicate %s entry %q", field, item.Value)
+			continue
+		}
+		seen[item.Value] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+// checkTargets reports targets and override keys that are not in known.
+func checkTargets(file string, doc *yaml.Node, known []string) []Issue {
+	c := &checker{file: file}
+	root := documentRoot(doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	hint := strings.Join(known, ", ")
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "targets":
+			if value.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, item := range value.Content {
+				if item.Kind == yaml.ScalarNode && !slices.Contains(known, item.Value) {
+					c.add(item, "unknown target %q (known: %s)", item.Value, hint)
+				}
+			}
+		case "overrides":
+			if value.Kind != yaml.MappingNode {
+				continue
+			}
+			for j := 0; j < len(value.Content); j += 2 {
+				if target := value.Content[j]; !slices.Contains(known, target.Value) {
+					c.add(target, "overrides for unknown target %q (known: %s)", target.Value, hint)
+				}
+			}
+		}
+	}
+	return c.sorted()
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc == nil || doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// syntaxIssue converts a YAML parse error into an issue, keeping the line number when yaml
+// reports one.
+func syntaxIssue(file string, err error) Issue {
+	issue := Issue{File: file, Line: 1, Column: 1, Message: err.Error()}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		if line, convErr := strconv.Atoi(m[1]); convErr == nil {
+			issue.Line = line
+			issue.Message = m[2]
+		}
+	}
+	return issue
+}
@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return dir
+}
+
+func TestLoadReportsSchemaIssuesWithLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		message string
+	}{
+		{
+			name:    "unknown top-level key",
+			content: "version: \"1.0\"\ntarget:\n  - cursor\n",
+			line:    2,
+			message: `unknown key "target"`,
+		},
+		{
+			name:    "unsupported version",
+			content: "version: \"2.0\"\n",
+			line:    1,
+			message: `unsupported manifest version "2.0"`,
+		},
+		{
+			name:    "targets not a list",
+			content: "version: \"1.0\"\ntargets: cursor\n",
+			line:    2,
+			message: "targets must be a list",
+		},
+		{
+			name:    "duplicate target",
+			content: "targets:\n  - cursor\n  - cursor\n",
+			line:    3,
+			message: `duplicate targets entry "cursor"`,
+		},
+		{
+			name:    "bad exclude pattern",
+			content: "exclude:\n  - \"[abc\"\n",
+			line:    2,
+			message: `invalid exclude pattern "[abc"`,
+		},
+		{
+			name:    "unknown override key",
+			content: "overrides:\n  copilot-prompt:\n    defaultModes: agent\n",
+			line:    3,
+			message: `unknown override key "defaultModes"`,
+		},
+		{
+			name:    "invalid mode",
+			content: "overrides:\n  copilot-prompt:\n    defaultMode: auto\n",
+			line:    3,
+			message: `invalid defaultMode "auto"`,
+		},
+		{
+			name:    "nested not a bool",
+			content: "overrides:\n  agents-md:\n    nested: \"yes\"\n",
+			line:    3,
+			message: "overrides.agents-md.nested must be true or false",
+		},
+		{
+			name:    "mode not a scalar",
+			content: "overrides:\n  roo-rules:\n    mode: [code]\n",
+			line:    3,
+			message: "overrides.roo-rules.mode must be a string",
+		},
+		{
+			name:    "unknown dependency kind",
+			content: "dependencies:\n  packages: [shared]\n  rules: [git]\n",
+			line:    3,
+			message: `unknown dependency kind "rules"`,
+		},
+		{
+			name:    "unknown condition",
+			content: "when:\n  projectType: [go]\n",
+			line:    2,
+			message: `unknown condition "projectType"`,
+		},
+		{
+			name:    "syntax error",
+			content: "targets: [cursor\n",
+			line:    1,
+			message: "did not find expected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeManifest(t, tt.content)
+			_, err := Load(dir)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+			if len(verr.Issues) != 1 {
+				t.Fatalf("expected one issue, got %+v", verr.Issues)
+			}
+			issue := verr.Issues[0]
+			if issue.Line != tt.line || !strings.Contains(issue.Message, tt.message) {
+				t.Fatalf("issue = %s, want line %d containing %q", issue, tt.line, tt.message)
+			}
+			if want := filepath.Join(dir, FileName) + ":"; !strings.HasPrefix(issue.String(), want) {
+				t.Fatalf("issue %q should start with %q", issue, want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsUnknownTargets(t *testing.T) {
+	dir := writeManifest(t, `version: "1"
+targets:
+  - cursor
+  - copilot-instructions
+overrides:
+  copilot-promt:
+    defaultMode: agent
+`)
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := m.SchemaVersion(); got != "1" {
+		t.Fatalf("SchemaVersion = %q, want 1", got)
+	}
+
+	err = m.Validate([]string{"cursor", "copilot-instr", "copilot-prompt"})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if len(verr.Issues) != 2 {
+		t.Fatalf("expected two issues, got %+v", verr.Issues)
+	}
+	if verr.Issues[0].Line != 4 || !strings.Contains(verr.Issues[0].Message, `unknown target "copilot-instructions"`) {
+		t.Errorf("unexpected first issue: %s", verr.Issues[0])
+	}
+	if verr.Issues[1].Line != 6 || !strings.Contains(verr.Issues[1].Message, `unknown target "copilot-promt"`) {
+		t.Errorf("unexpected second issue: %s", verr.Issues[1])
+	}
+
+	if err := m.Validate([]string{"cursor", "copilot-instructions", "copilot-promt"}); err != nil {
+		t.Fatalf("expected valid manifest, got %v", err)
+	}
+}
+
+func TestLoadDependencies(t *testing.T) {
+	m, err := Load(writeManifest(t, `dependencies:
+  packages: [shared, org:git]
+  commands: [review]
+  skills: [deploy]
+  agents: [reviewer]
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	deps := m.Dependencies
+	if deps.IsZero() || len(deps.Packages) != 2 || deps.Packages[1] != "org:git" || deps.Commands[0] != "review" || deps.Skills[0] != "deploy" || deps.Agents[0] != "reviewer" {
+		t.Fatalf("unexpected dependencies: %+v", deps)
+	}
+}
+
+func TestSchemaVersionDefaultsToCurrent(t *testing.T) {
+	m, err := Load(writeManifest(t, "targets: [cursor]\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if m.Version != "" || m.SchemaVersion() != CurrentVersion {
+		t.Fatalf("Version = %q, SchemaVersion = %q", m.Version, m.SchemaVersion())
+	}
+}
@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the default policy file name looked up in the package directory.
+const FileName = "policy.yaml"
+
+// Policy defines the structure of policy.yaml files evaluated by `cursor-rules policy`.
+type Policy struct {
+	Version string `yaml:"version"`
+	// Targets limits evaluation to the given rule targets. Empty means all rule targets.
+	Targets []string `yaml:"targets,omitempty"`
+	// RequiredPresets must be installed in at least one evaluated target.
+	RequiredPresets []string `yaml:"requiredPresets,omitempty"`
+	// ForbiddenPresets must not be installed in any evaluated target.
+	ForbiddenPresets []string `yaml:"forbiddenPresets,omitempty"`
+	// RequiredFrontmatter lists frontmatter keys every installed rule must define.
+	RequiredFrontmatter []string `yaml:"requiredFrontmatter,omitempty"`
+	// MaxBodyBytes caps the rule body size per target. The "default" key applies
+	// to targets without an explicit entry.
+	MaxBodyBytes map[string]int `yaml:"maxBodyBytes,omitempty"`
+}
+
+// Load reads and parses a policy file from path.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DefaultPath returns the policy file path inside packageDir.
+func DefaultPath(packageDir string) string {
+	return filepath.Join(packageDir, FileName)
+}
+
+// AppliesToTarget reports whether the policy evaluates the given target.
+func (p *Policy) AppliesToTarget(target string) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.Targets) == 0 {
+		return true
+	}
+	for _, t := range p.Targets {
+		if strings.TrimSpace(t) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxBodyBytesFor returns the body size limit for target, or 0 when unlimited.
+func (p *Policy) MaxBodyBytesFor(target string) int {
+	if p == nil || p.MaxBodyBytes == nil {
+		return 0
+	}
+	if limit, ok := p.MaxBodyBytes[target]; ok {
+		return limit
+	}
+	return p.MaxBodyBytes["default"]
+}
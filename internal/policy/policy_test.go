@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	content := `version: "1"
+targets: [cursor]
+requiredPresets: [git]
+forbiddenPresets: [legacy]
+requiredFrontmatter: [description]
+maxBodyBytes:
+  default: 100
+  cursor: 50
+`
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+
+	p, err := Load(DefaultPath(dir))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(p.RequiredPresets) != 1 || p.RequiredPresets[0] != "git" {
+		t.Errorf("unexpected required presets: %v", p.RequiredPresets)
+	}
+	if !p.AppliesToTarget("cursor") || p.AppliesToTarget("copilot-instr") {
+		t.Errorf("unexpected target filtering for %v", p.Targets)
+	}
+	if got := p.MaxBodyBytesFor("cursor"); got != 50 {
+		t.Errorf("MaxBodyBytesFor(cursor) = %d, want 50", got)
+	}
+	if got := p.MaxBodyBytesFor("opencode-rules"); got != 100 {
+		t.Errorf("MaxBodyBytesFor(opencode-rules) = %d, want 100", got)
+	}
+}
+
+func TestLoadPolicyInvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte("requiredPresets: [unclosed"), 0o644); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected parse error, got nil")
+	}
+}
+
+func TestNilPolicyHelpers(t *testing.T) {
+	var p *Policy
+	if p.AppliesToTarget("cursor") {
+		t.Error("nil policy should not apply to any target")
+	}
+	if p.MaxBodyBytesFor("cursor") != 0 {
+		t.Error("nil policy should have no body limit")
+	}
+}
@@ -73,6 +73,11 @@ Apply to TypeScript and TSX files.
 `
 }
 
+// Rule returns a rule file with the given frontmatter (without delimiters) and body
+func Rule(frontmatter, body string) string {
+	return "---\n" + frontmatter + "\n---\n" + body
+}
+
 // PresetWithoutFrontmatter returns content without frontmatter
 func PresetWithoutFrontmatter() string {
 	return `This is just plain text without frontmatter.
@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// RunGit runs git in dir and returns trimmed stdout, failing the test on error.
+func RunGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "init.defaultBranch=main"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// InitRemoteRepo creates a bare repository and a working clone pushing to it, for tests that
+// need a git remote without network access. It skips the test when git is not installed.
+func InitRemoteRepo(t *testing.T) (bareDir, workDir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	bareDir = filepath.Join(root, "remote.git")
+	workDir = filepath.Join(root, "work")
+	RunGit(t, root, "init", "--quiet", "--bare", bareDir)
+	RunGit(t, root, "init", "--quiet", workDir)
+	RunGit(t, workDir, "remote", "add", "origin", bareDir)
+	return bareDir, workDir
+}
+
+// CommitAndPush writes files into workDir, commits them and pushes to origin's main branch.
+// It returns the new commit id.
+func CommitAndPush(t *testing.T, workDir, message string, files map[string]string) string {
+	t.Helper()
+	for name, content := range files {
+		CreateTestFile(t, workDir, name, content)
+	}
+	RunGit(t, workDir, "add", "-A")
+	RunGit(t, workDir, "commit", "--quiet", "-m", message)
+	RunGit(t, workDir, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+	return RunGit(t, workDir, "rev-parse", "HEAD")
+}
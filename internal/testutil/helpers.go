@@ -29,6 +29,51 @@ func CreateTestManifest(t *testing.T, dir, content string) string {
 	return CreateTestFile(t, dir, "cursor-rules-manifest.yaml", content)
 }
 
+// SetupPackageProject creates a package dir holding files (keyed by slash path relative to it)
+// and an empty project dir, and points CURSOR_RULES_PACKAGE_DIR at the package dir and
+// CURSOR_RULES_CONFIG_DIR at a fresh config dir.
+func SetupPackageProject(t *testing.T, files map[string]string) (packageDir, projectDir string) {
+	t.Helper()
+	packageDir = CreateTestDir(t, files)
+	projectDir = t.TempDir()
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", packageDir)
+	t.Setenv("CURSOR_RULES_CONFIG_DIR", t.TempDir())
+	return packageDir, projectDir
+}
+
+// TestSource is a package source for SetupSourcesProject: its name and the files of its dir
+type TestSource struct {
+	Name  string
+	Files map[string]string
+}
+
+// SetupSourcesProject creates a dir per source and an empty project dir, and writes a config.yaml
+// listing the sources in precedence order. CURSOR_RULES_PACKAGE_DIR is cleared so the sources
+// are used.
+func SetupSourcesProject(t *testing.T, sources []TestSource) (sourceDirs []string, projectDir string) {
+	t.Helper()
+	_, projectDir = SetupPackageProject(t, nil)
+	t.Setenv("CURSOR_RULES_PACKAGE_DIR", "")
+	cfg := "sources:\n"
+	for _, src := range sources {
+		dir := CreateTestDir(t, src.Files)
+		sourceDirs = append(sourceDirs, dir)
+		cfg += "  - name: " + src.Name + "\n    path: " + dir + "\n"
+	}
+	CreateTestConfig(t, cfg)
+	return sourceDirs, projectDir
+}
+
+// CreateTestConfig writes config.yaml into the dir CURSOR_RULES_CONFIG_DIR points at
+func CreateTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := os.Getenv("CURSOR_RULES_CONFIG_DIR")
+	if dir == "" {
+		t.Fatal("CURSOR_RULES_CONFIG_DIR is not set")
+	}
+	return CreateTestFile(t, dir, "config.yaml", content)
+}
+
 // CreateTestPreset creates a test preset file
 func CreateTestPreset(t *testing.T, dir, name, content string) string {
 	t.Helper()
@@ -155,7 +200,7 @@ func CreateTestDir(t *testing.T, structure map[string]string) string {
 	tmpDir := t.TempDir()
 
 	for path, content := range structure {
-		fullPath := filepath.Join(tmpDir, path)
+		fullPath := filepath.Join(tmpDir, filepath.FromSlash(path))
 		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
 			t.Fatalf("Failed to create directory for %s: %v", path, err)
 		}
@@ -0,0 +1,201 @@
+package tokens
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed vocab.txt
+var vocabTable string
+
+// maxWhitespaceRun is how many whitespace characters one token covers; BPE vocabularies hold
+// merged runs of spaces and newlines up to about this length.
+const maxWhitespaceRun = 16
+
+// BPEEstimator approximates byte-pair-encoding tokenizers such as cl100k without network
+// access or the multi-megabyte merge tables. Text is pre-split the way those tokenizers do
+// (letters with an optional leading space, digit groups of up to three, punctuation runs,
+// whitespace runs); each piece is then covered greedily by the longest entries of an embedded
+// table of common words, subwords and symbols. Characters the table does not cover cost
+// what a byte-level BPE typically pays for them.
+type BPEEstimator struct {
+	vocab  map[string]struct{}
+	maxLen int
+}
+
+// NewBPEEstimator returns the estimator backed by the embedded table.
+func NewBPEEstimator() *BPEEstimator {
+	return NewBPEEstimatorFromTable(vocabTable)
+}
+
+// NewBPEEstimatorFromTable builds an estimator from a table with one entry per line. Blank
+// lines and comment lines starting with "# " are ignored.
+func NewBPEEstimatorFromTable(table string) *BPEEstimator {
+	e := &BPEEstimator{vocab: map[string]struct{}{}}
+	for _, line := range strings.Split(table, "\n") {
+		entry := strings.TrimRight(line, "\r")
+		if strings.TrimSpace(entry) == "" || strings.HasPrefix(entry, "# ") {
+			continue
+		}
+		e.vocab[entry] = struct{}{}
+		if len(entry) > e.maxLen {
+			e.maxLen = len(entry)
+		}
+	}
+	return e
+}
+
+// Name returns "bpe".
+func (e *BPEEstimator) Name() string { return "bpe" }
+
+// Count returns the estimated token count of text.
+func (e *BPEEstimator) Count(text string) int {
+	total := 0
+	for len(text) > 0 {
+		piece, kind := nextPiece(text)
+		text = text[len(piece):]
+		switch kind {
+		case pieceSpace:
+			total += (utf8.RuneCountInString(piece) + maxWhitespaceRun - 1) / maxWhitespaceRun
+		case pieceDigits:
+			total++
+		default:
+			total += e.countPiece(piece)
+		}
+	}
+	return total
+}
+
+type pieceKind int
+
+const (
+	pieceWord pieceKind = iota
+	pieceDigits
+	pieceSymbol
+	pieceSpace
+)
+
+// nextPiece returns the first pre-tokenization piece of text.
+func nextPiece(text string) (string, pieceKind) {
+	first, size := utf8.DecodeRuneInString(text)
+	// A single space joins the word or symbol run that follows it.
+	if first == ' ' && len(text) > 1 {
+		next, _ := utf8.DecodeRuneInString(text[1:])
+		if !unicode.IsSpace(next) && !unicode.IsDigit(next) {
+			rest, kind := nextPiece(text[1:])
+			return text[:1+len(rest)], kind
+		}
+	}
+	switch {
+	case unicode.IsSpace(first):
+		return text[:runLength(text, unicode.IsSpace, -1)], pieceSpace
+	case unicode.IsDigit(first):
+		return text[:runLength(text, unicode.IsDigit, 3)], pieceDigits
+	case unicode.IsLetter(first) || first == '\'':
+		n := size + runLength(text[size:], unicode.IsLetter, -1)
+		return text[:n], pieceWord
+	default:
+		return text[:runLength(text, isSymbol, -1)], pieceSymbol
+	}
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// runLength returns the byte length of the leading run of runes satisfying ok, capped at
+// limit runes when limit > 0.
+func runLength(text string, ok func(rune) bool, limit int) int {
+	n, count := 0, 0
+	for n < len(text) && (limit < 0 || count < limit) {
+		r, size := utf8.DecodeRuneInString(text[n:])
+		if !ok(r) {
+			break
+		}
+		n += size
+		count++
+	}
+	return n
+}
+
+// countPiece covers piece with the longest table entries, trying the lower-cased form too. A
+// leading space merges into the first token, as in BPE vocabularies.
+func (e *BPEEstimator) countPiece(piece string) int {
+	piece = strings.TrimPrefix(piece, " ")
+	if e.has(piece) {
+		return 1
+	}
+	covered := e.cover(piece)
+	// Frequent ASCII words missing from the table are still merged into few tokens; about four
+	// letters per token bounds the greedy cover, which over-counts them.
+	if isASCII(piece) {
+		if bound := (len(piece) + 3) / 4; bound < covered {
+			return bound
+		}
+	}
+	return covered
+}
+
+// cover counts the table entries and fallback tokens needed to cover piece from left to right.
+func (e *BPEEstimator) cover(piece string) int {
+	total := 0
+	for len(piece) > 0 {
+		n := e.longestEntry(piece)
+		if n > 0 {
+			total++
+			piece = piece[n:]
+			continue
+		}
+		// Uncovered: ASCII runs merge into roughly three-byte tokens, other characters cost
+		// about one token per two UTF-8 bytes (CJK and emoji land on one to two tokens each).
+		r, size := utf8.DecodeRuneInString(piece)
+		if r < utf8.RuneSelf {
+			run := 1
+			for run < len(piece) && piece[run] < utf8.RuneSelf && e.longestEntry(piece[run:]) == 0 {
+				run++
+			}
+			total += (run + 2) / 3
+			piece = piece[run:]
+			continue
+		}
+		total += (size + 1) / 2
+		piece = piece[size:]
+	}
+	return total
+}
+
+func (e *BPEEstimator) has(s string) bool {
+	if _, ok := e.vocab[s]; ok {
+		return true
+	}
+	_, ok := e.vocab[strings.ToLower(s)]
+	return ok
+}
+
+// longestEntry returns the byte length of the longest table entry prefixing s, or 0.
+func (e *BPEEstimator) longestEntry(s string) int {
+	n := len(s)
+	if n > e.maxLen {
+		n = e.maxLen
+	}
+	for ; n > 0; n-- {
+		if n < len(s) && !utf8.RuneStart(s[n]) {
+			continue
+		}
+		if e.has(s[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+// Package tokens estimates how many model tokens text costs and truncates markdown to a token
+// budget without splitting code blocks or UTF-8 sequences.
+package tokens
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// Estimator counts the tokens a text costs in a model's context.
+type Estimator interface {
+	// Name identifies the estimator, e.g. for --tokenizer flags.
+	Name() string
+	// Count returns the estimated token count of text.
+	Count(text string) int
+}
+
+// DefaultName is the estimator used when none is selected.
+const DefaultName = "bpe"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Estimator{}
+)
+
+func init() {
+	Register(NewBPEEstimator())
+	Register(CharEstimator{})
+}
+
+// Register makes an estimator available under its name, replacing any previous one.
+func Register(e Estimator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[e.Name()] = e
+}
+
+// Lookup returns the estimator registered under name; an empty name selects DefaultName.
+func Lookup(name string) (Estimator, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultName
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := registry[name]
+	if !ok {
+		return nil, errors.Newf(errors.CodeInvalidArgument, "unknown tokenizer: %s (available: %s)", name, strings.Join(namesLocked(), ", "))
+	}
+	return e, nil
+}
+
+// Names lists the registered estimators in alphabetical order.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the default estimator.
+func Default() Estimator {
+	e, err := Lookup(DefaultName)
+	if err != nil {
+		return CharEstimator{}
+	}
+	return e
+}
+
+// Count estimates the tokens of text with the default estimator.
+func Count(text string) int {
+	return Default().Count(text)
+}
+
+// CharEstimator is the classic heuristic of about four characters per token.
+type CharEstimator struct{}
+
+// Name returns "chars".
+func (CharEstimator) Name() string { return "chars" }
+
+// Count returns the rune count divided by four, rounded up.
+func (CharEstimator) Count(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Truncate shortens markdown text to at most max tokens as counted by e. It cuts between
+// blocks (headings, paragraphs, fenced code blocks), never inside a fenced block, and drops
+// headings left without content. Only a paragraph that opens the text may be cut mid-way, at
+// a line, word or rune boundary. max <= 0 means no limit. The second result reports whether
+// anything was removed.
+func Truncate(text string, max int, e Estimator) (string, bool) {
+	if max <= 0 || e.Count(text) <= max {
+		return text, false
+	}
+	var kept []block
+	used := 0
+	for _, b := range splitBlocks(text) {
+		cost := e.Count(b.text)
+		if used+cost <= max {
+			kept = append(kept, b)
+			used += cost
+			continue
+		}
+		if len(kept) == 0 && b.kind == blockParagraph {
+			if partial := truncateParagraph(b.text, max, e); partial != "" {
+				kept = append(kept, block{kind: blockParagraph, text: partial})
+			}
+		}
+		break
+	}
+	for len(kept) > 0 && kept[len(kept)-1].kind == blockHeading {
+		kept = kept[:len(kept)-1]
+	}
+	var out strings.Builder
+	for _, b := range kept {
+		out.WriteString(b.text)
+	}
+	return strings.TrimRight(out.String(), " \t\n"), true
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockFence
+)
+
+// block is a run of lines, including the blank lines that follow it, so joining blocks
+// reproduces the input.
+type block struct {
+	kind blockKind
+	text string
+}
+
+func splitBlocks(text string) []block {
+	lines := strings.SplitAfter(text, "\n")
+	var blocks []block
+	for i := 0; i < len(lines); {
+		line := strings.TrimSpace(lines[i])
+		start := i
+		kind := blockParagraph
+		switch {
+		case line == "":
+			// Leading blank lines stick to the previous block.
+			i++
+			if len(blocks) > 0 {
+				blocks[len(blocks)-1].text += lines[start]
+				continue
+			}
+		case isFence(line):
+			kind = blockFence
+			i = fenceEnd(lines, i)
+		case strings.HasPrefix(line, "#"):
+			kind = blockHeading
+			i++
+		default:
+			for i++; i < len(lines); i++ {
+				next := strings.TrimSpace(lines[i])
+				if next == "" || isFence(next) || strings.HasPrefix(next, "#") {
+					break
+				}
+			}
+		}
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+		blocks = append(blocks, block{kind: kind, text: strings.Join(lines[start:i], "")})
+	}
+	return blocks
+}
+
+// FencedLines reports, for each line of text split on "\n", whether it belongs to a fenced code
+// block (``` or ~~~), fence lines included.
+func FencedLines(text string) []bool {
+	lines := strings.SplitAfter(text, "\n")
+	fenced := make([]bool, len(lines))
+	for i := 0; i < len(lines); {
+		if !isFence(strings.TrimSpace(lines[i])) {
+			i++
+			continue
+		}
+		for end := fenceEnd(lines, i); i < end; i++ {
+			fenced[i] = true
+		}
+	}
+	return fenced
+}
+
+func isFence(line string) bool {
+	return strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~")
+}
+
+// fenceEnd returns the index after the line closing the fence opened at lines[start]. An
+// unclosed fence runs to the end of the text.
+func fenceEnd(lines []string, start int) int {
+	open := strings.TrimSpace(lines[start])
+	marker := open[:3]
+	width := len(open) - len(strings.TrimLeft(open, marker[:1]))
+	for i := start + 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, marker) && strings.Trim(line, marker[:1]) == "" && len(line) >= width {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// truncateParagraph keeps the longest prefix of whole lines, then words, then runes that fits.
+func truncateParagraph(text string, max int, e Estimator) string {
+	lines := strings.SplitAfter(text, "\n")
+	if n := longestPrefix(len(lines), max, e, func(n int) string { return strings.Join(lines[:n], "") }); n > 0 {
+		return strings.Join(lines[:n], "")
+	}
+	words := strings.SplitAfter(lines[0], " ")
+	if n := longestPrefix(len(words), max, e, func(n int) string { return strings.Join(words[:n], "") }); n > 0 {
+		return strings.Join(words[:n], "")
+	}
+	runes := []rune(words[0])
+	n := longestPrefix(len(runes), max, e, func(n int) string { return string(runes[:n]) })
+	return string(runes[:n])
+}
+
+// longestPrefix binary-searches the largest n in [0, total] whose prefix(n) fits in max tokens.
+func longestPrefix(total, max int, e Estimator, prefix func(int) string) int {
+	lo, hi := 0, total
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if e.Count(prefix(mid)) <= max {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo
+}
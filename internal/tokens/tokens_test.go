@@ -0,0 +1,99 @@
+package tokens
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBPEEstimatorCount(t *testing.T) {
+	e := NewBPEEstimator()
+	tests := []struct {
+		text     string
+		min, max int
+	}{
+		{"", 0, 0},
+		{"Use the default config file.", 6, 7},
+		{"Always handle errors before you return.", 6, 9},
+		{"func main() {\n\tfmt.Println(\"hi\")\n}", 10, 18},
+		{"12345678", 3, 3},
+		{"こんにちは世界", 7, 14},
+		{"    \n\n    ", 1, 1},
+		{"xyzzyplugh", 2, 5},
+	}
+	for _, tt := range tests {
+		if got := e.Count(tt.text); got < tt.min || got > tt.max {
+			t.Errorf("Count(%q) = %d, want %d..%d", tt.text, got, tt.min, tt.max)
+		}
+	}
+}
+
+func TestBPEEstimatorTable(t *testing.T) {
+	e := NewBPEEstimatorFromTable("# words\nhello\nworld\n#\n")
+	if got := e.Count("Hello world"); got != 2 {
+		t.Fatalf("Count = %d, want 2", got)
+	}
+	if got := e.Count("# x"); got != 2 {
+		t.Fatalf("Count(%q) = %d, want 2", "# x", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	e, err := Lookup("")
+	if err != nil || e.Name() != DefaultName {
+		t.Fatalf("Lookup(\"\") = %v, %v", e, err)
+	}
+	if e, err := Lookup("chars"); err != nil || e.Count("abcdefgh") != 2 {
+		t.Fatalf("Lookup(chars) = %v, %v", e, err)
+	}
+	if _, err := Lookup("gpt-17"); err == nil || !strings.Contains(err.Error(), "available: bpe, chars") {
+		t.Fatalf("expected unknown tokenizer error, got %v", err)
+	}
+}
+
+func TestTruncateKeepsFencedBlocksWhole(t *testing.T) {
+	text := "# Go\n\nUse gofmt.\n\n```go\nfunc main() {\n\tprintln(\"a long line of code here\")\n}\n```\n\n## Tests\n\nTable tests."
+	e := CharEstimator{}
+	got, truncated := Truncate(text, 12, e)
+	if !truncated {
+		t.Fatal("expected truncation")
+	}
+	if got != "# Go\n\nUse gofmt." {
+		t.Fatalf("Truncate = %q", got)
+	}
+	if strings.Count(got, "```")%2 != 0 {
+		t.Fatalf("fence split: %q", got)
+	}
+
+	got, _ = Truncate(text, e.Count(text)-3, e)
+	if strings.HasSuffix(got, "## Tests") || !strings.HasSuffix(got, "```") {
+		t.Fatalf("expected trailing heading dropped after the code block, got %q", got)
+	}
+
+	if got, truncated := Truncate(text, 0, e); truncated || got != text {
+		t.Fatal("max 0 must not truncate")
+	}
+}
+
+func TestTruncateParagraphAtRuneBoundary(t *testing.T) {
+	text := strings.Repeat("é", 100)
+	got, truncated := Truncate(text, 10, CharEstimator{})
+	if !truncated || !utf8.ValidString(got) || utf8.RuneCountInString(got) != 40 {
+		t.Fatalf("Truncate = %q (%d runes)", got, utf8.RuneCountInString(got))
+	}
+
+	words := "alpha beta gamma delta epsilon zeta eta theta"
+	got, _ = Truncate(words, 5, CharEstimator{})
+	if got != "alpha beta gamma" {
+		t.Fatalf("expected a cut between words, got %q", got)
+	}
+}
+
+func TestFencedLines(t *testing.T) {
+	text := "intro\n```go\n@file a.md\n```\nafter\n~~~~\n```\n~~~~\n````\nopen"
+	want := []bool{false, true, true, true, false, true, true, true, true, true}
+	if got := FencedLines(text); !slices.Equal(got, want) {
+		t.Fatalf("FencedLines = %v, want %v", got, want)
+	}
+}
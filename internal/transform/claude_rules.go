@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ClaudeRulesTransformer transforms Cursor rules into Claude Code memory rules
+// (`.claude/rules/*.md` or `~/.claude/rules`), which Claude Code loads alongside CLAUDE.md.
+type ClaudeRulesTransformer struct {
+	// IncludeRefs are appended to the body as @path imports.
+	IncludeRefs []string
+}
+
+// NewClaudeRulesTransformer creates a transformer for Claude Code rule files.
+func NewClaudeRulesTransformer() *ClaudeRulesTransformer {
+	return &ClaudeRulesTransformer{}
+}
+
+// Transform scopes the rule with `paths` (see pathsRuleTransform) and imports IncludeRefs.
+func (t *ClaudeRulesTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
+	return pathsRuleTransform(node, body, t.IncludeRefs, func(ref string) string { return "@" + ref })
+}
+
+// WithOverrides returns a copy that imports IncludeRefs.
+func (t *ClaudeRulesTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
+}
+
+// Validate checks that the transformed rule only carries well-formed paths.
+func (t *ClaudeRulesTransformer) Validate(node *yaml.Node) error {
+	return validatePathsRule(node, "Claude Code")
+}
+
+// Target returns the identifier for Claude Code rules format.
+func (t *ClaudeRulesTransformer) Target() string {
+	return "claude-rules"
+}
+
+// Extension returns the file extension for Claude Code rule files.
+func (t *ClaudeRulesTransformer) Extension() string {
+	return ".md"
+}
+
+// OutputDir returns the project-local output directory for Claude Code rules.
+func (t *ClaudeRulesTransformer) OutputDir() string {
+	return ".claude/rules"
+}
+
+// pathsRuleTransform maps globs (or apply_to/applyTo) to the `paths` field that Claude Code and
+// Cline use to scope a rule to matching files. Rules without globs, including alwaysApply rules,
+// are always loaded; other Cursor fields have no equivalent in either tool and are dropped. refs
+// are appended to the body, each formatted by fmtRef.
+func pathsRuleTransform(node *yaml.Node, body string, refs []string, fmtRef func(ref string) string) (*yaml.Node, string, error) {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "decode frontmatter")
+	}
+
+	result := make(map[string]interface{})
+	alwaysApply, _ := fm["alwaysApply"].(bool)
+	if !alwaysApply {
+		for _, key := range []string{"globs", "apply_to", "applyTo"} {
+			if paths := globPatterns(fm[key]); len(paths) > 0 {
+				result["paths"] = paths
+				break
+			}
+		}
+	}
+
+	out := &yaml.Node{}
+	if err := out.Encode(result); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "encode frontmatter")
+	}
+	return out, appendReferences(body, refs, fmtRef), nil
+}
+
+// validatePathsRule checks that a rule written by pathsRuleTransform only carries well-formed
+// paths; toolName names the target in the error.
+func validatePathsRule(node *yaml.Node, toolName string) error {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return err
+	}
+	for key := range fm {
+		if key != "paths" {
+			return errors.Newf(errors.CodeInvalidArgument, "unsupported %s rule field: %s", toolName, key)
+		}
+	}
+	return validateGlobField("paths", fm["paths"])
+}
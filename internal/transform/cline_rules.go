@@ -0,0 +1,52 @@
+package transform
+
+import "gopkg.in/yaml.v3"
+
+// ClineRulesTransformer transforms Cursor rules into Cline workspace rules (`.clinerules/*.md`).
+type ClineRulesTransformer struct {
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
+}
+
+// NewClineRulesTransformer creates a transformer for Cline rule files.
+func NewClineRulesTransformer() *ClineRulesTransformer {
+	return &ClineRulesTransformer{}
+}
+
+// Transform scopes the rule with `paths` (see pathsRuleTransform) and links IncludeRefs.
+// Rules without paths are written as plain markdown.
+func (t *ClineRulesTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
+	return pathsRuleTransform(node, body, t.IncludeRefs, markdownReference)
+}
+
+// Render omits the frontmatter block of rules without paths.
+func (t *ClineRulesTransformer) Render(node *yaml.Node, body string) ([]byte, error) {
+	return marshalOptionalFrontmatter(node, body)
+}
+
+// WithOverrides returns a copy that references IncludeRefs.
+func (t *ClineRulesTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
+}
+
+// Validate checks that the transformed rule only carries well-formed paths.
+func (t *ClineRulesTransformer) Validate(node *yaml.Node) error {
+	return validatePathsRule(node, "Cline")
+}
+
+// Target returns the identifier for Cline rules format.
+func (t *ClineRulesTransformer) Target() string {
+	return "cline-rules"
+}
+
+// Extension returns the file extension for Cline rule files.
+func (t *ClineRulesTransformer) Extension() string {
+	return ".md"
+}
+
+// OutputDir returns the project-local output directory for Cline rules.
+func (t *ClineRulesTransformer) OutputDir() string {
+	return ".clinerules"
+}
@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ComposedMDTransformer composes Cursor rules into a plain markdown instructions file such as
+// AGENTS.md or GEMINI.md. Each installed preset owns a managed section of the file.
+type ComposedMDTransformer struct {
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
+	// Nested composes rules whose globs share a directory into that directory's file instead
+	// of the root one.
+	Nested bool
+
+	target string
+	file   string
+}
+
+// NewAgentsMDTransformer creates a transformer for AGENTS.md, the instructions file read by
+// Codex, Jules, Amp and other agents.
+func NewAgentsMDTransformer() *ComposedMDTransformer {
+	return &ComposedMDTransformer{target: "agents-md", file: "AGENTS.md"}
+}
+
+// NewGeminiMDTransformer creates a transformer for GEMINI.md, the context file Gemini CLI loads
+// from the project root and the directories below it.
+func NewGeminiMDTransformer() *ComposedMDTransformer {
+	return &ComposedMDTransformer{target: "gemini-md", file: "GEMINI.md"}
+}
+
+// Transform keeps the markdown body; the composed file has no frontmatter, so every field is
+// dropped.
+func (t *ComposedMDTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "decode frontmatter")
+	}
+	out := &yaml.Node{}
+	if err := out.Encode(map[string]interface{}{}); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "encode frontmatter")
+	}
+	return out, appendReferences(body, t.IncludeRefs, markdownReference), nil
+}
+
+// WithOverrides returns a copy that references IncludeRefs and nests rules when asked to.
+func (t *ComposedMDTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	out.Nested = o.Nested
+	return &out, nil
+}
+
+// Validate checks that the transformed rule carries no frontmatter.
+func (t *ComposedMDTransformer) Validate(node *yaml.Node) error {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return err
+	}
+	for key := range fm {
+		return errors.Newf(errors.CodeInvalidArgument, "unsupported %s field: %s", t.file, key)
+	}
+	return nil
+}
+
+// Target returns the identifier of the composed format, e.g. agents-md.
+func (t *ComposedMDTransformer) Target() string {
+	return t.target
+}
+
+// Extension returns the file extension of the composed file.
+func (t *ComposedMDTransformer) Extension() string {
+	return ".md"
+}
+
+// OutputDir returns the project root, where the top-level composed file lives.
+func (t *ComposedMDTransformer) OutputDir() string {
+	return "."
+}
+
+// ComposedFile returns the name of the composed file, e.g. AGENTS.md.
+func (t *ComposedMDTransformer) ComposedFile() string {
+	return t.file
+}
+
+// ComposeDir returns the directory shared by the rule's globs when Nested is set, so agents
+// pick the rule up from the closest composed file; otherwise, and for rules that always apply,
+// the project root.
+func (t *ComposedMDTransformer) ComposeDir(node *yaml.Node) (string, error) {
+	if !t.Nested {
+		return "", nil
+	}
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return "", errors.Wrapf(err, errors.CodeInternal, "decode frontmatter")
+	}
+	return globsDir(fm), nil
+}
@@ -2,18 +2,24 @@ package transform
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
 	"gopkg.in/yaml.v3"
 )
 
 // CopilotInstructionsTransformer transforms Cursor rules to Copilot instructions format.
 type CopilotInstructionsTransformer struct {
-	DefaultGlobs  []string
-	MaxTokens     int
+	DefaultGlobs []string
+	// MaxTokens caps the body size; zero or less disables truncation.
+	MaxTokens int
+	// Tokenizer counts tokens for MaxTokens; nil uses the default tokenizer.
+	Tokenizer     tokens.Estimator
 	ValidateGlobs bool
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
 }
 
 // NewCopilotInstructionsTransformer creates a new transformer with default settings.
@@ -60,8 +66,8 @@ func (t *CopilotInstructionsTransformer) Transform(node *yaml.Node, body string)
 		}
 	}
 
-	// 4. Truncate body if exceeds token limit
-	body = t.truncateBody(body)
+	// 4. Truncate body if exceeds token limit, keeping references intact
+	body = appendReferences(t.truncateBody(body), t.IncludeRefs, markdownReference)
 
 	// 5. Encode back to YAML node
 	out := &yaml.Node{}
@@ -72,6 +78,13 @@ func (t *CopilotInstructionsTransformer) Transform(node *yaml.Node, body string)
 	return out, body, nil
 }
 
+// WithOverrides returns a copy that references IncludeRefs.
+func (t *CopilotInstructionsTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
+}
+
 // extractApplyTo extracts and normalizes the applyTo field from Cursor frontmatter.
 func (t *CopilotInstructionsTransformer) extractApplyTo(fm map[string]interface{}) string {
 	// Check apply_to (Cursor format)
@@ -96,28 +109,28 @@ func (t *CopilotInstructionsTransformer) extractApplyTo(fm map[string]interface{
 	return ""
 }
 
-// validateGlobPattern validates glob patterns for Copilot compatibility.
+// validateGlobPattern validates a comma-separated applyTo value; commas inside braces do not
+// split patterns.
 func (t *CopilotInstructionsTransformer) validateGlobPattern(pattern string) error {
-	// Split comma-separated patterns
-	patterns := strings.Split(pattern, ",")
-	for _, p := range patterns {
-		p = strings.TrimSpace(p)
-		// Test with filepath.Match (basic validation)
-		if _, err := filepath.Match(p, "test.ts"); err != nil {
+	for _, p := range glob.SplitList(pattern) {
+		if err := glob.Validate(p); err != nil {
 			return errors.Wrapf(err, errors.CodeInvalidArgument, "invalid pattern %q", p)
 		}
 	}
 	return nil
 }
 
-// truncateBody truncates the body if it exceeds the token limit.
+// truncateBody truncates the body to MaxTokens between markdown blocks.
 func (t *CopilotInstructionsTransformer) truncateBody(body string) string {
-	// Rough token estimation: ~4 chars per token
-	maxChars := t.MaxTokens * 4
-	if len(body) <= maxChars {
+	tokenizer := t.Tokenizer
+	if tokenizer == nil {
+		tokenizer = tokens.Default()
+	}
+	truncated, cut := tokens.Truncate(body, t.MaxTokens, tokenizer)
+	if !cut {
 		return body
 	}
-	return body[:maxChars] + "\n\n[... truncated for token limit ...]"
+	return truncated + "\n\n[... truncated for token limit ...]"
 }
 
 // Validate checks that required fields are present in transformed frontmatter.
@@ -135,7 +148,7 @@ func (t *CopilotInstructionsTransformer) Validate(node *yaml.Node) error {
 		return errors.New(errors.CodeInvalidArgument, "missing required field: applyTo")
 	}
 
-	return nil
+	return validateGlobField("applyTo", fm["applyTo"])
 }
 
 // Target returns the identifier for Copilot instructions format.
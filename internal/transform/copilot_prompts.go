@@ -1,6 +1,8 @@
 package transform
 
 import (
+	"strings"
+
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
 	"gopkg.in/yaml.v3"
 )
@@ -21,6 +23,25 @@ func NewCopilotPromptsTransformer() *CopilotPromptsTransformer {
 	}
 }
 
+// WithOverrides returns a copy using the manifest's default mode, tools and references.
+func (t *CopilotPromptsTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	mode := strings.TrimSpace(o.DefaultMode)
+	if err := validatePromptMode(mode); err != nil {
+		return nil, err
+	}
+	instructions := *t.CopilotInstructionsTransformer
+	instructions.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	out := *t
+	out.CopilotInstructionsTransformer = &instructions
+	if mode != "" {
+		out.DefaultMode = mode
+	}
+	if len(o.DefaultTools) > 0 {
+		out.DefaultTools = append([]string(nil), o.DefaultTools...)
+	}
+	return &out, nil
+}
+
 // Transform converts Cursor frontmatter to Copilot prompts format.
 func (t *CopilotPromptsTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
 	// Start with instructions transform
@@ -72,8 +93,7 @@ func (t *CopilotPromptsTransformer) Validate(node *yaml.Node) error {
 
 	// Validate mode enum
 	if mode, ok := fm["mode"].(string); ok {
-		validModes := map[string]bool{"agent": true, "edit": true, "chat": true}
-		if !validModes[mode] {
+		if !validPromptModes[mode] {
 			return errors.Newf(errors.CodeInvalidArgument, "invalid mode: %s (must be agent, edit, or chat)", mode)
 		}
 	}
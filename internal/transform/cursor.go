@@ -5,16 +5,32 @@ import (
 )
 
 // CursorTransformer is an identity transformer that passes through Cursor rules unchanged.
-type CursorTransformer struct{}
+// IncludeRefs, when set, are appended to the body as @file references.
+type CursorTransformer struct {
+	IncludeRefs []string
+}
 
 // NewCursorTransformer creates a new CursorTransformer instance.
 func NewCursorTransformer() *CursorTransformer {
 	return &CursorTransformer{}
 }
 
-// Transform passes through frontmatter and body unchanged.
+// cursorToolFields are frontmatter fields only cursor-rules reads: `when` applicability
+// conditions, the `tags`/`keywords` used for recommendations and the Roo Code mode a rule is
+// routed to. Cursor ignores them.
+var cursorToolFields = []string{"when", "tags", "keywords", RooModeField}
+
+// Transform passes through the rule, minus the fields only cursor-rules reads.
 func (t *CursorTransformer) Transform(frontmatter *yaml.Node, body string) (*yaml.Node, string, error) {
-	return frontmatter, body, nil
+	dropFields(frontmatter, cursorToolFields...)
+	return frontmatter, appendReferences(body, t.IncludeRefs, func(ref string) string { return "@file " + ref }), nil
+}
+
+// WithOverrides returns a copy that references IncludeRefs.
+func (t *CursorTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
 }
 
 // Validate performs basic validation on Cursor frontmatter.
@@ -23,8 +39,11 @@ func (t *CursorTransformer) Validate(frontmatter *yaml.Node) error {
 	if err := frontmatter.Decode(&fm); err != nil {
 		return err
 	}
-	// Cursor rules are flexible; no strict validation required
-	return nil
+	// Cursor rules are flexible; only glob fields must be well-formed
+	if err := validateGlobField("globs", fm["globs"]); err != nil {
+		return err
+	}
+	return validateGlobField("apply_to", fm["apply_to"])
 }
 
 // Target returns the identifier for Cursor format.
@@ -9,7 +9,10 @@ import (
 
 // OpenCodeRulesTransformer transforms Cursor rules into markdown files suitable
 // for the opencode-rules plugin (`.opencode/rules` or `~/.config/opencode/rules`).
-type OpenCodeRulesTransformer struct{}
+type OpenCodeRulesTransformer struct {
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
+}
 
 // NewOpenCodeRulesTransformer creates a transformer for OpenCode rule files.
 func NewOpenCodeRulesTransformer() *OpenCodeRulesTransformer {
@@ -56,7 +59,14 @@ func (t *OpenCodeRulesTransformer) Transform(node *yaml.Node, body string) (*yam
 		return nil, "", errors.Wrapf(err, errors.CodeInternal, "encode frontmatter")
 	}
 
-	return out, body, nil
+	return out, appendReferences(body, t.IncludeRefs, markdownReference), nil
+}
+
+// WithOverrides returns a copy that references IncludeRefs.
+func (t *OpenCodeRulesTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
 }
 
 // Validate checks that the transformed rule contains only supported metadata.
@@ -72,7 +82,7 @@ func (t *OpenCodeRulesTransformer) Validate(node *yaml.Node) error {
 			return errors.Newf(errors.CodeInvalidArgument, "invalid match: %s (must be any or all)", match)
 		}
 	}
-	return nil
+	return validateGlobField("globs", fm["globs"])
 }
 
 // Target returns the identifier for OpenCode rules format.
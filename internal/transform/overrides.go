@@ -0,0 +1,77 @@
+package transform
+
+import (
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+)
+
+// Overrides holds per-target transformer settings declared by a package manifest.
+// Transformers apply the settings they support and ignore the rest.
+type Overrides struct {
+	// DefaultMode is the prompt mode used when a rule does not set one (agent, edit or chat).
+	DefaultMode string
+	// DefaultTools are the prompt tools used when a rule does not list any.
+	DefaultTools []string
+	// IncludeRefs are files every transformed rule references, in the target's reference syntax.
+	IncludeRefs []string
+	// Nested composes rules scoped to one directory into a file in that directory.
+	Nested bool
+	// Mode is the agent mode rules are scoped to when they do not name one (a Roo Code mode slug).
+	Mode string
+}
+
+// IsZero reports whether o configures nothing.
+func (o Overrides) IsZero() bool {
+	return strings.TrimSpace(o.DefaultMode) == "" && len(o.DefaultTools) == 0 && len(o.IncludeRefs) == 0 && !o.Nested &&
+		strings.TrimSpace(o.Mode) == ""
+}
+
+// Configurable is implemented by transformers that accept manifest overrides.
+type Configurable interface {
+	// WithOverrides returns a configured copy of the transformer; the receiver is left unchanged.
+	WithOverrides(o Overrides) (Transformer, error)
+}
+
+// ApplyOverrides returns t configured with o, or t itself when o is empty or t is not configurable.
+func ApplyOverrides(t Transformer, o Overrides) (Transformer, error) {
+	if o.IsZero() {
+		return t, nil
+	}
+	c, ok := t.(Configurable)
+	if !ok {
+		return t, nil
+	}
+	return c.WithOverrides(o)
+}
+
+var validPromptModes = map[string]bool{"agent": true, "edit": true, "chat": true}
+
+// appendReferences appends one formatted line per ref to body, separated by a blank line.
+func appendReferences(body string, refs []string, format func(ref string) string) string {
+	var lines []string
+	for _, ref := range refs {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			lines = append(lines, format(ref))
+		}
+	}
+	if len(lines) == 0 {
+		return body
+	}
+	refsBlock := strings.Join(lines, "\n")
+	if strings.TrimSpace(body) == "" {
+		return refsBlock
+	}
+	return strings.TrimRight(body, "\n") + "\n\n" + refsBlock
+}
+
+func markdownReference(ref string) string {
+	return "- [" + ref + "](" + ref + ")"
+}
+
+func validatePromptMode(mode string) error {
+	if mode != "" && !validPromptModes[mode] {
+		return errors.Newf(errors.CodeInvalidArgument, "invalid defaultMode: %s (must be agent, edit, or chat)", mode)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// RooModeField is the rule frontmatter field naming the Roo Code mode a rule is scoped to.
+const RooModeField = "rooMode"
+
+var rooModeSlug = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+// RooRulesTransformer transforms Cursor rules into Roo Code rules (`.roo/rules/*.md`), or into
+// the mode-specific `.roo/rules-<mode>/` directories Roo Code only loads in that mode.
+type RooRulesTransformer struct {
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
+	// Mode is the mode slug used when a rule does not set rooMode; empty applies rules to
+	// every mode.
+	Mode string
+}
+
+// NewRooRulesTransformer creates a transformer for Roo Code rule files.
+func NewRooRulesTransformer() *RooRulesTransformer {
+	return &RooRulesTransformer{}
+}
+
+// Transform drops the frontmatter: Roo Code loads every rule in its directory as plain markdown,
+// so a rule is scoped only by the mode directory it lands in (see RuleDir).
+func (t *RooRulesTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
+	out := &yaml.Node{}
+	if err := out.Encode(map[string]interface{}{}); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "encode frontmatter")
+	}
+	return out, appendReferences(body, t.IncludeRefs, markdownReference), nil
+}
+
+// Render writes the body alone.
+func (t *RooRulesTransformer) Render(node *yaml.Node, body string) ([]byte, error) {
+	return marshalOptionalFrontmatter(node, body)
+}
+
+// RuleDir returns "rules-<mode>" for the rule's rooMode, or Mode when it sets none, and "rules"
+// for rules that apply to every mode.
+func (t *RooRulesTransformer) RuleDir(node *yaml.Node) (string, error) {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return "", errors.Wrapf(err, errors.CodeInternal, "decode frontmatter")
+	}
+	mode := strings.TrimSpace(toString(fm[RooModeField]))
+	if mode == "" {
+		mode = t.Mode
+	}
+	if mode == "" {
+		return "rules", nil
+	}
+	if err := validateRooMode(mode); err != nil {
+		return "", err
+	}
+	return "rules-" + mode, nil
+}
+
+// WithOverrides returns a copy that references IncludeRefs and defaults rules to Mode.
+func (t *RooRulesTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	mode := strings.TrimSpace(o.Mode)
+	if mode != "" {
+		if err := validateRooMode(mode); err != nil {
+			return nil, err
+		}
+	}
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	out.Mode = mode
+	return &out, nil
+}
+
+// Validate checks that the transformed rule carries no frontmatter.
+func (t *RooRulesTransformer) Validate(node *yaml.Node) error {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return err
+	}
+	for key := range fm {
+		return errors.Newf(errors.CodeInvalidArgument, "unsupported Roo Code rule field: %s", key)
+	}
+	return nil
+}
+
+// Target returns the identifier for Roo Code rules format.
+func (t *RooRulesTransformer) Target() string {
+	return "roo-rules"
+}
+
+// Extension returns the file extension for Roo Code rule files.
+func (t *RooRulesTransformer) Extension() string {
+	return ".md"
+}
+
+// OutputDir returns the project-local directory for rules that apply to every Roo Code mode;
+// mode-specific rules go to its rules-<mode> siblings.
+func (t *RooRulesTransformer) OutputDir() string {
+	return ".roo/rules"
+}
+
+func validateRooMode(mode string) error {
+	if !rooModeSlug.MatchString(mode) {
+		return errors.Newf(errors.CodeInvalidArgument, "invalid Roo Code mode: %q (use the mode slug, e.g. code or architect)", mode)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package transform
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateField is the rule frontmatter field that opts a rule into placeholder rendering.
+const TemplateField = "template"
+
+// TemplateProject describes the project a rule is rendered for.
+type TemplateProject struct {
+	Name       string
+	Type       string
+	Root       string
+	Languages  []string
+	Frameworks []string
+}
+
+// TemplateData holds the values placeholders such as {{ .Project.Name }} or
+// {{ .Vars.goVersion }} are replaced with.
+type TemplateData struct {
+	Project TemplateProject
+	Vars    map[string]string
+}
+
+// Template configures placeholder rendering for rules that set `template: true`.
+type Template struct {
+	Data TemplateData
+	// Strict fails on placeholders that cannot be rendered. Otherwise they are kept as written
+	// and reported by UnrenderedPlaceholders.
+	Strict bool
+}
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{([^{}\n]*)\}\}`)
+	projectPlaceholder = regexp.MustCompile(`^\.Project\.(Name|Type|Root|Languages|Frameworks)$`)
+	varsPlaceholder    = regexp.MustCompile(`^\.Vars\.([A-Za-z_][A-Za-z0-9_-]*)$`)
+)
+
+// WithTemplate returns t wrapped so that the frontmatter values and body of rules that set
+// `template: true` are rendered with tmpl before t transforms them. The template field itself
+// is dropped from every rule. A nil tmpl returns t unchanged.
+func WithTemplate(t Transformer, tmpl *Template) Transformer {
+	if tmpl == nil {
+		return t
+	}
+	return &templateTransformer{Transformer: t, tmpl: *tmpl}
+}
+
+type templateTransformer struct {
+	Transformer
+	tmpl Template
+}
+
+// Transform renders placeholders of opted-in rules, then delegates to the wrapped transformer.
+func (t *templateTransformer) Transform(frontmatter *yaml.Node, body string) (*yaml.Node, string, error) {
+	enabled, err := takeTemplateField(frontmatter)
+	if err != nil {
+		return nil, "", err
+	}
+	if !enabled {
+		return t.Transformer.Transform(frontmatter, body)
+	}
+	if err := t.renderNode(frontmatter); err != nil {
+		return nil, "", err
+	}
+	rendered, err := t.tmpl.Render("body", body)
+	if err != nil {
+		return nil, "", err
+	}
+	return t.Transformer.Transform(frontmatter, rendered)
+}
+
+func (t *templateTransformer) renderNode(n *yaml.Node) error {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yaml.ScalarNode {
+		rendered, err := t.tmpl.Render("frontmatter", n.Value)
+		if err != nil {
+			return err
+		}
+		n.Value = rendered
+		return nil
+	}
+	for _, child := range n.Content {
+		if err := t.renderNode(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnrenderedPlaceholders returns the placeholders of a source rule that t would keep as
+// written, or nil when t does not render templates or the rule does not set `template: true`.
+func UnrenderedPlaceholders(t Transformer, frontmatter *yaml.Node, body string) []string {
+	wrapped, ok := t.(*templateTransformer)
+	if !ok {
+		return nil
+	}
+	if enabled, err := templateFieldValue(frontmatter); err != nil || !enabled {
+		return nil
+	}
+	var texts []string
+	collectScalars(mappingNode(frontmatter), &texts)
+	texts = append(texts, body)
+	var out []string
+	for _, text := range texts {
+		_, unrendered := wrapped.tmpl.expand(text)
+		out = append(out, unrendered...)
+	}
+	return out
+}
+
+// Render replaces the known placeholders in text: {{ .Project.Name }}, {{ .Project.Type }},
+// {{ .Project.Root }}, {{ .Project.Languages }}, {{ .Project.Frameworks }} (comma-separated)
+// and {{ .Vars.<key> }} for defined keys. Fenced code blocks and inline code are left alone,
+// so rules can document other template syntaxes. Any other placeholder is kept as written,
+// or fails the render when Strict is set.
+func (tmpl Template) Render(name, text string) (string, error) {
+	out, unrendered := tmpl.expand(text)
+	if len(unrendered) > 0 && tmpl.Strict {
+		return "", errors.Newf(errors.CodeInvalidArgument, "render %s: unrendered template placeholders: %s", name, strings.Join(unrendered, ", "))
+	}
+	return out, nil
+}
+
+// expand renders text outside code and returns the placeholders it could not render.
+func (tmpl Template) expand(text string) (string, []string) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	var out strings.Builder
+	var unrendered []string
+	for _, segment := range splitCode(text) {
+		if segment.code {
+			out.WriteString(segment.text)
+			continue
+		}
+		out.WriteString(placeholderPattern.ReplaceAllStringFunc(segment.text, func(placeholder string) string {
+			if value, ok := tmpl.lookup(strings.TrimSpace(placeholder[2 : len(placeholder)-2])); ok {
+				return value
+			}
+			unrendered = append(unrendered, placeholder)
+			return placeholder
+		}))
+	}
+	return out.String(), unrendered
+}
+
+func (tmpl Template) lookup(expr string) (string, bool) {
+	if m := projectPlaceholder.FindStringSubmatch(expr); m != nil {
+		project := tmpl.Data.Project
+		switch m[1] {
+		case "Name":
+			return project.Name, true
+		case "Type":
+			return project.Type, true
+		case "Root":
+			return project.Root, true
+		case "Languages":
+			return strings.Join(project.Languages, ", "), true
+		case "Frameworks":
+			return strings.Join(project.Frameworks, ", "), true
+		}
+	}
+	if m := varsPlaceholder.FindStringSubmatch(expr); m != nil {
+		value, ok := tmpl.Data.Vars[m[1]]
+		return value, ok
+	}
+	return "", false
+}
+
+type textSegment struct {
+	text string
+	code bool
+}
+
+// splitCode splits markdown into fenced code blocks, inline code spans and the text between them.
+func splitCode(text string) []textSegment {
+	var segments []textSegment
+	var prose strings.Builder
+	flush := func() {
+		if prose.Len() > 0 {
+			segments = append(segments, splitInlineCode(prose.String())...)
+			prose.Reset()
+		}
+	}
+	lines := strings.SplitAfter(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		fence := fenceMarker(lines[i])
+		if fence == "" {
+			prose.WriteString(lines[i])
+			continue
+		}
+		flush()
+		block := lines[i]
+		for i+1 < len(lines) {
+			i++
+			block += lines[i]
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) && strings.Trim(strings.TrimSpace(lines[i]), fence[:1]) == "" {
+				break
+			}
+		}
+		segments = append(segments, textSegment{text: block, code: true})
+	}
+	flush()
+	return segments
+}
+
+// fenceMarker returns the ``` or ~~~ run opening a fenced code block on line, or "".
+func fenceMarker(line string) string {
+	trimmed := strings.TrimSpace(line)
+	for _, c := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// splitInlineCode splits text into inline code spans, delimited by equal backtick runs, and
+// the text around them. An unmatched run of backticks is plain text.
+func splitInlineCode(text string) []textSegment {
+	var segments []textSegment
+	start := 0
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		closing := indexBacktickRun(text[i+run:], run)
+		if closing < 0 {
+			i += run
+			continue
+		}
+		if start < i {
+			segments = append(segments, textSegment{text: text[start:i]})
+		}
+		end := i + run + closing + run
+		segments = append(segments, textSegment{text: text[i:end], code: true})
+		i, start = end, end
+	}
+	if start < len(text) {
+		segments = append(segments, textSegment{text: text[start:]})
+	}
+	return segments
+}
+
+// indexBacktickRun returns the index of the first run of exactly n backticks in text, or -1.
+func indexBacktickRun(text string, n int) int {
+	for i := 0; i < len(text); {
+		if text[i] != '`' {
+			i++
+			continue
+		}
+		run := len(text[i:]) - len(strings.TrimLeft(text[i:], "`"))
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// takeTemplateField removes the template field from a rule's frontmatter and returns its value.
+func takeTemplateField(frontmatter *yaml.Node) (bool, error) {
+	enabled, err := templateFieldValue(frontmatter)
+	if err != nil {
+		return false, err
+	}
+	dropFields(frontmatter, TemplateField)
+	return enabled, nil
+}
+
+func templateFieldValue(frontmatter *yaml.Node) (bool, error) {
+	m := mappingNode(frontmatter)
+	if m == nil {
+		return false, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != TemplateField {
+			continue
+		}
+		var enabled bool
+		if err := m.Content[i+1].Decode(&enabled); err != nil {
+			return false, errors.Newf(errors.CodeInvalidArgument, "%s must be true or false", TemplateField)
+		}
+		return enabled, nil
+	}
+	return false, nil
+}
+
+func collectScalars(n *yaml.Node, out *[]string) {
+	if n == nil {
+		return
+	}
+	if n.Kind == yaml.ScalarNode {
+		*out = append(*out, n.Value)
+		return
+	}
+	for _, child := range n.Content {
+		collectScalars(child, out)
+	}
+}
@@ -1,9 +1,12 @@
 package transform
 
 import (
+	"slices"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
 	"gopkg.in/yaml.v3"
 )
 
@@ -49,6 +52,58 @@ Use strict mode.`
 	}
 }
 
+func TestCursorTransformerDropsToolFields(t *testing.T) {
+	fm, body, err := SplitFrontmatter([]byte("---\ndescription: \"Go\"\nwhen:\n  languages: [go]\ntags: [go]\nkeywords: [golang]\nrooMode: code\nglobs: [\"**/*.go\"]\n---\nUse gofmt."))
+	if err != nil {
+		t.Fatalf("SplitFrontmatter failed: %v", err)
+	}
+	outFM, outBody, err := NewCursorTransformer().Transform(fm, body)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	out, err := MarshalMarkdown(outFM, outBody)
+	if err != nil {
+		t.Fatalf("MarshalMarkdown failed: %v", err)
+	}
+	want := "---\ndescription: \"Go\"\nglobs: [\"**/*.go\"]\n---\n"
+	if !strings.HasPrefix(string(out), want) {
+		t.Fatalf("expected tool-only fields to be dropped, got:\n%s", out)
+	}
+}
+
+func TestRuleTransformersDropRooMode(t *testing.T) {
+	transformers := []Transformer{
+		NewCursorTransformer(),
+		NewClaudeRulesTransformer(),
+		NewClineRulesTransformer(),
+		NewWindsurfRulesTransformer(),
+		NewCopilotInstructionsTransformer(),
+		NewCopilotPromptsTransformer(),
+		NewOpenCodeRulesTransformer(),
+		NewAgentsMDTransformer(),
+		NewRooRulesTransformer(),
+	}
+	for _, tr := range transformers {
+		t.Run(tr.Target(), func(t *testing.T) {
+			fm, body, err := SplitFrontmatter([]byte("---\ndescription: Go\nrooMode: code\nglobs: [\"**/*.go\"]\n---\nUse gofmt."))
+			if err != nil {
+				t.Fatalf("SplitFrontmatter failed: %v", err)
+			}
+			outFM, outBody, err := tr.Transform(fm, body)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+			out, err := RenderRule(tr, outFM, outBody)
+			if err != nil {
+				t.Fatalf("RenderRule failed: %v", err)
+			}
+			if strings.Contains(string(out), RooModeField) {
+				t.Fatalf("expected %s to be dropped, got:\n%s", RooModeField, out)
+			}
+		})
+	}
+}
+
 func TestCopilotInstructionsTransformer(t *testing.T) {
 	transformer := NewCopilotInstructionsTransformer()
 
@@ -264,6 +319,23 @@ func TestTruncateBody(t *testing.T) {
 	}
 }
 
+func TestTruncateBodyRespectsMarkdown(t *testing.T) {
+	transformer := NewCopilotInstructionsTransformer()
+	transformer.Tokenizer = tokens.CharEstimator{}
+	transformer.MaxTokens = 20
+
+	body := "# Errors\n\nWrap errors with context.\n\n```go\nreturn fmt.Errorf(\"load config: %w\", err)\n```\n\n## Naming — ünïcödé\n\nShort names."
+	out := transformer.truncateBody(body)
+	if !strings.HasPrefix(out, "# Errors\n\nWrap errors with context.\n\n[... truncated") {
+		t.Fatalf("expected the cut before the code block, got %q", out)
+	}
+
+	transformer.MaxTokens = 0
+	if out := transformer.truncateBody(body); out != body {
+		t.Fatalf("MaxTokens 0 must disable truncation, got %q", out)
+	}
+}
+
 func TestValidateGlobPattern(t *testing.T) {
 	transformer := NewCopilotInstructionsTransformer()
 
@@ -275,7 +347,9 @@ func TestValidateGlobPattern(t *testing.T) {
 		{"**/*.{ts,tsx}", false},
 		{"src/**/*.js", false},
 		{"**", false},
+		{"**/*.{ts,tsx}, docs/**", false},
 		{"[invalid", true}, // Invalid glob
+		{"src/*.{ts,tsx", true},
 	}
 
 	for _, tt := range tests {
@@ -288,6 +362,35 @@ func TestValidateGlobPattern(t *testing.T) {
 	}
 }
 
+func TestValidateRejectsMalformedGlobs(t *testing.T) {
+	tests := []struct {
+		transformer Transformer
+		frontmatter string
+	}{
+		{NewCursorTransformer(), "globs: \"src/[a-z\""},
+		{NewCursorTransformer(), "apply_to: [\"**/*.{ts,tsx\"]"},
+		{NewCopilotInstructionsTransformer(), "description: x\napplyTo: \"**/*.ts, lib/{a,b\""},
+		{NewOpenCodeRulesTransformer(), "globs: [\"**/*.go\", \"cmd/[\"]"},
+	}
+	for _, tt := range tests {
+		var node yaml.Node
+		if err := yaml.Unmarshal([]byte(tt.frontmatter), &node); err != nil {
+			t.Fatalf("unmarshal %q: %v", tt.frontmatter, err)
+		}
+		if err := tt.transformer.Validate(&node); err == nil {
+			t.Errorf("%s: expected Validate to reject %q", tt.transformer.Target(), tt.frontmatter)
+		}
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte("globs: \"**/*.{ts,tsx}, !**/*.test.ts\""), &node); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCursorTransformer().Validate(&node); err != nil {
+		t.Errorf("expected valid globs to pass: %v", err)
+	}
+}
+
 func TestSplitFrontmatter(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -518,6 +621,27 @@ func TestTransformerMetadata(t *testing.T) {
 			wantExt: ".mdc",
 			wantDir: ".opencode/rules",
 		},
+		{
+			name:    "cline-rules",
+			trans:   NewClineRulesTransformer(),
+			wantTgt: "cline-rules",
+			wantExt: ".md",
+			wantDir: ".clinerules",
+		},
+		{
+			name:    "roo-rules",
+			trans:   NewRooRulesTransformer(),
+			wantTgt: "roo-rules",
+			wantExt: ".md",
+			wantDir: ".roo/rules",
+		},
+		{
+			name:    "gemini-md",
+			trans:   NewGeminiMDTransformer(),
+			wantTgt: "gemini-md",
+			wantExt: ".md",
+			wantDir: ".",
+		},
 	}
 
 	for _, tt := range tests {
@@ -585,3 +709,327 @@ Use table-driven tests.`
 		t.Fatal("description field should be removed")
 	}
 }
+
+func TestClaudeRulesTransformer(t *testing.T) {
+	transformer := NewClaudeRulesTransformer()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"---\ndescription: Go\nglobs: \"**/*.go, cmd/**\"\nalwaysApply: false\n---\nUse gofmt.", "---\npaths:\n    - '**/*.go'\n    - cmd/**\n---\n\nUse gofmt."},
+		{"---\napply_to:\n  - web/**/*.tsx\n---\nUse hooks.", "---\npaths:\n    - web/**/*.tsx\n---\n\nUse hooks."},
+		{"---\nalwaysApply: true\nglobs: \"*.md\"\n---\nBe brief.", "---\n{}\n---\n\nBe brief."},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontmatter([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("SplitFrontmatter failed: %v", err)
+		}
+		outFM, outBody, err := transformer.Transform(fm, body)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if err := transformer.Validate(outFM); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		out, err := MarshalMarkdown(outFM, outBody)
+		if err != nil {
+			t.Fatalf("MarshalMarkdown failed: %v", err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("Transform(%q) =\n%s\nwant\n%s", tt.input, out, tt.want)
+		}
+	}
+
+	configured, err := transformer.WithOverrides(Overrides{IncludeRefs: []string{"docs/style.md"}})
+	if err != nil {
+		t.Fatalf("WithOverrides failed: %v", err)
+	}
+	fm, body, _ := SplitFrontmatter([]byte("---\n---\nBody."))
+	if _, outBody, _ := configured.Transform(fm, body); !strings.HasSuffix(outBody, "@docs/style.md") {
+		t.Fatalf("expected @import reference, got %q", outBody)
+	}
+}
+
+func TestWindsurfRulesTransformer(t *testing.T) {
+	transformer := NewWindsurfRulesTransformer()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"---\nalwaysApply: true\nglobs: \"*.md\"\n---\nBe brief.", "---\ntrigger: always_on\n---\n\nBe brief."},
+		{"---\ndescription: Go\nglobs: \"**/*.go, cmd/**\"\n---\nUse gofmt.", "---\nglobs: '**/*.go, cmd/**'\ntrigger: glob\n---\n\nUse gofmt."},
+		{"---\ndescription: Release checklist\n---\nTag first.", "---\ndescription: Release checklist\ntrigger: model_decision\n---\n\nTag first."},
+		{"---\n---\nOnly when asked.", "---\ntrigger: manual\n---\n\nOnly when asked."},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontmatter([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("SplitFrontmatter failed: %v", err)
+		}
+		outFM, outBody, err := transformer.Transform(fm, body)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if err := transformer.Validate(outFM); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		if warning := transformer.Check(outFM, outBody); warning != "" {
+			t.Errorf("unexpected warning for %q: %s", tt.input, warning)
+		}
+		out, err := MarshalMarkdown(outFM, outBody)
+		if err != nil {
+			t.Fatalf("MarshalMarkdown failed: %v", err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("Transform(%q) =\n%s\nwant\n%s", tt.input, out, tt.want)
+		}
+	}
+}
+
+func TestWindsurfRulesTransformerTruncatesToLimit(t *testing.T) {
+	transformer := &WindsurfRulesTransformer{MaxChars: 200, IncludeRefs: []string{"docs/style.md"}}
+	body := "# Style\n\n" + strings.Repeat("Keep functions short. ", 4) + "\n\n## More\n\n" + strings.Repeat("Name things well. ", 20)
+	fm, _, _ := SplitFrontmatter([]byte("---\nalwaysApply: true\n---\n"))
+	outFM, outBody, err := transformer.Transform(fm, body)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	out, _ := MarshalMarkdown(outFM, outBody)
+	if n := utf8.RuneCount(out); n > transformer.MaxChars {
+		t.Fatalf("output is %d characters, want at most %d:\n%s", n, transformer.MaxChars, out)
+	}
+	if strings.Contains(outBody, "## More") || !strings.HasSuffix(outBody, windsurfTruncatedNote+"\n\n- [docs/style.md](docs/style.md)") {
+		t.Fatalf("expected truncation at a block boundary with references kept, got:\n%s", outBody)
+	}
+	if warning := CheckRule(WithTemplate(transformer, &Template{}), outFM, outBody); !strings.Contains(warning, "truncated") {
+		t.Fatalf("expected truncation warning, got %q", warning)
+	}
+}
+
+func TestAgentsMDTransformerComposeDir(t *testing.T) {
+	flat := NewAgentsMDTransformer()
+	nested, err := flat.WithOverrides(Overrides{Nested: true})
+	if err != nil {
+		t.Fatalf("WithOverrides failed: %v", err)
+	}
+	tests := []struct {
+		input      string
+		flat, nest string
+	}{
+		{"---\nglobs: web/src/**/*.tsx, web/src/*.ts\n---\nUse hooks.", "", "web/src"},
+		{"---\napplyTo: \"api/**\"\n---\nVersion routes.", "", "api"},
+		{"---\nglobs: [api/**, web/**]\n---\nShared.", "", ""},
+		{"---\nalwaysApply: true\nglobs: docs/**\n---\nBe brief.", "", ""},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontmatter([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("SplitFrontmatter failed: %v", err)
+		}
+		outFM, outBody, err := nested.Transform(fm, body)
+		if err != nil || nested.Validate(outFM) != nil || outBody != body {
+			t.Fatalf("Transform(%q) = %q, %v", tt.input, outBody, err)
+		}
+		if dir, _ := flat.ComposeDir(fm); dir != tt.flat {
+			t.Errorf("flat ComposeDir(%q) = %q, want %q", tt.input, dir, tt.flat)
+		}
+		if dir, _ := nested.(Composer).ComposeDir(fm); dir != tt.nest {
+			t.Errorf("nested ComposeDir(%q) = %q, want %q", tt.input, dir, tt.nest)
+		}
+	}
+}
+
+func TestClineRulesTransformer(t *testing.T) {
+	transformer := NewClineRulesTransformer()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"---\ndescription: Go\nglobs: \"**/*.go, cmd/**\"\n---\nUse gofmt.", "---\npaths:\n    - '**/*.go'\n    - cmd/**\n---\n\nUse gofmt."},
+		{"---\nalwaysApply: true\nglobs: \"*.md\"\n---\nBe brief.", "Be brief."},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontmatter([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("SplitFrontmatter failed: %v", err)
+		}
+		outFM, outBody, err := transformer.Transform(fm, body)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+		if err := transformer.Validate(outFM); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+		out, err := RenderRule(transformer, outFM, outBody)
+		if err != nil {
+			t.Fatalf("RenderRule failed: %v", err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("Transform(%q) =\n%s\nwant\n%s", tt.input, out, tt.want)
+		}
+	}
+}
+
+func TestRooRulesTransformerRoutesModes(t *testing.T) {
+	transformer := NewRooRulesTransformer()
+	architect, err := transformer.WithOverrides(Overrides{Mode: "architect"})
+	if err != nil {
+		t.Fatalf("WithOverrides failed: %v", err)
+	}
+	tests := []struct {
+		input         string
+		plain, manual string
+	}{
+		{"---\ndescription: Go\nglobs: \"**/*.go\"\n---\nUse gofmt.", "rules", "rules-architect"},
+		{"---\nrooMode: code\n---\nReview diffs.", "rules-code", "rules-code"},
+	}
+	for _, tt := range tests {
+		fm, body, err := SplitFrontmatter([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("SplitFrontmatter failed: %v", err)
+		}
+		if dir, _, err := RuleDir(transformer, fm); err != nil || dir != tt.plain {
+			t.Errorf("RuleDir(%q) = %q, %v, want %q", tt.input, dir, err, tt.plain)
+		}
+		if dir, _, err := RuleDir(WithTemplate(architect, &Template{}), fm); err != nil || dir != tt.manual {
+			t.Errorf("RuleDir(%q) with mode override = %q, %v, want %q", tt.input, dir, err, tt.manual)
+		}
+		outFM, outBody, err := transformer.Transform(fm, body)
+		if err != nil || transformer.Validate(outFM) != nil {
+			t.Fatalf("Transform(%q) failed: %v", tt.input, err)
+		}
+		if out, _ := RenderRule(transformer, outFM, outBody); string(out) != body {
+			t.Errorf("RenderRule(%q) = %q, want the plain body", tt.input, out)
+		}
+	}
+
+	fm, _, _ := SplitFrontmatter([]byte("---\nrooMode: ../code\n---\nBody."))
+	if _, _, err := RuleDir(transformer, fm); err == nil {
+		t.Fatal("expected invalid rooMode to fail")
+	}
+	if _, err := transformer.WithOverrides(Overrides{Mode: "two words"}); err == nil {
+		t.Fatal("expected invalid mode override to fail")
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	input := `---
+description: "Generate component"
+---
+Create a React component.`
+	overrides := Overrides{
+		DefaultMode:  "agent",
+		DefaultTools: []string{"githubRepo"},
+		IncludeRefs:  []string{"docs/architecture.md"},
+	}
+
+	base := NewCopilotPromptsTransformer()
+	configured, err := ApplyOverrides(base, overrides)
+	if err != nil {
+		t.Fatalf("ApplyOverrides failed: %v", err)
+	}
+	fm, body, err := SplitFrontmatter([]byte(input))
+	if err != nil {
+		t.Fatalf("SplitFrontmatter failed: %v", err)
+	}
+	outFM, outBody, err := configured.Transform(fm, body)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	var result map[string]interface{}
+	if err := outFM.Decode(&result); err != nil {
+		t.Fatalf("Decode result failed: %v", err)
+	}
+	if result["mode"] != "agent" {
+		t.Errorf("mode: expected 'agent', got %v", result["mode"])
+	}
+	if tools, ok := result["tools"].([]interface{}); !ok || len(tools) != 1 || tools[0] != "githubRepo" {
+		t.Errorf("tools: expected [githubRepo], got %v", result["tools"])
+	}
+	if !strings.HasSuffix(outBody, "\n\n- [docs/architecture.md](docs/architecture.md)") {
+		t.Errorf("expected reference link appended, got %q", outBody)
+	}
+	if base.DefaultMode != "chat" || len(base.IncludeRefs) != 0 {
+		t.Errorf("ApplyOverrides must not modify the base transformer")
+	}
+
+	cursor, err := ApplyOverrides(NewCursorTransformer(), overrides)
+	if err != nil {
+		t.Fatalf("ApplyOverrides cursor failed: %v", err)
+	}
+	fm, body, _ = SplitFrontmatter([]byte(input))
+	if _, outBody, _ = cursor.Transform(fm, body); !strings.HasSuffix(outBody, "@file docs/architecture.md") {
+		t.Errorf("expected @file reference for cursor, got %q", outBody)
+	}
+
+	if _, err := ApplyOverrides(NewCopilotPromptsTransformer(), Overrides{DefaultMode: "pair"}); err == nil {
+		t.Error("expected error for invalid defaultMode")
+	}
+}
+
+func TestWithTemplate(t *testing.T) {
+	tmpl := &Template{Data: TemplateData{
+		Project: TemplateProject{Name: "webapp", Type: "go", Languages: []string{"go", "typescript"}},
+		Vars:    map[string]string{"goVersion": "1.22"},
+	}}
+	src := "---\ndescription: \"Rules for {{ .Project.Name }}\"\nglobs: [\"**/*.go\"]\ntemplate: true\n---\n" +
+		"Use Go {{ .Vars.goVersion }} for {{ .Project.Languages }}.{{ .Vars.missing }}\n" +
+		"Vue: {{ message }}, inline `{{ .Project.Name }}`\n" +
+		"```tmpl\n{{ . }} {{/* note */}} a {{- \"x\" -}} b {{ .Project.Type }}\n```\n"
+	fm, body, err := SplitFrontmatter([]byte(src))
+	if err != nil {
+		t.Fatalf("SplitFrontmatter: %v", err)
+	}
+
+	transformer := WithTemplate(NewCursorTransformer(), tmpl)
+	unrendered := UnrenderedPlaceholders(transformer, fm, body)
+	if want := []string{"{{ .Vars.missing }}", "{{ message }}"}; !slices.Equal(unrendered, want) {
+		t.Fatalf("UnrenderedPlaceholders = %q, want %q", unrendered, want)
+	}
+	outFM, outBody, err := transformer.Transform(fm, body)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+	out, err := MarshalMarkdown(outFM, outBody)
+	if err != nil {
+		t.Fatalf("MarshalMarkdown: %v", err)
+	}
+	want := "Use Go 1.22 for go, typescript.{{ .Vars.missing }}\n" +
+		"Vue: {{ message }}, inline `{{ .Project.Name }}`\n" +
+		"```tmpl\n{{ . }} {{/* note */}} a {{- \"x\" -}} b {{ .Project.Type }}\n```"
+	if outBody != want || !strings.Contains(string(out), "Rules for webapp") || strings.Contains(string(out), "template:") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	// Rules without `template: true` are left as written.
+	fm, body, _ = SplitFrontmatter([]byte("---\ndescription: \"{{ .Project.Name }}\"\n---\nUse {{ .Project.Name }}\n"))
+	if UnrenderedPlaceholders(transformer, fm, body) != nil {
+		t.Fatal("rules without template: true should not report placeholders")
+	}
+	outFM, outBody, err = transformer.Transform(fm, body)
+	if err != nil || outBody != "Use {{ .Project.Name }}" {
+		t.Fatalf("Transform without opt-in = %q, %v", outBody, err)
+	}
+	if out, _ := MarshalMarkdown(outFM, outBody); !strings.Contains(string(out), "{{ .Project.Name }}") {
+		t.Fatalf("frontmatter should not be rendered without opt-in:\n%s", out)
+	}
+
+	strict := &Template{Data: tmpl.Data, Strict: true}
+	if _, err := strict.Render("body", "{{ .Vars.missing }}"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("strict mode should reject undefined variables, got %v", err)
+	}
+	if _, err := strict.Render("body", "{{ message }}"); err == nil {
+		t.Fatal("strict mode should reject unknown placeholders")
+	}
+	if _, err := strict.Render("body", "```\n{{ message }}\n```\n"); err != nil {
+		t.Fatalf("strict mode should ignore code, got %v", err)
+	}
+	fm, body, _ = SplitFrontmatter([]byte("---\ntemplate: yes please\n---\nbody\n"))
+	if _, _, err := transformer.Transform(fm, body); err == nil {
+		t.Fatal("expected error for non-boolean template field")
+	}
+	if got := WithTemplate(NewCursorTransformer(), nil); got.Target() != "cursor" {
+		t.Fatalf("nil template should return the transformer unchanged")
+	}
+}
@@ -28,3 +28,75 @@ type Result struct {
 	Body        string
 	Warnings    []string
 }
+
+// Composer is implemented by transformers whose rules are composed into one shared file per
+// directory (such as AGENTS.md) instead of being written to a file each.
+type Composer interface {
+	// ComposedFile returns the name of the file rules are composed into.
+	ComposedFile() string
+
+	// ComposeDir returns the slash-separated project directory a rule with the given source
+	// frontmatter is composed into; "" is the project root.
+	ComposeDir(frontmatter *yaml.Node) (string, error)
+}
+
+// Checker is implemented by transformers with limits that depend on the whole transformed rule,
+// such as a maximum file size. Problems are warnings: the rule is still written.
+type Checker interface {
+	// Check returns a warning for the transformed rule, or "" when there is nothing to report.
+	Check(frontmatter *yaml.Node, body string) string
+}
+
+// CheckRule returns t's warning for a transformed rule, or "" when t does not check rules.
+func CheckRule(t Transformer, frontmatter *yaml.Node, body string) string {
+	if c, ok := unwrap(t).(Checker); ok {
+		return c.Check(frontmatter, body)
+	}
+	return ""
+}
+
+// Renderer is implemented by transformers whose files are not a frontmatter block followed by
+// the body, such as targets that read plain markdown.
+type Renderer interface {
+	// Render lays out a transformed rule as file content.
+	Render(frontmatter *yaml.Node, body string) ([]byte, error)
+}
+
+// RenderRule lays out a transformed rule with t's Renderer, or MarshalMarkdown when t has none.
+func RenderRule(t Transformer, frontmatter *yaml.Node, body string) ([]byte, error) {
+	if r, ok := unwrap(t).(Renderer); ok {
+		return r.Render(frontmatter, body)
+	}
+	return MarshalMarkdown(frontmatter, body)
+}
+
+// Router is implemented by transformers that spread rules over directories next to OutputDir,
+// such as Roo Code's mode-specific rules-<mode> directories.
+type Router interface {
+	// RuleDir returns the name of the directory, next to OutputDir, a rule with the given
+	// source frontmatter is written to.
+	RuleDir(frontmatter *yaml.Node) (string, error)
+}
+
+// RuleDir returns the directory t routes a rule with the given source frontmatter to, and false
+// when t writes every rule to OutputDir.
+func RuleDir(t Transformer, frontmatter *yaml.Node) (string, bool, error) {
+	r, ok := unwrap(t).(Router)
+	if !ok {
+		return "", false, nil
+	}
+	dir, err := r.RuleDir(frontmatter)
+	return dir, err == nil, err
+}
+
+// unwrap returns the transformer behind any WithTemplate wrapping, so optional interfaces are
+// found on the transformer that implements them.
+func unwrap(t Transformer) Transformer {
+	for {
+		wrapped, ok := t.(*templateTransformer)
+		if !ok {
+			return t
+		}
+		t = wrapped.Transformer
+	}
+}
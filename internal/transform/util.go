@@ -2,8 +2,10 @@ package transform
 
 import (
 	"bytes"
+	"slices"
 
 	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/glob"
 	"gopkg.in/yaml.v3"
 )
 
@@ -45,3 +47,97 @@ func MarshalMarkdown(frontmatter *yaml.Node, body string) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+// globPatterns returns the patterns of a frontmatter glob field, given as a comma-separated
+// string or a list.
+func globPatterns(value interface{}) []string {
+	var patterns []string
+	switch v := value.(type) {
+	case string:
+		patterns = glob.SplitList(v)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				patterns = append(patterns, glob.SplitList(s)...)
+			}
+		}
+	case []string:
+		for _, s := range v {
+			patterns = append(patterns, glob.SplitList(s)...)
+		}
+	}
+	return patterns
+}
+
+// validateGlobField checks every pattern of a frontmatter glob field, given as a comma-separated
+// string or a list. A missing field is valid.
+func validateGlobField(field string, value interface{}) error {
+	for _, p := range globPatterns(value) {
+		if err := glob.Validate(p); err != nil {
+			return errors.Wrapf(err, errors.CodeInvalidArgument, "invalid %s pattern %q", field, p)
+		}
+	}
+	return nil
+}
+
+// globsDir returns the deepest directory shared by every pattern of a rule's globs (or
+// apply_to/applyTo), or "" when the rule always applies, has no globs or spans the project.
+func globsDir(fm map[string]interface{}) string {
+	if alwaysApply, _ := fm["alwaysApply"].(bool); alwaysApply {
+		return ""
+	}
+	var patterns []string
+	for _, key := range []string{"globs", "apply_to", "applyTo"} {
+		if patterns = globPatterns(fm[key]); len(patterns) > 0 {
+			break
+		}
+	}
+	dir := ""
+	for i, pattern := range patterns {
+		p, err := glob.Compile(pattern)
+		if err != nil || p.Negated() {
+			return ""
+		}
+		if i == 0 {
+			dir = p.Dir()
+		} else if p.Dir() != dir {
+			return ""
+		}
+	}
+	return dir
+}
+
+// marshalOptionalFrontmatter writes body alone when frontmatter has no fields, for targets
+// that read plain markdown, and a MarshalMarkdown file otherwise.
+func marshalOptionalFrontmatter(frontmatter *yaml.Node, body string) ([]byte, error) {
+	if frontmatter == nil || (frontmatter.Kind == yaml.MappingNode && len(frontmatter.Content) == 0) {
+		return []byte(body), nil
+	}
+	return MarshalMarkdown(frontmatter, body)
+}
+
+// mappingNode returns the mapping of a parsed frontmatter document, or nil.
+func mappingNode(n *yaml.Node) *yaml.Node {
+	if n != nil && n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	return n
+}
+
+// dropFields removes fields from frontmatter in place, keeping the order and style of the rest.
+func dropFields(frontmatter *yaml.Node, fields ...string) {
+	m := mappingNode(frontmatter)
+	if m == nil {
+		return
+	}
+	content := m.Content[:0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if !slices.Contains(fields, m.Content[i].Value) {
+			content = append(content, m.Content[i], m.Content[i+1])
+		}
+	}
+	m.Content = content
+}
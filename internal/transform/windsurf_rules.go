@@ -0,0 +1,180 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZanzyTHEbar/cursor-rules/internal/errors"
+	"github.com/ZanzyTHEbar/cursor-rules/internal/tokens"
+	"gopkg.in/yaml.v3"
+)
+
+// WindsurfMaxChars is the size Windsurf allows each rule file.
+const WindsurfMaxChars = 12000
+
+// windsurfTruncatedNote ends rules cut down to fit MaxChars.
+const windsurfTruncatedNote = "[... truncated for Windsurf's character limit ...]"
+
+var windsurfTriggers = []string{"always_on", "glob", "model_decision", "manual"}
+
+// WindsurfRulesTransformer transforms Cursor rules into Windsurf workspace rules
+// (`.windsurf/rules/*.md`).
+type WindsurfRulesTransformer struct {
+	// IncludeRefs are appended to the body as markdown links.
+	IncludeRefs []string
+	// MaxChars caps the size of each rule file; zero or less disables the limit.
+	MaxChars int
+}
+
+// NewWindsurfRulesTransformer creates a transformer for Windsurf rule files.
+func NewWindsurfRulesTransformer() *WindsurfRulesTransformer {
+	return &WindsurfRulesTransformer{MaxChars: WindsurfMaxChars}
+}
+
+// Transform maps Cursor's rule types to Windsurf triggers: alwaysApply becomes always_on, globs
+// (or apply_to/applyTo) glob, a description alone model_decision, and anything else manual.
+// A rule that already names a Windsurf trigger keeps it. Bodies that would push the file past
+// MaxChars are truncated between markdown blocks, keeping references intact.
+func (t *WindsurfRulesTransformer) Transform(node *yaml.Node, body string) (*yaml.Node, string, error) {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "decode frontmatter")
+	}
+
+	var globs []string
+	for _, key := range []string{"globs", "apply_to", "applyTo"} {
+		if globs = globPatterns(fm[key]); len(globs) > 0 {
+			break
+		}
+	}
+	description := strings.TrimSpace(toString(fm["description"]))
+	alwaysApply, _ := fm["alwaysApply"].(bool)
+
+	trigger := strings.TrimSpace(toString(fm["trigger"]))
+	if trigger == "" {
+		switch {
+		case alwaysApply:
+			trigger = "always_on"
+		case len(globs) > 0:
+			trigger = "glob"
+		case description != "":
+			trigger = "model_decision"
+		default:
+			trigger = "manual"
+		}
+	}
+
+	result := map[string]interface{}{"trigger": trigger}
+	switch trigger {
+	case "glob":
+		result["globs"] = strings.Join(globs, ", ")
+	case "model_decision":
+		result["description"] = description
+	}
+
+	out := &yaml.Node{}
+	if err := out.Encode(result); err != nil {
+		return nil, "", errors.Wrapf(err, errors.CodeInternal, "encode frontmatter")
+	}
+	header, err := MarshalMarkdown(out, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return out, t.fitBody(utf8.RuneCount(header), body), nil
+}
+
+// fitBody appends references and truncates body so that, after a header of headerLen
+// characters, the file stays within MaxChars.
+func (t *WindsurfRulesTransformer) fitBody(headerLen int, body string) string {
+	full := appendReferences(body, t.IncludeRefs, markdownReference)
+	if t.MaxChars <= 0 || headerLen+utf8.RuneCountInString(full) <= t.MaxChars {
+		return full
+	}
+	tail := appendReferences(windsurfTruncatedNote, t.IncludeRefs, markdownReference)
+	budget := t.MaxChars - headerLen - utf8.RuneCountInString(tail) - len("\n\n")
+	if budget <= 0 {
+		return tail
+	}
+	truncated, _ := tokens.Truncate(body, budget, charCounter{})
+	if strings.TrimSpace(truncated) == "" {
+		return tail
+	}
+	return truncated + "\n\n" + tail
+}
+
+// Check warns when a rule was truncated to fit, or still exceeds, Windsurf's size limit.
+func (t *WindsurfRulesTransformer) Check(node *yaml.Node, body string) string {
+	if t.MaxChars <= 0 {
+		return ""
+	}
+	out, err := MarshalMarkdown(node, body)
+	if err != nil {
+		return ""
+	}
+	if n := utf8.RuneCount(out); n > t.MaxChars {
+		return fmt.Sprintf("rule is %d characters, over Windsurf's %d-character limit", n, t.MaxChars)
+	}
+	if strings.Contains(body, windsurfTruncatedNote) {
+		return fmt.Sprintf("rule truncated to fit Windsurf's %d-character limit", t.MaxChars)
+	}
+	return ""
+}
+
+// WithOverrides returns a copy that references IncludeRefs.
+func (t *WindsurfRulesTransformer) WithOverrides(o Overrides) (Transformer, error) {
+	out := *t
+	out.IncludeRefs = append([]string(nil), o.IncludeRefs...)
+	return &out, nil
+}
+
+// Validate checks the trigger and the field it requires.
+func (t *WindsurfRulesTransformer) Validate(node *yaml.Node) error {
+	var fm map[string]interface{}
+	if err := node.Decode(&fm); err != nil {
+		return err
+	}
+	for key := range fm {
+		switch key {
+		case "trigger", "globs", "description":
+		default:
+			return errors.Newf(errors.CodeInvalidArgument, "unsupported Windsurf rule field: %s", key)
+		}
+	}
+	trigger := toString(fm["trigger"])
+	switch trigger {
+	case "glob":
+		if len(globPatterns(fm["globs"])) == 0 {
+			return errors.New(errors.CodeInvalidArgument, "glob trigger requires globs")
+		}
+	case "model_decision":
+		if strings.TrimSpace(toString(fm["description"])) == "" {
+			return errors.New(errors.CodeInvalidArgument, "model_decision trigger requires a description")
+		}
+	case "always_on", "manual":
+	default:
+		return errors.Newf(errors.CodeInvalidArgument, "invalid trigger: %q (must be %s)", trigger, strings.Join(windsurfTriggers, ", "))
+	}
+	return validateGlobField("globs", fm["globs"])
+}
+
+// Target returns the identifier for Windsurf rules format.
+func (t *WindsurfRulesTransformer) Target() string {
+	return "windsurf"
+}
+
+// Extension returns the file extension for Windsurf rule files.
+func (t *WindsurfRulesTransformer) Extension() string {
+	return ".md"
+}
+
+// OutputDir returns the project-local output directory for Windsurf rules.
+func (t *WindsurfRulesTransformer) OutputDir() string {
+	return ".windsurf/rules"
+}
+
+// charCounter counts characters, so tokens.Truncate can cut to a character budget.
+type charCounter struct{}
+
+func (charCounter) Name() string          { return "runes" }
+func (charCounter) Count(text string) int { return utf8.RuneCountInString(text) }